@@ -0,0 +1,45 @@
+//go:build !race
+// +build !race
+
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// TestAllocations guards the allocation counts of the hot paths, so that new
+// features don't silently make them more expensive.
+func TestAllocations(t *testing.T) {
+	chain := deepChain(10)
+	var err error
+	tests := []struct {
+		name string
+		max  float64
+		f    func()
+	}{
+		{"New", 4, func() { err = New("error") }},
+		{"Errorf", 5, func() { err = Errorf("error %d", 1) }},
+		{"Errorlazy", 5, func() { err = Errorlazy("error %d", 1) }},
+		{"WithStack", 3, func() { err = WithStack(io.EOF) }},
+		{"Wrap", 3, func() { err = Wrap(io.EOF, "wrap") }},
+		{"Wrapf", 4, func() { err = Wrapf(io.EOF, "wrap %d", 1) }},
+		{"Wraplazy", 5, func() { err = Wraplazy(io.EOF, "wrap %d", 1) }},
+		{"Error", 0, func() { _ = chain.Error() }},
+		{"Sprintf %s", 1, func() { _ = fmt.Sprintf("%s", chain) }},
+		{"Sprintf %+v", 12, func() { _ = fmt.Sprintf("%+v", chain) }},
+		{"Is", 0, func() { _ = goerrors.Is(chain, io.EOF) }},
+		{"As", 1, func() {
+			var target *countingError
+			_ = goerrors.As(chain, &target)
+		}},
+	}
+	for _, tt := range tests {
+		if got := testing.AllocsPerRun(100, tt.f); got > tt.max {
+			t.Errorf("%s: got %v allocations, want at most %v", tt.name, got, tt.max)
+		}
+	}
+	GlobalE = err
+}
@@ -0,0 +1,119 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// withAttr annotates an error with a named value, without changing its
+// message. The name is the key of the value in the representation built by
+// ToMap.
+type withAttr struct {
+	error
+	name  string
+	value interface{}
+}
+
+// Unwrap returns the annotated error
+func (w *withAttr) Unwrap() error { return w.error }
+
+// Cause is the same as Unwrap
+func (w *withAttr) Cause() error { return w.error }
+
+// Is reports whether target is the Kind or Code err is annotated with.
+// KindUnknown and the empty Code match no error. Is of the package only
+// matches the outermost kind and code of a chain, see KindOf.
+func (w *withAttr) Is(target error) bool {
+	switch t := target.(type) {
+	case Kind:
+		return t != KindUnknown && w.name == "kind" && w.value == target
+	case Code:
+		return t != "" && w.name == "code" && w.value == target
+	}
+	return false
+}
+
+// Format formats the annotated error
+func (w *withAttr) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if pkgErrorsFormat() {
+				_, _ = fmt.Fprintf(s, "%+v", w.error)
+				return
+			}
+			if formatIndented(s, w) {
+				return
+			}
+			formatChain(s, w)
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, w.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", w.Error())
+	}
+}
+
+// levelAttr returns the value named name of err itself, without walking its
+// chain. The kind, code, reference ID and fingerprint of decoded errors are
+// attributes too.
+func levelAttr(err error, name string) (interface{}, bool) {
+	switch e := err.(type) {
+	case *withAttr:
+		if e.name == name {
+			return e.value, true
+		}
+	case *RemoteError:
+		if name == "kind" && e.Kind != KindUnknown {
+			return e.Kind, true
+		}
+		if name == "code" && e.Code != "" {
+			return e.Code, true
+		}
+		if name == "ref_id" && e.RefID != "" {
+			return e.RefID, true
+		}
+		if name == "fingerprint" && e.Fingerprint != "" {
+			return e.Fingerprint, true
+		}
+	}
+	return nil, false
+}
+
+// lookupAttr returns the value named name of the outermost error of the chain
+// of err annotated with one. The kind, code, reference ID and fingerprint of
+// decoded errors are attributes too.
+func lookupAttr(err error, name string) (interface{}, bool) {
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		if v, ok := levelAttr(err, name); ok {
+			return v, true
+		}
+		causes := causes(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return nil, false
+}
+
+// lookupAttrs returns the values named name of the errors of the chain of err
+// annotated with one, from the outermost to the innermost.
+func lookupAttrs(err error, name string) []interface{} {
+	var values []interface{}
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		if w, ok := err.(*withAttr); ok && w.name == name {
+			values = append(values, w.value)
+		}
+		causes := causes(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return values
+}
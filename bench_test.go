@@ -107,3 +107,141 @@ func BenchmarkStackFormatting(b *testing.B) {
 	}
 	GlobalE = stackStr
 }
+
+func BenchmarkStackPooling(b *testing.B) {
+	for _, pooling := range []bool{false, true} {
+		b.Run(fmt.Sprintf("pooling-%t", pooling), func(b *testing.B) {
+			SetStackPooling(pooling)
+			defer SetStackPooling(false)
+			var err error
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err = yesErrors(0, 10)
+			}
+			b.StopTimer()
+			GlobalE = err
+		})
+	}
+}
+
+// deepChain returns io.EOF wrapped depth times, alternating constructors.
+func deepChain(depth int) error {
+	err := error(New("root"))
+	for i := 0; i < depth; i++ {
+		switch i % 3 {
+		case 0:
+			err = Wrap(err, "wrap")
+		case 1:
+			err = Wrapf(err, "wrapf %d", i)
+		default:
+			err = WithStack(err)
+		}
+	}
+	return err
+}
+
+func BenchmarkConstructors(b *testing.B) {
+	cause := goerrors.New("cause")
+	constructors := []struct {
+		name string
+		f    func() error
+	}{
+		{"New", func() error { return New("error") }},
+		{"Errorf", func() error { return Errorf("error %d", 1) }},
+		{"Errorlazy", func() error { return Errorlazy("error %d", 1) }},
+		{"WithStack", func() error { return WithStack(cause) }},
+		{"Wrap", func() error { return Wrap(cause, "wrap") }},
+		{"Wrapf", func() error { return Wrapf(cause, "wrap %d", 1) }},
+		{"Wraplazy", func() error { return Wraplazy(cause, "wrap %d", 1) }},
+	}
+	for _, c := range constructors {
+		b.Run(c.name, func(b *testing.B) {
+			var err error
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err = c.f()
+			}
+			b.StopTimer()
+			GlobalE = err
+		})
+	}
+}
+
+func BenchmarkChainFormatting(b *testing.B) {
+	var str string
+	for _, depth := range []int{1, 10} {
+		for _, format := range []string{"%s", "%v", "%+v"} {
+			b.Run(fmt.Sprintf("%s-depth-%d", format, depth), func(b *testing.B) {
+				err := deepChain(depth)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					str = fmt.Sprintf(format, err)
+				}
+			})
+		}
+	}
+	GlobalE = str
+}
+
+func BenchmarkAnnotatedChainFormatting(b *testing.B) {
+	err := deepChain(10)
+	for i := 0; i < 10; i++ {
+		err = WithField(Wrap(err, "wrap"), "attempt", i)
+	}
+	var str string
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		str = fmt.Sprintf("%+v", err)
+	}
+	GlobalE = str
+}
+
+func BenchmarkIsAs(b *testing.B) {
+	for _, depth := range []int{1, 10, 50} {
+		err := Wrap(deepChain(depth), "outer")
+		target := goerrors.New("not in chain")
+		b.Run(fmt.Sprintf("Is-depth-%d", depth), func(b *testing.B) {
+			var ok bool
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ok = goerrors.Is(err, target)
+			}
+			GlobalE = ok
+		})
+		b.Run(fmt.Sprintf("As-depth-%d", depth), func(b *testing.B) {
+			var ok bool
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var target *countingError
+				ok = goerrors.As(err, &target)
+			}
+			GlobalE = ok
+		})
+	}
+}
+
+func BenchmarkIsAsCached(b *testing.B) {
+	for _, depth := range []int{1, 10, 50} {
+		err := Wrap(deepChain(depth), "outer")
+		target := goerrors.New("not in chain")
+		b.Run(fmt.Sprintf("Is-depth-%d", depth), func(b *testing.B) {
+			var ok bool
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ok = Is(err, target)
+			}
+			GlobalE = ok
+		})
+		b.Run(fmt.Sprintf("As-depth-%d", depth), func(b *testing.B) {
+			var ok bool
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var target *countingError
+				ok = As(err, &target)
+			}
+			GlobalE = ok
+		})
+	}
+}
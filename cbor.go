@@ -0,0 +1,270 @@
+package errors
+
+import (
+	"encoding/binary"
+	goerrors "errors"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// maxCBORDepth bounds the nesting of decoded CBOR documents.
+const maxCBORDepth = 64
+
+// CBOR major types.
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborSimple = 7
+)
+
+// ToCBOR encodes the representation of err built by ToMap as CBOR
+// (RFC 8949), for constrained transports. Frames are compacted to the
+// package and function names and the base name of their source file, such
+// as "errors.TestToCBOR cbor_test.go:12".
+// If err is nil, ToCBOR returns nil.
+func ToCBOR(err error) []byte {
+	if err == nil {
+		return nil
+	}
+	m := toMap(acyclic(err), func(f Frame, _ modeSettings) string {
+		return path.Base(f.name()) + " " + path.Base(f.file()) + ":" + strconv.Itoa(f.line())
+	})
+	return appendCBOR(nil, boundSize(addHops(m, err)))
+}
+
+// FromCBOR decodes an error encoded by ToCBOR.
+func FromCBOR(data []byte) (*RemoteError, error) {
+	d := cborDecoder{data: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.off != len(d.data) {
+		return nil, goerrors.New("errors: trailing CBOR data")
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, goerrors.New("errors: CBOR document is not a map")
+	}
+	return FromMap(m)
+}
+
+func appendCBORHead(b []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(b, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return append(b, major|25, byte(n>>8), byte(n))
+	case n <= math.MaxUint32:
+		return append(b, major|26, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return appendUint64(append(b, major|27), n)
+}
+
+func appendCBOR(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, cborSimple<<5|22)
+	case bool:
+		if v {
+			return append(b, cborSimple<<5|21)
+		}
+		return append(b, cborSimple<<5|20)
+	case int:
+		return appendCBORInt(b, int64(v))
+	case int64:
+		return appendCBORInt(b, v)
+	case uint64:
+		return appendCBORHead(b, cborUint, v)
+	case float64:
+		b = append(b, cborSimple<<5|27)
+		return appendUint64(b, math.Float64bits(v))
+	case string:
+		b = appendCBORHead(b, cborText, uint64(len(v)))
+		return append(b, v...)
+	case []byte:
+		b = appendCBORHead(b, cborBytes, uint64(len(v)))
+		return append(b, v...)
+	case []string:
+		b = appendCBORHead(b, cborArray, uint64(len(v)))
+		for _, s := range v {
+			b = appendCBOR(b, s)
+		}
+		return b
+	case []interface{}:
+		b = appendCBORHead(b, cborArray, uint64(len(v)))
+		for _, item := range v {
+			b = appendCBOR(b, item)
+		}
+		return b
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = appendCBORHead(b, cborMap, uint64(len(v)))
+		for _, k := range keys {
+			b = appendCBOR(b, k)
+			b = appendCBOR(b, v[k])
+		}
+		return b
+	}
+	return appendCBOR(b, fmt.Sprint(v))
+}
+
+func appendCBORInt(b []byte, n int64) []byte {
+	if n < 0 {
+		return appendCBORHead(b, cborNegInt, uint64(-(n + 1)))
+	}
+	return appendCBORHead(b, cborUint, uint64(n))
+}
+
+func appendUint64(b []byte, n uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], n)
+	return append(b, buf[:]...)
+}
+
+// cborDecoder decodes the subset of CBOR produced by ToCBOR: definite
+// lengths, integers, floats, strings, arrays and maps with text keys.
+type cborDecoder struct {
+	data []byte
+	off  int
+}
+
+var errCBORTruncated = goerrors.New("errors: truncated CBOR data")
+
+func (d *cborDecoder) head() (major byte, info byte, n uint64, err error) {
+	if d.off >= len(d.data) {
+		return 0, 0, 0, errCBORTruncated
+	}
+	major, info = d.data[d.off]>>5, d.data[d.off]&0x1f
+	d.off++
+	size := 0
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, 0, 0, goerrors.New("errors: unsupported CBOR length encoding")
+	}
+	if len(d.data)-d.off < size {
+		return 0, 0, 0, errCBORTruncated
+	}
+	for _, c := range d.data[d.off : d.off+size] {
+		n = n<<8 | uint64(c)
+	}
+	d.off += size
+	return major, info, n, nil
+}
+
+func (d *cborDecoder) bytes(n uint64) ([]byte, error) {
+	if uint64(len(d.data)-d.off) < n {
+		return nil, errCBORTruncated
+	}
+	b := d.data[d.off : d.off+int(n)]
+	d.off += int(n)
+	return b, nil
+}
+
+func (d *cborDecoder) value(depth int) (interface{}, error) {
+	if depth > maxCBORDepth {
+		return nil, goerrors.New("errors: CBOR data nested too deeply")
+	}
+	major, info, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborUint:
+		if n > math.MaxInt64 {
+			return n, nil
+		}
+		return int64(n), nil
+	case cborNegInt:
+		if n > math.MaxInt64 {
+			return nil, goerrors.New("errors: CBOR integer overflow")
+		}
+		return -int64(n) - 1, nil
+	case cborBytes:
+		b, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+	case cborText:
+		b, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		if !utf8.Valid(b) {
+			return nil, goerrors.New("errors: invalid UTF-8 in CBOR text")
+		}
+		return string(b), nil
+	case cborArray:
+		if n > uint64(len(d.data)-d.off) {
+			return nil, errCBORTruncated
+		}
+		list := make([]interface{}, 0, n)
+		for i := uint64(0); i < n; i++ {
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case cborMap:
+		if n > uint64(len(d.data)-d.off)/2 {
+			return nil, errCBORTruncated
+		}
+		m := make(map[string]interface{}, n)
+		for i := uint64(0); i < n; i++ {
+			k, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, goerrors.New("errors: CBOR map key is not a string")
+			}
+			if m[key], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case cborSimple:
+		switch info {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		case 26:
+			return float64(math.Float32frombits(uint32(n))), nil
+		case 27:
+			return math.Float64frombits(n), nil
+		}
+	}
+	return nil, goerrors.New("errors: unsupported CBOR data")
+}
@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestToCBOR(t *testing.T) {
+	if got := ToCBOR(nil); got != nil {
+		t.Errorf("ToCBOR(nil): got %x, want nil", got)
+	}
+	want := append(append([]byte{0xa1, 0x67}, "message"...), append([]byte{0x63}, "EOF"...)...)
+	if got := ToCBOR(io.EOF); !bytes.Equal(got, want) {
+		t.Errorf("ToCBOR(io.EOF): got %x, want %x", got, want)
+	}
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	for _, err := range []error{
+		io.EOF,
+		New("error"),
+		Wrap(fmt.Errorf("decode: %w", New("bad payload")), "uplink"),
+		Errorf("%w, %w", Wrap(io.EOF, "read"), io.ErrUnexpectedEOF),
+	} {
+		data := ToCBOR(err)
+		decoded, decodeErr := FromCBOR(data)
+		if decodeErr != nil {
+			t.Fatalf("FromCBOR(ToCBOR(%q)): %v", err, decodeErr)
+		}
+		if decoded.Error() != err.Error() {
+			t.Errorf("FromCBOR(ToCBOR(%q)): got message %q", err, decoded)
+		}
+		if got := Cause(decoded).Error(); got != Cause(err).Error() {
+			t.Errorf("FromCBOR(ToCBOR(%q)): got cause %q, want %q", err, got, Cause(err))
+		}
+		if again := ToCBOR(decoded); !bytes.Equal(again, data) {
+			t.Errorf("ToCBOR(FromCBOR(ToCBOR(%q))) differs:\n got %x\nwant %x", err, again, data)
+		}
+	}
+}
+
+func TestCBORFrames(t *testing.T) {
+	decoded, err := FromCBOR(ToCBOR(New("error")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stack := decoded.Stack
+	if len(stack) == 0 || !regexp.MustCompile(`^errors\.TestCBORFrames cbor_test.go:\d+$`).MatchString(stack[0]) {
+		t.Errorf("decoded stack: got %q, want compacted frames", stack)
+	}
+}
+
+func TestFromCBORInvalid(t *testing.T) {
+	valid := ToCBOR(Wrap(io.EOF, "read"))
+	tests := [][]byte{
+		nil,
+		valid[:len(valid)-1],
+		append(append([]byte{}, valid...), 0),
+		{0x63, 'E', 'O', 'F'},
+		{0xa1, 0x01, 0x01},
+		{0xa1, 0x67, 'm', 'e', 's', 's', 'a', 'g', 'e', 0x01},
+		{0xbf},
+		{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		{0x62, 0xff, 0xfe},
+		bytes.Repeat([]byte{0x81}, maxCBORDepth+2),
+	}
+	for i, data := range tests {
+		if _, err := FromCBOR(data); err == nil {
+			t.Errorf("test %d: FromCBOR(%x): expected an error", i+1, data)
+		}
+	}
+}
+
+func TestCBORValues(t *testing.T) {
+	values := map[string]interface{}{
+		"int":    int64(-1000),
+		"uint":   int64(70000),
+		"float":  1.5,
+		"bool":   true,
+		"null":   nil,
+		"list":   []interface{}{"a", int64(1)},
+		"binary": []byte{1, 2},
+	}
+	d := cborDecoder{data: appendCBOR(nil, values)}
+	got, err := d.value(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(values) {
+		t.Errorf("decoded values: got %v, want %v", got, values)
+	}
+	if _, ok := got.(map[string]interface{})["binary"].([]byte); !ok {
+		t.Errorf("binary value decoded as %T", got.(map[string]interface{})["binary"])
+	}
+}
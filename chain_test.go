@@ -0,0 +1,138 @@
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type chainRoot struct{}
+
+func (*chainRoot) Error() string { return "root" }
+
+// The operations used to build chains mixing this package's wrappers and
+// fmt.Errorf.
+const (
+	opWrap byte = iota
+	opWrapf
+	opWraplazy
+	opWithStack
+	opFmtErrorf
+	opErrorf
+	numOps
+)
+
+// buildChain wraps root with each operation in turn. It returns the
+// resulting error, the lines expected in its %+v output, once stack traces are
+// removed, and the number of stack traces it should print.
+func buildChain(root error, ops []byte) (err error, lines []string, stacks int) {
+	err = root
+	lines = []string{root.Error()}
+	hasStack := false
+	for i, op := range ops {
+		msg := fmt.Sprintf("m%d", i)
+		switch op % numOps {
+		case opWrap:
+			err = Wrap(err, msg)
+		case opWrapf:
+			err = Wrapf(err, "%s", msg)
+		case opWraplazy:
+			err = Wraplazy(err, "%s", msg)
+		case opWithStack:
+			err = WithStack(err)
+		case opFmtErrorf:
+			err = fmt.Errorf("%s: %w", msg, err)
+		case opErrorf:
+			err = Errorf("%s: %w", msg, err)
+		}
+
+		switch op % numOps {
+		case opWithStack:
+		case opFmtErrorf, opErrorf:
+			if !hasStack {
+				// the cause has no stack trace, it is printed as one line.
+				lines = []string{err.Error()}
+				break
+			}
+			lines = append(lines, msg)
+		default:
+			lines = append(lines, msg)
+		}
+		if op%numOps != opFmtErrorf {
+			hasStack = true
+			stacks++
+		}
+	}
+	if len(ops) > 0 && ops[len(ops)-1]%numOps == opFmtErrorf {
+		// fmt.Errorf doesn't print the stack traces of its causes.
+		return err, []string{err.Error()}, 0
+	}
+	return err, lines, stacks
+}
+
+// checkChain verifies that the chain built from ops behaves like a chain
+// built with the standard library only, and that its stack traces are
+// printed.
+func checkChain(t *testing.T, ops []byte) {
+	t.Helper()
+	root := &chainRoot{}
+	err, wantLines, wantStacks := buildChain(root, ops)
+
+	if !goerrors.Is(err, root) {
+		t.Errorf("%v: errors.Is(err, root) = false", ops)
+	}
+	var target *chainRoot
+	if !goerrors.As(err, &target) || target != root {
+		t.Errorf("%v: errors.As(err, &target) = false", ops)
+	}
+	if got := Cause(err); got != root {
+		t.Errorf("%v: Cause(err): got %#v, want root", ops, got)
+	}
+	last := err
+	for unw := goerrors.Unwrap(last); unw != nil; unw = goerrors.Unwrap(last) {
+		last = unw
+	}
+	if last != root {
+		t.Errorf("%v: unwrapped error: got %#v, want root", ops, last)
+	}
+
+	var gotLines []string
+	gotStacks := 0
+	lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+	for i, l := range lines {
+		switch {
+		case strings.HasPrefix(l, "\t"):
+		case i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t"):
+			if l == "github.com/objenious/errors.buildChain" {
+				gotStacks++
+			}
+		default:
+			gotLines = append(gotLines, l)
+		}
+	}
+	if strings.Join(gotLines, "\n") != strings.Join(wantLines, "\n") {
+		t.Errorf("%v: %%+v messages:\n got: %q\nwant: %q", ops, gotLines, wantLines)
+	}
+	if gotStacks != wantStacks {
+		t.Errorf("%v: %%+v printed %d stack traces, want %d", ops, gotStacks, wantStacks)
+	}
+}
+
+func TestMixedChains(t *testing.T) {
+	var ops [][]byte
+	ops = append(ops, nil)
+	for depth := 1; depth <= 4; depth++ {
+		for _, prev := range ops {
+			if len(prev) != depth-1 {
+				continue
+			}
+			for op := byte(0); op < numOps; op++ {
+				ops = append(ops, append(append([]byte{}, prev...), op))
+			}
+		}
+	}
+	for _, o := range ops {
+		checkChain(t, o)
+	}
+}
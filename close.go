@@ -0,0 +1,40 @@
+package errors
+
+import "io"
+
+// DeferClose closes c and reports its failure in *errp, to be deferred by
+// functions returning an error in errp, so that the errors of Close are not
+// lost:
+//
+//	func writeConfig(path string, cfg []byte) (err error) {
+//		f, err := os.Create(path)
+//		if err != nil {
+//			return errors.Wrap(err, "create config")
+//		}
+//		defer errors.DeferClose(&err, f, "close config")
+//		_, err = f.Write(cfg)
+//		return errors.Wrap(err, "write config")
+//	}
+//
+// The error of Close is annotated with msg and the stack trace of the
+// function returning. If *errp is nil, it becomes the error of Close;
+// otherwise, the error of Close is joined after *errp, that remains the
+// first cause of the result (see Join).
+func DeferClose(errp *error, c io.Closer, msg string) {
+	err := c.Close()
+	if err == nil {
+		return
+	}
+	err = &withStack{
+		error:   err,
+		stack:   callers(),
+		created: timestamp(),
+		msg:     msg,
+		wrapped: true,
+	}
+	if *errp == nil {
+		*errp = err
+	} else {
+		*errp = Join(*errp, err)
+	}
+}
@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+type closer struct {
+	err    error
+	closed bool
+}
+
+func (c *closer) Close() error {
+	c.closed = true
+	return c.err
+}
+
+func closeAfter(c io.Closer, err error) (result error) {
+	defer DeferClose(&result, c, "close file")
+	return err
+}
+
+func TestDeferClose(t *testing.T) {
+	c := &closer{}
+	if err := closeAfter(c, nil); err != nil || !c.closed {
+		t.Errorf("DeferClose() = %v, closed %t, want nil, closed", err, c.closed)
+	}
+	if err := closeAfter(&closer{}, io.ErrUnexpectedEOF); err != io.ErrUnexpectedEOF {
+		t.Errorf("DeferClose() = %v, want the returned error", err)
+	}
+
+	err := closeAfter(&closer{err: io.ErrClosedPipe}, nil)
+	if got, want := err.Error(), "close file: io: read/write on closed pipe"; got != want {
+		t.Errorf("DeferClose().Error() = %q, want %q", got, want)
+	}
+	if got := fmt.Sprintf("%n", GetStackTrace(err).StackTrace()[0]); got != "closeAfter" {
+		t.Errorf("DeferClose() stack starts in %s, want closeAfter", got)
+	}
+
+	err = closeAfter(&closer{err: io.ErrClosedPipe}, io.ErrUnexpectedEOF)
+	if !Is(err, io.ErrUnexpectedEOF) || !Is(err, io.ErrClosedPipe) {
+		t.Errorf("DeferClose() = %v, want both errors", err)
+	}
+	if cause := causes(err); len(cause) != 2 || cause[0] != io.ErrUnexpectedEOF {
+		t.Errorf("causes(DeferClose()) = %v, want the returned error first", cause)
+	}
+}
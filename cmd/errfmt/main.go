@@ -0,0 +1,64 @@
+// Command errfmt reads log streams and re-renders the errors formatted with
+// %+v by github.com/objenious/errors they contain, colorized, collapsed or as
+// JSON, leaving the other lines as they are:
+//
+//	kubectl logs api | errfmt -collapse
+//
+// A block of %+v output is recognised by its stack traces, each frame being
+// a function name followed by a line with a tab and the file and line. Up to
+// two lines of messages between stack traces belong to the block, and the
+// block begins at the line preceding its first stack trace, usually that of
+// the log entry.
+//
+// With -json, errfmt writes each block as a line of JSON,
+//
+//	{"errors":[{"message":"load","stack":[{"func":"main.main","file":"/src/main.go","line":14}]}]}
+//
+// and drops the other lines.
+//
+// Usage:
+//
+//	errfmt [-color=false] [-collapse] [-json] [file ...]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	var opts options
+	flag.BoolVar(&opts.color, "color", true, "colorize messages and frames")
+	flag.BoolVar(&opts.collapse, "collapse", false, "print the first frame of each stack trace only")
+	flag.BoolVar(&opts.json, "json", false, "write the blocks as JSON, one per line, and drop the other lines")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: errfmt [-color=false] [-collapse] [-json] [file ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		if err := format(os.Stdout, os.Stdin, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "errfmt: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	for _, name := range flag.Args() {
+		if err := formatFile(os.Stdout, name, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "errfmt: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// formatFile formats the file name to w.
+func formatFile(w io.Writer, name string, opts options) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return format(w, f, opts)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/objenious/errors/internal/blocks"
+)
+
+// ANSI escape sequences of the colors of the output.
+const (
+	colorMessage = "\x1b[1;31m"
+	colorFunc    = "\x1b[36m"
+	colorFile    = "\x1b[2m"
+	colorReset   = "\x1b[0m"
+)
+
+// options are the rendering options of the command line.
+type options struct {
+	color    bool
+	collapse bool
+	json     bool
+}
+
+// format copies r to w, re-rendering the blocks it contains with opts.
+func format(w io.Writer, r io.Reader, opts options) error {
+	bw := bufio.NewWriter(w)
+	text := func(line string) error {
+		if opts.json {
+			return nil
+		}
+		_, err := fmt.Fprintln(bw, line)
+		return err
+	}
+	emit := func(b *blocks.Block) error {
+		if err := render(bw, b, opts); err != nil {
+			return err
+		}
+		// Blocks are flushed as they end, so that streams are followed.
+		return bw.Flush()
+	}
+	if err := blocks.Parse(r, text, emit); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// render writes b to w with opts.
+func render(w io.Writer, b *blocks.Block, opts options) error {
+	if opts.json {
+		data, err := json.Marshal(b)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+	paint := func(color, s string) string {
+		if !opts.color {
+			return s
+		}
+		return color + s + colorReset
+	}
+	var sb strings.Builder
+	for _, e := range b.Entries {
+		if e.Message != "" {
+			for _, l := range strings.Split(e.Message, "\n") {
+				sb.WriteString(paint(colorMessage, l) + "\n")
+			}
+		}
+		stack := e.Stack
+		if opts.collapse && len(stack) > 1 {
+			stack = stack[:1]
+		}
+		for _, f := range stack {
+			sb.WriteString(paint(colorFunc, f.Func) + "\n")
+			sb.WriteString("\t" + paint(colorFile, fmt.Sprintf("%s:%d", f.File, f.Line)) + "\n")
+		}
+		if len(stack) < len(e.Stack) {
+			sb.WriteString("\t" + paint(colorFile, fmt.Sprintf("... %d more", len(e.Stack)-len(stack))) + "\n")
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
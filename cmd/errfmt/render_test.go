@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const logs = `starting
+2024/01/01 request failed: EOF
+read
+main.read
+	/src/main.go:11
+main.main
+	/src/main.go:20
+load
+main.main
+	/src/main.go:21
+done
+more
+2024/01/01 other: boom
+main.main
+	/src/main.go:30
+`
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		opts options
+		want string
+	}{
+		{options{}, logs},
+		{options{collapse: true}, `starting
+2024/01/01 request failed: EOF
+read
+main.read
+	/src/main.go:11
+	... 1 more
+load
+main.main
+	/src/main.go:21
+done
+more
+2024/01/01 other: boom
+main.main
+	/src/main.go:30
+`},
+		{options{json: true}, `{"errors":[{"message":"read","stack":[{"func":"main.read","file":"/src/main.go","line":11},{"func":"main.main","file":"/src/main.go","line":20}]},{"message":"load","stack":[{"func":"main.main","file":"/src/main.go","line":21}]}]}
+{"errors":[{"message":"2024/01/01 other: boom","stack":[{"func":"main.main","file":"/src/main.go","line":30}]}]}
+`},
+		{options{color: true}, "starting\n2024/01/01 request failed: EOF\n" +
+			colorMessage + "read" + colorReset + "\n" +
+			colorFunc + "main.read" + colorReset + "\n\t" + colorFile + "/src/main.go:11" + colorReset + "\n" +
+			colorFunc + "main.main" + colorReset + "\n\t" + colorFile + "/src/main.go:20" + colorReset + "\n" +
+			colorMessage + "load" + colorReset + "\n" +
+			colorFunc + "main.main" + colorReset + "\n\t" + colorFile + "/src/main.go:21" + colorReset + "\n" +
+			"done\nmore\n" +
+			colorMessage + "2024/01/01 other: boom" + colorReset + "\n" +
+			colorFunc + "main.main" + colorReset + "\n\t" + colorFile + "/src/main.go:30" + colorReset + "\n"},
+	}
+	for _, tt := range tests {
+		var b bytes.Buffer
+		if err := format(&b, strings.NewReader(logs), tt.opts); err != nil {
+			t.Fatal(err)
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("format(%+v):\n got: %q\nwant: %q", tt.opts, got, tt.want)
+		}
+	}
+}
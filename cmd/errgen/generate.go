@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// catalog is the content of a catalog file.
+type catalog struct {
+	Errors []definition `json:"errors"`
+}
+
+// definition is the definition of an error of a catalog.
+type definition struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Kind        string            `json:"kind"`
+	Message     string            `json:"message"`
+	Params      map[string]string `json:"params"`
+	Wrap        bool              `json:"wrap"`
+	HTTP        int               `json:"http"`
+	GRPC        string            `json:"grpc"`
+	Description string            `json:"description"`
+}
+
+// kinds maps the values of the kinds of github.com/objenious/errors to the
+// names of their constants.
+var kinds = map[string]string{
+	"invalid":           "KindInvalid",
+	"not_found":         "KindNotFound",
+	"already_exists":    "KindAlreadyExists",
+	"conflict":          "KindConflict",
+	"unauthenticated":   "KindUnauthenticated",
+	"permission_denied": "KindPermissionDenied",
+	"rate_limited":      "KindRateLimited",
+	"canceled":          "KindCanceled",
+	"timeout":           "KindTimeout",
+	"unavailable":       "KindUnavailable",
+	"internal":          "KindInternal",
+}
+
+// grpcCodes maps the names of gRPC codes to their values.
+var grpcCodes = map[string]uint32{
+	"OK":                 0,
+	"Canceled":           1,
+	"Unknown":            2,
+	"InvalidArgument":    3,
+	"DeadlineExceeded":   4,
+	"NotFound":           5,
+	"AlreadyExists":      6,
+	"PermissionDenied":   7,
+	"ResourceExhausted":  8,
+	"FailedPrecondition": 9,
+	"Aborted":            10,
+	"OutOfRange":         11,
+	"Unimplemented":      12,
+	"Internal":           13,
+	"Unavailable":        14,
+	"DataLoss":           15,
+	"Unauthenticated":    16,
+}
+
+// param is a parameter of a constructor.
+type param struct {
+	name, typ string
+}
+
+// entry is a definition checked and prepared for generation.
+type entry struct {
+	definition
+	format string  // format of the message
+	params []param // parameters, in the order of the message
+}
+
+// packageName returns the name of the package of the Go files in dir, or the
+// base name of dir if there are none.
+func packageName(dir string) string {
+	fset := token.NewFileSet()
+	notTest := func(fi os.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }
+	pkgs, err := parser.ParseDir(fset, dir, notTest, parser.PackageClauseOnly)
+	if err == nil {
+		for name := range pkgs {
+			return name
+		}
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, filepath.Base(abs))
+}
+
+// generate returns the source of the package pkg for the catalog data, read
+// from the file source.
+func generate(data []byte, pkg, source string) ([]byte, error) {
+	var c catalog
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&c); err != nil {
+		return nil, err
+	}
+	entries := make([]entry, 0, len(c.Errors))
+	ids := make(map[string]bool)
+	names := make(map[string]bool)
+	for i, def := range c.Errors {
+		e, err := prepare(def)
+		if err != nil {
+			return nil, fmt.Errorf("error %d: %v", i, err)
+		}
+		if ids[e.ID] {
+			return nil, fmt.Errorf("error %d: duplicate id %q", i, e.ID)
+		}
+		if names[e.Name] {
+			return nil, fmt.Errorf("error %d: duplicate name %s", i, e.Name)
+		}
+		ids[e.ID], names[e.Name] = true, true
+		entries = append(entries, e)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by errgen from %s; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import %s\n\n", strconv.Quote("github.com/objenious/errors"))
+	b.WriteString("// Codes of the errors of the catalog, that errors.Is matches with the errors\n")
+	b.WriteString("// returned by their constructors.\n")
+	b.WriteString("const (\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "// Err%s is the code of the errors returned by %s.", e.Name, e.Name)
+		if e.Description != "" {
+			fmt.Fprintf(&b, "\n// %s", strings.Replace(e.Description, "\n", "\n// ", -1))
+		}
+		fmt.Fprintf(&b, "\nErr%s errors.Code = %s\n", e.Name, strconv.Quote(e.ID))
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("func init() {\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "errors.RegisterCode(Err%s, %s)\n", e.Name, strconv.Quote(e.Description))
+	}
+	b.WriteString("}\n")
+
+	b.WriteString("\n// HTTPStatuses maps the codes of the catalog to the HTTP statuses of their\n")
+	b.WriteString("// errors.\n")
+	b.WriteString("var HTTPStatuses = map[errors.Code]int{\n")
+	for _, e := range entries {
+		if e.HTTP != 0 {
+			fmt.Fprintf(&b, "Err%s: %d,\n", e.Name, e.HTTP)
+		}
+	}
+	b.WriteString("}\n")
+
+	b.WriteString("\n// GRPCCodes maps the codes of the catalog to the values of the gRPC codes of\n")
+	b.WriteString("// their errors.\n")
+	b.WriteString("var GRPCCodes = map[errors.Code]uint32{\n")
+	for _, e := range entries {
+		if e.GRPC != "" {
+			fmt.Fprintf(&b, "Err%s: %d, // %s\n", e.Name, grpcCodes[e.GRPC], e.GRPC)
+		}
+	}
+	b.WriteString("}\n")
+
+	for _, e := range entries {
+		constructor(&b, e)
+	}
+	return format.Source(b.Bytes())
+}
+
+// prepare checks def, and returns it prepared for generation.
+func prepare(def definition) (entry, error) {
+	e := entry{definition: def}
+	if e.ID == "" {
+		return e, fmt.Errorf("missing id")
+	}
+	if e.Name == "" {
+		e.Name = identifier(e.ID)
+	}
+	if !token.IsIdentifier(e.Name) || !token.IsExported(e.Name) {
+		return e, fmt.Errorf("%s: invalid name %q", e.ID, e.Name)
+	}
+	if _, ok := kinds[e.Kind]; !ok && e.Kind != "" {
+		return e, fmt.Errorf("%s: unknown kind %q", e.ID, e.Kind)
+	}
+	if e.HTTP != 0 && (e.HTTP < 100 || e.HTTP > 599) {
+		return e, fmt.Errorf("%s: invalid HTTP status %d", e.ID, e.HTTP)
+	}
+	if _, ok := grpcCodes[e.GRPC]; !ok && e.GRPC != "" {
+		return e, fmt.Errorf("%s: unknown gRPC code %q", e.ID, e.GRPC)
+	}
+	if e.Message == "" {
+		return e, fmt.Errorf("%s: missing message", e.ID)
+	}
+	var err error
+	if e.format, e.params, err = parseMessage(e.Message, e.Params); err != nil {
+		return e, fmt.Errorf("%s: %v", e.ID, err)
+	}
+	return e, nil
+}
+
+// identifier returns the exported identifier made of the letters and digits
+// of id, each of their sequences being capitalized, such as UserNotFound for
+// user_not_found.
+func identifier(id string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(id, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		b.WriteString(strings.ToUpper(word[:1]) + strings.ToLower(word[1:]))
+	}
+	return b.String()
+}
+
+// parseMessage returns the format of the message template msg, and its
+// parameters, typed by types.
+func parseMessage(msg string, types map[string]string) (string, []param, error) {
+	var b strings.Builder
+	var params []param
+	seen := make(map[string]bool)
+	for i := 0; i < len(msg); i++ {
+		switch c := msg[i]; {
+		case c == '{' && strings.HasPrefix(msg[i:], "{{"):
+			b.WriteByte('{')
+			i++
+		case c == '{':
+			end := strings.IndexByte(msg[i:], '}')
+			if end < 0 {
+				return "", nil, fmt.Errorf("unterminated placeholder in %q", msg)
+			}
+			name := msg[i+1 : i+end]
+			if !token.IsIdentifier(name) || name == "err" {
+				return "", nil, fmt.Errorf("invalid placeholder {%s}", name)
+			}
+			if !seen[name] {
+				seen[name] = true
+				typ := types[name]
+				if typ == "" {
+					typ = "string"
+				}
+				params = append(params, param{name: name, typ: typ})
+			}
+			b.WriteString("%v")
+			i += end
+		case c == '%':
+			b.WriteString("%%")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	var unused []string
+	for name := range types {
+		if !seen[name] {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return "", nil, fmt.Errorf("params %s not in the message", strings.Join(unused, ", "))
+	}
+	return b.String(), params, nil
+}
+
+// constructor writes the constructor of e to b.
+func constructor(b *bytes.Buffer, e entry) {
+	var params, args []string
+	if e.Wrap {
+		params = append(params, "err error")
+	}
+	for _, p := range e.params {
+		params = append(params, p.name+" "+p.typ)
+		args = append(args, ", "+p.name)
+	}
+
+	fmt.Fprintf(b, "\n// %s returns an error with the code Err%s and the message\n// %s", e.Name, e.Name, strconv.Quote(e.Message))
+	if e.Wrap {
+		b.WriteString(", wrapping err.\n// If err is nil, " + e.Name + " returns nil.\n")
+	} else {
+		b.WriteString(".\n")
+	}
+	fmt.Fprintf(b, "func %s(%s) error {\n", e.Name, strings.Join(params, ", "))
+	message := strconv.Quote(strings.Replace(e.format, "%%", "%", -1))
+	switch {
+	case e.Wrap && len(args) > 0:
+		fmt.Fprintf(b, "err = errors.Wrapf(err, %s%s)\n", strconv.Quote(e.format), strings.Join(args, ""))
+	case e.Wrap:
+		fmt.Fprintf(b, "err = errors.Wrap(err, %s)\n", message)
+	case len(args) > 0:
+		fmt.Fprintf(b, "err := errors.Errorf(%s%s)\n", strconv.Quote(e.format), strings.Join(args, ""))
+	default:
+		fmt.Fprintf(b, "err := errors.New(%s)\n", message)
+	}
+	for _, p := range e.params {
+		fmt.Fprintf(b, "err = errors.WithField(err, %s, %s)\n", strconv.Quote(p.name), p.name)
+	}
+	if e.Kind != "" {
+		fmt.Fprintf(b, "err = errors.WithKind(err, errors.%s)\n", kinds[e.Kind])
+	}
+	if e.HTTP != 0 {
+		fmt.Fprintf(b, "err = errors.WithHTTPStatus(err, %d)\n", e.HTTP)
+	}
+	fmt.Fprintf(b, "return errors.WithCode(err, Err%s)\n}\n", e.Name)
+}
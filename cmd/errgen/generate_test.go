@@ -0,0 +1,217 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+const catalogSource = `{
+	"errors": [
+		{
+			"id": "user_not_found",
+			"kind": "not_found",
+			"message": "user {id} not found",
+			"http": 404,
+			"grpc": "NotFound",
+			"description": "The user does not exist."
+		},
+		{
+			"id": "QUOTA-0042",
+			"name": "QuotaExceeded",
+			"kind": "rate_limited",
+			"message": "quota of {count} requests at 100% for {user}",
+			"params": {"count": "int"},
+			"grpc": "ResourceExhausted"
+		},
+		{
+			"id": "storage",
+			"kind": "unavailable",
+			"message": "storage {{down}",
+			"wrap": true
+		}
+	]
+}
+`
+
+const catalogCode = `// Code generated by errgen from errors.json; DO NOT EDIT.
+
+package users
+
+import "github.com/objenious/errors"
+
+// Codes of the errors of the catalog, that errors.Is matches with the errors
+// returned by their constructors.
+const (
+	// ErrUserNotFound is the code of the errors returned by UserNotFound.
+	// The user does not exist.
+	ErrUserNotFound errors.Code = "user_not_found"
+	// ErrQuotaExceeded is the code of the errors returned by QuotaExceeded.
+	ErrQuotaExceeded errors.Code = "QUOTA-0042"
+	// ErrStorage is the code of the errors returned by Storage.
+	ErrStorage errors.Code = "storage"
+)
+
+func init() {
+	errors.RegisterCode(ErrUserNotFound, "The user does not exist.")
+	errors.RegisterCode(ErrQuotaExceeded, "")
+	errors.RegisterCode(ErrStorage, "")
+}
+
+// HTTPStatuses maps the codes of the catalog to the HTTP statuses of their
+// errors.
+var HTTPStatuses = map[errors.Code]int{
+	ErrUserNotFound: 404,
+}
+
+// GRPCCodes maps the codes of the catalog to the values of the gRPC codes of
+// their errors.
+var GRPCCodes = map[errors.Code]uint32{
+	ErrUserNotFound:  5, // NotFound
+	ErrQuotaExceeded: 8, // ResourceExhausted
+}
+
+// UserNotFound returns an error with the code ErrUserNotFound and the message
+// "user {id} not found".
+func UserNotFound(id string) error {
+	err := errors.Errorf("user %v not found", id)
+	err = errors.WithField(err, "id", id)
+	err = errors.WithKind(err, errors.KindNotFound)
+	err = errors.WithHTTPStatus(err, 404)
+	return errors.WithCode(err, ErrUserNotFound)
+}
+
+// QuotaExceeded returns an error with the code ErrQuotaExceeded and the message
+// "quota of {count} requests at 100% for {user}".
+func QuotaExceeded(count int, user string) error {
+	err := errors.Errorf("quota of %v requests at 100%% for %v", count, user)
+	err = errors.WithField(err, "count", count)
+	err = errors.WithField(err, "user", user)
+	err = errors.WithKind(err, errors.KindRateLimited)
+	return errors.WithCode(err, ErrQuotaExceeded)
+}
+
+// Storage returns an error with the code ErrStorage and the message
+// "storage {{down}", wrapping err.
+// If err is nil, Storage returns nil.
+func Storage(err error) error {
+	err = errors.Wrap(err, "storage {down}")
+	err = errors.WithKind(err, errors.KindUnavailable)
+	return errors.WithCode(err, ErrStorage)
+}
+`
+
+const catalogTest = `package users
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/objenious/errors"
+)
+
+func TestCatalog(t *testing.T) {
+	err := UserNotFound("42")
+	if got := fmt.Sprint(err); got != "user 42 not found" {
+		t.Errorf("UserNotFound() = %s", got)
+	}
+	if !errors.Is(err, ErrUserNotFound) || errors.KindOf(err) != errors.KindNotFound {
+		t.Errorf("UserNotFound() = %+v", err)
+	}
+	if status, _ := errors.HTTPStatus(err); status != HTTPStatuses[ErrUserNotFound] {
+		t.Errorf("HTTPStatus() = %d", status)
+	}
+	if got := errors.Fields(QuotaExceeded(3, "bob"))["count"]; got != 3 {
+		t.Errorf("count = %v", got)
+	}
+	if Storage(nil) != nil {
+		t.Errorf("Storage(nil) != nil")
+	}
+	if got := fmt.Sprint(Storage(errors.New("down"))); got != "storage {down}: down" {
+		t.Errorf("Storage() = %s", got)
+	}
+	if _, ok := errors.RegisteredCodes()[ErrStorage]; !ok {
+		t.Errorf("ErrStorage is not registered")
+	}
+}
+`
+
+func TestGenerate(t *testing.T) {
+	src, err := generate([]byte(catalogSource), "users", "errors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != catalogCode {
+		t.Errorf("generate:\n got: %s\nwant: %s", src, catalogCode)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []string{
+		`{"errors": [{"id": "a"}]}`,
+		`{"errors": [{"message": "a"}]}`,
+		`{"errors": [{"id": "a", "message": "a", "kind": "missing"}]}`,
+		`{"errors": [{"id": "a", "message": "a", "http": 42}]}`,
+		`{"errors": [{"id": "a", "message": "a", "grpc": "Missing"}]}`,
+		`{"errors": [{"id": "42", "message": "a"}]}`,
+		`{"errors": [{"id": "a", "message": "a {b"}]}`,
+		`{"errors": [{"id": "a", "message": "a {b c}"}]}`,
+		`{"errors": [{"id": "a", "message": "a", "params": {"b": "int"}}]}`,
+		`{"errors": [{"id": "a", "message": "a"}, {"id": "a", "message": "b"}]}`,
+		`{"errors": [{"id": "a", "message": "a"}, {"id": "A", "message": "b"}]}`,
+		`{"errors": [{"id": "a", "message": "a", "unknown": true}]}`,
+	}
+	for _, tt := range tests {
+		if _, err := generate([]byte(tt), "users", "errors.json"); err == nil {
+			t.Errorf("generate(%s) succeeded", tt)
+		}
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"user_not_found": "UserNotFound",
+		"DEV-0042":       "Dev0042",
+		"http.timeout":   "HttpTimeout",
+	}
+	for id, want := range tests {
+		if got := identifier(id); got != want {
+			t.Errorf("identifier(%q) = %s, want %s", id, got, want)
+		}
+	}
+}
+
+// TestGenerateBuild runs the tests of a package using generated code.
+func TestGenerateBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	_, file, _, _ := runtime.Caller(0)
+	root := filepath.Join(filepath.Dir(file), "..", "..")
+	dir, err := ioutil.TempDir("", "errgen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"errors_errgen.go": catalogCode,
+		"users_test.go":    catalogTest,
+		"go.mod": "module example.com/users\n\ngo 1.13\n\n" +
+			"require github.com/objenious/errors v0.0.0\n\n" +
+			"replace github.com/objenious/errors => " + root + "\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command("go", "test", "-mod=mod", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOPROXY=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,90 @@
+// Command errgen generates the codes, constructors and mappings of a catalog
+// of errors defined in a JSON file, so that the documentation of the errors
+// of a service, their code and their HTTP and gRPC mappings are kept in sync
+// from a single definition.
+//
+// For a catalog errors.json in the directory of a package,
+//
+//	//go:generate errgen -catalog errors.json
+//
+// generates in errors_errgen.go, for each error of the catalog, a code that
+// is also the sentinel matched by errors.Is, and a constructor. The catalog
+//
+//	{
+//		"errors": [
+//			{
+//				"id": "user_not_found",
+//				"kind": "not_found",
+//				"message": "user {id} not found",
+//				"http": 404,
+//				"grpc": "NotFound",
+//				"description": "The user does not exist."
+//			}
+//		]
+//	}
+//
+// generates the code ErrUserNotFound, and a function
+//
+//	func UserNotFound(id string) error
+//
+// returning an error with the message "user 42 not found", the field id, the
+// kind KindNotFound, the HTTP status 404 and the code ErrUserNotFound. The
+// codes are registered with errors.RegisterCode, and the maps HTTPStatuses
+// and GRPCCodes give the statuses and gRPC codes of the catalog.
+//
+// The placeholders of messages, {name}, are the parameters of the
+// constructors, of type string unless given by params, such as
+// "params": {"count": "int"}; "{{" is a literal brace. The name of the
+// constructor is derived from the ID unless given by name, and errors with
+// "wrap": true have constructors taking a cause as first parameter.
+//
+// Usage:
+//
+//	errgen -catalog file [-package name] [-output file]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	catalog := flag.String("catalog", "", "JSON catalog of errors")
+	pkg := flag.String("package", "", "package name, that of the Go files of the directory of the catalog by default")
+	output := flag.String("output", "", "output file, <catalog>_errgen.go in the directory of the catalog by default")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: errgen -catalog file [-package name] [-output file]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *catalog == "" || flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := filepath.Dir(*catalog)
+	if *pkg == "" {
+		*pkg = packageName(dir)
+	}
+	data, err := ioutil.ReadFile(*catalog)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "errgen: %v\n", err)
+		os.Exit(1)
+	}
+	src, err := generate(data, *pkg, filepath.Base(*catalog))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "errgen: %s: %v\n", *catalog, err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		base := filepath.Base(*catalog)
+		*output = filepath.Join(dir, strings.TrimSuffix(base, filepath.Ext(base))+"_errgen.go")
+	}
+	if err := ioutil.WriteFile(*output, src, 0666); err != nil {
+		fmt.Fprintf(os.Stderr, "errgen: %v\n", err)
+		os.Exit(1)
+	}
+}
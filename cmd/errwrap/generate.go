@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// method is a method of the decorated interface.
+type method struct {
+	name string
+	typ  *ast.FuncType
+	file *ast.File
+}
+
+// generator generates the decorator of an interface of a package.
+type generator struct {
+	fset    *token.FileSet
+	pkg     *ast.Package
+	imports map[string]string // names to paths of the used imports
+	buf     bytes.Buffer
+}
+
+// generate returns the source of the decorator of the interface typeName
+// declared in the package in dir.
+func generate(dir, typeName string) ([]byte, error) {
+	fset := token.NewFileSet()
+	notTest := func(fi os.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }
+	pkgs, err := parser.ParseDir(fset, dir, notTest, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range pkgs {
+		g := &generator{fset: fset, pkg: pkg, imports: make(map[string]string)}
+		if _, _, ok := g.lookup(typeName); !ok {
+			continue
+		}
+		methods, err := g.methods(typeName, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		return g.generate(typeName, methods)
+	}
+	return nil, fmt.Errorf("interface %s not found in %s", typeName, dir)
+}
+
+// lookup returns the interface name declared in the package, and its file.
+func (g *generator) lookup(name string) (*ast.InterfaceType, *ast.File, bool) {
+	for _, file := range g.pkg.Files {
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.Name.Name != name {
+					continue
+				}
+				it, ok := ts.Type.(*ast.InterfaceType)
+				return it, file, ok
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// methods returns the methods of the interface name, including those of the
+// interfaces it embeds, sorted by name.
+func (g *generator) methods(name string, seen map[string]bool) ([]method, error) {
+	if seen[name] {
+		return nil, nil
+	}
+	seen[name] = true
+	it, file, ok := g.lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an interface of package %s", name, g.pkg.Name)
+	}
+	var methods []method
+	for _, f := range it.Methods.List {
+		switch t := f.Type.(type) {
+		case *ast.FuncType:
+			for _, n := range f.Names {
+				methods = append(methods, method{name: n.Name, typ: t, file: file})
+			}
+		case *ast.Ident:
+			if t.Name == "error" {
+				errorType := &ast.FuncType{Params: &ast.FieldList{}, Results: &ast.FieldList{
+					List: []*ast.Field{{Type: ast.NewIdent("string")}},
+				}}
+				methods = append(methods, method{name: "Error", typ: errorType, file: file})
+				continue
+			}
+			embedded, err := g.methods(t.Name, seen)
+			if err != nil {
+				return nil, err
+			}
+			methods = append(methods, embedded...)
+		default:
+			return nil, fmt.Errorf("%s: unsupported embedded interface %s", name, g.expr(f.Type))
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].name < methods[j].name })
+	return methods, nil
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// generate returns the source of the decorator of the interface typeName
+// with methods.
+func (g *generator) generate(typeName string, methods []method) ([]byte, error) {
+	exported := ast.IsExported(typeName)
+	capitalized := strings.ToUpper(typeName[:1]) + typeName[1:]
+	wrapFunc, wrapType := "Wrap"+capitalized, "wrapped"+capitalized
+	if !exported {
+		wrapFunc = "wrap" + capitalized
+	}
+
+	var body bytes.Buffer
+	for _, m := range methods {
+		g.method(&body, wrapType, m)
+	}
+	errorsName := "errors"
+	if _, ok := g.imports[errorsName]; ok {
+		errorsName = "oerrors"
+	}
+
+	g.printf("// Code generated by errwrap; DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", g.pkg.Name)
+	g.printf("import (\n")
+	names := make([]string, 0, len(g.imports))
+	for name := range g.imports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if p := g.imports[name]; guessName(p) == name {
+			g.printf("%s\n", strconv.Quote(p))
+		} else {
+			g.printf("%s %s\n", name, strconv.Quote(p))
+		}
+	}
+	if errorsName == "errors" {
+		g.printf("\n%s\n", strconv.Quote("github.com/objenious/errors"))
+	} else {
+		g.printf("\n%s %s\n", errorsName, strconv.Quote("github.com/objenious/errors"))
+	}
+	g.printf(")\n\n")
+	g.printf("// %s wraps the errors returned by the methods of a %s.\n", wrapType, typeName)
+	g.printf("type %s struct {\nimpl %s\nop string\n}\n\n", wrapType, typeName)
+	g.printf("// %s returns a %s calling the methods of impl, that wraps the errors\n", wrapFunc, typeName)
+	g.printf("// they return with the message op.Method.\n")
+	g.printf("func %s(impl %s, op string) %s {\nreturn &%s{impl: impl, op: op}\n}\n", wrapFunc, typeName, typeName, wrapType)
+	g.buf.Write(bytes.Replace(body.Bytes(), []byte("errors.Wrap("), []byte(errorsName+".Wrap("), -1))
+	return format.Source(g.buf.Bytes())
+}
+
+// method writes the method m of the decorator type wrapType to b.
+func (g *generator) method(b *bytes.Buffer, wrapType string, m method) {
+	var params, args []string
+	variadic := false
+	n := 0
+	for _, f := range fields(m.typ.Params) {
+		name := fmt.Sprintf("a%d", n)
+		n++
+		typ := g.typeExpr(f, m.file)
+		if _, ok := f.Type.(*ast.Ellipsis); ok {
+			variadic = true
+		}
+		params = append(params, name+" "+typ)
+		args = append(args, name)
+	}
+	call := fmt.Sprintf("w.impl.%s(%s", m.name, strings.Join(args, ", "))
+	if variadic {
+		call += "..."
+	}
+	call += ")"
+
+	var results, names, returns []string
+	for i, f := range fields(m.typ.Results) {
+		name := fmt.Sprintf("r%d", i)
+		typ := g.typeExpr(f, m.file)
+		results = append(results, name+" "+typ)
+		names = append(names, name)
+		if typ == "error" {
+			returns = append(returns, fmt.Sprintf("errors.Wrap(%s, w.op+%s)", name, strconv.Quote("."+m.name)))
+		} else {
+			returns = append(returns, name)
+		}
+	}
+
+	fmt.Fprintf(b, "\nfunc (w *%s) %s(%s) ", wrapType, m.name, strings.Join(params, ", "))
+	if len(results) > 0 {
+		fmt.Fprintf(b, "(%s) {\n%s = %s\nreturn %s\n}\n", strings.Join(results, ", "), strings.Join(names, ", "), call, strings.Join(returns, ", "))
+	} else {
+		fmt.Fprintf(b, "{\n%s\n}\n", call)
+	}
+}
+
+// fields returns the fields of l, one per name.
+func fields(l *ast.FieldList) []*ast.Field {
+	if l == nil {
+		return nil
+	}
+	var list []*ast.Field
+	for _, f := range l.List {
+		for i := 0; i < len(f.Names) || i == 0 && len(f.Names) == 0; i++ {
+			list = append(list, f)
+		}
+	}
+	return list
+}
+
+// typeExpr returns the type of f, declared in file, recording the imports it
+// uses.
+func (g *generator) typeExpr(f *ast.Field, file *ast.File) string {
+	ast.Inspect(f.Type, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok {
+			for _, imp := range file.Imports {
+				p, _ := strconv.Unquote(imp.Path.Value)
+				name := guessName(p)
+				if imp.Name != nil {
+					name = imp.Name.Name
+				}
+				if name == x.Name {
+					g.imports[name] = p
+				}
+			}
+		}
+		return false
+	})
+	return g.expr(f.Type)
+}
+
+// expr returns the source of e.
+func (g *generator) expr(e ast.Expr) string {
+	var b bytes.Buffer
+	printer.Fprint(&b, g.fset, e)
+	return b.String()
+}
+
+// guessName returns the likely name of the package of import path p: its last
+// element, without a major version suffix or a "go-" prefix.
+func guessName(p string) string {
+	name := path.Base(p)
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" && path.Dir(p) != "." {
+		name = path.Base(path.Dir(p))
+	}
+	if i := strings.Index(name, ".v"); i > 0 {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	return strings.Replace(name, "-", "_", -1)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+const storeSource = `package store
+
+import (
+	"context"
+	"io"
+	"net/url"
+)
+
+type User struct{ Name string }
+
+type Closer interface {
+	Close() error
+}
+
+type Store interface {
+	Closer
+	Get(ctx context.Context, id string) (*User, error)
+	Put(context.Context, *User) error
+	Find(ctx context.Context, filter url.Values, ids ...string) ([]*User, int, error)
+	Name() string
+	Reset()
+}
+
+var _ io.Reader
+`
+
+const storeWrapper = `// Code generated by errwrap; DO NOT EDIT.
+
+package store
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/objenious/errors"
+)
+
+// wrappedStore wraps the errors returned by the methods of a Store.
+type wrappedStore struct {
+	impl Store
+	op   string
+}
+
+// WrapStore returns a Store calling the methods of impl, that wraps the errors
+// they return with the message op.Method.
+func WrapStore(impl Store, op string) Store {
+	return &wrappedStore{impl: impl, op: op}
+}
+
+func (w *wrappedStore) Close() (r0 error) {
+	r0 = w.impl.Close()
+	return errors.Wrap(r0, w.op+".Close")
+}
+
+func (w *wrappedStore) Find(a0 context.Context, a1 url.Values, a2 ...string) (r0 []*User, r1 int, r2 error) {
+	r0, r1, r2 = w.impl.Find(a0, a1, a2...)
+	return r0, r1, errors.Wrap(r2, w.op+".Find")
+}
+
+func (w *wrappedStore) Get(a0 context.Context, a1 string) (r0 *User, r1 error) {
+	r0, r1 = w.impl.Get(a0, a1)
+	return r0, errors.Wrap(r1, w.op+".Get")
+}
+
+func (w *wrappedStore) Name() (r0 string) {
+	r0 = w.impl.Name()
+	return r0
+}
+
+func (w *wrappedStore) Put(a0 context.Context, a1 *User) (r0 error) {
+	r0 = w.impl.Put(a0, a1)
+	return errors.Wrap(r0, w.op+".Put")
+}
+
+func (w *wrappedStore) Reset() {
+	w.impl.Reset()
+}
+`
+
+const storeTest = `package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/objenious/errors"
+)
+
+type store struct{ Store }
+
+func (store) Get(ctx context.Context, id string) (*User, error) {
+	return nil, errors.New("not found")
+}
+
+func TestWrap(t *testing.T) {
+	_, err := WrapStore(store{}, "users").Get(context.Background(), "1")
+	if got := fmt.Sprint(err); got != "users.Get: not found" {
+		t.Errorf("Get() = %s", got)
+	}
+}
+`
+
+func tempPackage(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "errwrap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "store.go"), []byte(storeSource), 0666); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestGenerate(t *testing.T) {
+	dir := tempPackage(t)
+	defer os.RemoveAll(dir)
+	src, err := generate(dir, "Store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != storeWrapper {
+		t.Errorf("generate:\n got: %s\nwant: %s", src, storeWrapper)
+	}
+
+	if _, err := generate(dir, "User"); err == nil {
+		t.Errorf("generate(User) succeeded")
+	}
+	if _, err := generate(dir, "Missing"); err == nil {
+		t.Errorf("generate(Missing) succeeded")
+	}
+}
+
+// TestGenerateBuild runs the tests of a package using generated code.
+func TestGenerateBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+	_, file, _, _ := runtime.Caller(0)
+	root := filepath.Join(filepath.Dir(file), "..", "..")
+	dir := tempPackage(t)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"store_errwrap.go": storeWrapper,
+		"store_test.go":    storeTest,
+		"go.mod": "module example.com/store\n\ngo 1.13\n\n" +
+			"require github.com/objenious/errors v0.0.0\n\n" +
+			"replace github.com/objenious/errors => " + root + "\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command("go", "test", "-mod=mod", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOPROXY=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,61 @@
+// Command errwrap generates decorators of interfaces wrapping the errors
+// returned by their methods with github.com/objenious/errors, so that the
+// errors of storage and client layers are annotated consistently without
+// hand-written decorators. Go can't implement interfaces at run time, hence
+// the generated code.
+//
+// For an interface Store declared in the package in the current directory,
+//
+//	//go:generate errwrap -type Store
+//
+// generates in store_errwrap.go a function
+//
+//	func WrapStore(impl Store, op string) Store
+//
+// returning a Store whose methods call those of impl, and wrap the errors
+// they return with the message op.Method, e.g. "users.Get", and the stack
+// trace at the point they returned.
+//
+// Usage:
+//
+//	errwrap -type name [-output file] [dir]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeName := flag.String("type", "", "name of the interface to decorate")
+	output := flag.String("output", "", "output file, <type>_errwrap.go in dir by default")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: errwrap -type name [-output file] [dir]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *typeName == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	src, err := generate(dir, *typeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "errwrap: %v\n", err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		*output = filepath.Join(dir, strings.ToLower(*typeName)+"_errwrap.go")
+	}
+	if err := ioutil.WriteFile(*output, src, 0666); err != nil {
+		fmt.Fprintf(os.Stderr, "errwrap: %v\n", err)
+		os.Exit(1)
+	}
+}
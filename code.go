@@ -0,0 +1,63 @@
+package errors
+
+import "sync"
+
+// Code identifies an error condition in a stable, machine-readable way,
+// such as "DEV-0042", for documentation, support and triage. Like a Kind, a
+// Code is also an error, that Is matches with the errors annotated with that
+// code, see CodeOf.
+type Code string
+
+// String returns c
+func (c Code) String() string { return string(c) }
+
+// Error returns c
+func (c Code) Error() string { return string(c) }
+
+// WithCode annotates err with the code c, as returned by CodeOf and included
+// by ToMap.
+// If err is nil, WithCode returns nil.
+func WithCode(err error, c Code) error {
+	if err == nil {
+		return nil
+	}
+	return &withAttr{error: err, name: "code", value: c}
+}
+
+// CodeOf returns the code of the outermost error of the chain of err
+// annotated with WithCode or decoded with a code, or an empty Code. It is the
+// only code of the chain Is matches: an outer WithCode overrides the codes
+// set deeper, which errors.Is of the standard library still matches.
+func CodeOf(err error) Code {
+	c, _ := lookupAttr(err, "code")
+	code, _ := c.(Code)
+	return code
+}
+
+var (
+	codesMu sync.RWMutex
+	// registeredCodes is the catalog of RegisterCode.
+	registeredCodes = map[Code]string{}
+)
+
+// RegisterCode registers the code c, with a description of the condition it
+// identifies, in the catalog of codes returned by RegisteredCodes and
+// documented by JSONSchema. Registering a code again replaces its
+// description.
+func RegisterCode(c Code, description string) {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+	registeredCodes[c] = description
+}
+
+// RegisteredCodes returns the codes registered with RegisterCode, with their
+// descriptions.
+func RegisteredCodes() map[Code]string {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+	codes := make(map[Code]string, len(registeredCodes))
+	for c, description := range registeredCodes {
+		codes[c] = description
+	}
+	return codes
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"io"
+	"testing"
+)
+
+func TestIsKindAndCode(t *testing.T) {
+	err := Wrap(WithCode(WithKind(New("device not found"), KindNotFound), "DEV-0042"), "get device")
+	if got := CodeOf(err); got != "DEV-0042" {
+		t.Errorf("CodeOf(): got %q, want \"DEV-0042\"", got)
+	}
+	if got := CodeOf(io.EOF); got != "" {
+		t.Errorf("CodeOf(io.EOF): got %q, want \"\"", got)
+	}
+	if got := WithCode(nil, "DEV-0042"); got != nil {
+		t.Errorf("WithCode(nil): got %#v, want nil", got)
+	}
+
+	data, _ := json.Marshal(ToMap(err))
+	remote, decodeErr := FromJSON(data)
+	if decodeErr != nil {
+		t.Fatal(decodeErr)
+	}
+	for _, err := range []error{err, Wrap(remote, "call")} {
+		tests := []struct {
+			target error
+			want   bool
+		}{
+			{KindNotFound, true},
+			{KindInternal, false},
+			{KindUnknown, false},
+			{Code("DEV-0042"), true},
+			{Code("DEV-0043"), false},
+			{Code(""), false},
+		}
+		for _, tt := range tests {
+			if got := goerrors.Is(err, tt.target); got != tt.want {
+				t.Errorf("errors.Is(%#v, %q): got %t, want %t", err, tt.target, got, tt.want)
+			}
+		}
+		if got := KindOf(err); got != KindNotFound {
+			t.Errorf("KindOf(%#v): got %q, want %q", err, got, KindNotFound)
+		}
+		if got := CodeOf(err); got != "DEV-0042" {
+			t.Errorf("CodeOf(%#v): got %q, want \"DEV-0042\"", err, got)
+		}
+		if !IsClientError(err) {
+			t.Errorf("IsClientError(%#v) = false", err)
+		}
+	}
+
+	// outer annotations override the kind and code for Is, not errors.Is.
+	err = WithCode(WithKind(Wrap(WithCode(WithKind(io.EOF, KindNotFound), "DEV-0001"), "get"), KindInternal), "DEV-0002")
+	if !Is(err, KindInternal) || Is(err, KindNotFound) || KindOf(err) != KindInternal {
+		t.Errorf("Is(%#v): an overridden kind matches", err)
+	}
+	if !Is(err, Code("DEV-0002")) || Is(err, Code("DEV-0001")) || CodeOf(err) != "DEV-0002" {
+		t.Errorf("Is(%#v): an overridden code matches", err)
+	}
+	if !goerrors.Is(err, KindNotFound) || !goerrors.Is(err, Code("DEV-0001")) {
+		t.Errorf("errors.Is(%#v): the overridden kind and code do not match", err)
+	}
+	if !Is(Wrap(KindNotFound, "get"), KindNotFound) {
+		t.Errorf("Is(): a Kind returned as an error does not match")
+	}
+
+	// errors annotated with the zero kind or code don't match it.
+	err = WithCode(WithKind(io.EOF, KindUnknown), "")
+	if goerrors.Is(err, KindUnknown) || goerrors.Is(err, Code("")) || Is(err, KindUnknown) {
+		t.Errorf("errors.Is(%#v) matches the zero kind or code", err)
+	}
+}
@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// codePrefix is whether SetCodePrefix is enabled.
+var codePrefix int32
+
+// SetCodePrefix sets whether the messages of the errors annotated with a code
+// by WithCode start with the code in brackets, so that logs, support tickets
+// and the messages shown to users share the same identifiers:
+//
+//	[DEV-0042] device not found
+//
+// The prefix is added by Error, and thus by %s, %v and %q, by the first line
+// of %+v and by UserMessage. Messages already starting with the prefix are
+// not prefixed again. It is disabled by default; as the errors created by
+// Wrap render their message once, it should be set before errors are
+// created.
+func SetCodePrefix(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&codePrefix, v)
+}
+
+// withCodePrefix returns msg prefixed with the code c if SetCodePrefix is
+// enabled.
+func withCodePrefix(c Code, msg string) string {
+	if c == "" || atomic.LoadInt32(&codePrefix) == 0 {
+		return msg
+	}
+	prefix := "[" + string(c) + "] "
+	if strings.HasPrefix(msg, prefix) {
+		return msg
+	}
+	return prefix + msg
+}
+
+// Error returns the message of the annotated error, prefixed with its code,
+// see SetCodePrefix.
+func (w *withAttr) Error() string {
+	msg := w.error.Error()
+	if c, ok := w.value.(Code); ok && w.name == "code" {
+		return withCodePrefix(c, msg)
+	}
+	return msg
+}
@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSetCodePrefix(t *testing.T) {
+	defer SetCodePrefix(false)
+
+	err := WithCode(New("device not found"), "DEV-0042")
+	if got := err.Error(); got != "device not found" {
+		t.Errorf("Error() without prefix = %q", got)
+	}
+
+	SetCodePrefix(true)
+	tests := []struct {
+		format, want string
+	}{
+		{"%s", "[DEV-0042] device not found"},
+		{"%v", "[DEV-0042] device not found"},
+		{"%q", `"[DEV-0042] device not found"`},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, err); got != tt.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.HasPrefix(got, "[DEV-0042] device not found\n") {
+		t.Errorf("Sprintf(%%+v) = %q, want the prefix on the first line", got)
+	}
+	if got, want := Wrap(err, "load").Error(), "load: [DEV-0042] device not found"; got != want {
+		t.Errorf("Wrap().Error() = %q, want %q", got, want)
+	}
+	if got, want := WithCode(err, "DEV-0042").Error(), "[DEV-0042] device not found"; got != want {
+		t.Errorf("WithCode(WithCode()).Error() = %q, want %q", got, want)
+	}
+	if got, want := UserMessage(WithUserMessage(err, "Device not found")), "[DEV-0042] Device not found"; got != want {
+		t.Errorf("UserMessage() = %q, want %q", got, want)
+	}
+	if got := New("boom").Error(); got != "boom" {
+		t.Errorf("Error() without code = %q", got)
+	}
+}
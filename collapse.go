@@ -0,0 +1,62 @@
+package errors
+
+import "sync/atomic"
+
+// WrapRendering selects how %+v prints consecutive wrappers, see
+// SetWrapRendering.
+type WrapRendering int32
+
+// Wrap renderings.
+const (
+	// FullWraps prints the message and the stack trace of every wrapper.
+	FullWraps WrapRendering = iota
+	// CollapsedWraps prints consecutive wrappers created in the same
+	// function, whose stack traces only differ by the line of their first
+	// frame, as a single "a: b: c" line with the stack trace of the
+	// innermost of them.
+	CollapsedWraps
+)
+
+// wrapRendering is the current WrapRendering.
+var wrapRendering int32
+
+// SetWrapRendering sets how %+v prints consecutive wrappers. The default is
+// FullWraps.
+func SetWrapRendering(r WrapRendering) {
+	atomic.StoreInt32(&wrapRendering, int32(r))
+}
+
+// collapsed returns the consecutive wrappers w can be collapsed with, from w
+// to the innermost of them, or nil if there are none or wrappers are not
+// collapsed.
+func (w *withStack) collapsed() []*withStack {
+	if WrapRendering(atomic.LoadInt32(&wrapRendering)) != CollapsedWraps {
+		return nil
+	}
+	var run []*withStack
+	for last := w; ; {
+		inner, ok := last.error.(*withStack)
+		if !ok || !inner.wrapped || inner.message() == "" || !sameFunction(last.stack, inner.stack) {
+			return run
+		}
+		if run == nil {
+			run = []*withStack{w}
+		}
+		run = append(run, inner)
+		last = inner
+	}
+}
+
+// sameFunction reports whether a and b were recorded in the same function,
+// called from the same place.
+func sameFunction(a, b *stack) bool {
+	if a == nil || b == nil || len(*a) == 0 || len(*a) != len(*b) {
+		return false
+	}
+	for i := 1; i < len(*a); i++ {
+		if (*a)[i] != (*b)[i] {
+			return false
+		}
+	}
+	return Frame((*a)[0]).name() == Frame((*b)[0]).name()
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// layered wraps err several times in the same function.
+func layered(err error) error {
+	err = Wrap(err, "decode")
+	err = Wrap(err, "parse")
+	return Wrap(err, "load")
+}
+
+func TestSetWrapRendering(t *testing.T) {
+	defer SetWrapRendering(FullWraps)
+	err := Wrap(layered(New("boom")), "import")
+
+	full := fmt.Sprintf("%+.1v", err)
+	if n := strings.Count(full, "errors.layered\n"); n != 3 {
+		t.Errorf("FullWraps prints %d stack traces of layered, want 3:\n%s", n, full)
+	}
+
+	SetWrapRendering(CollapsedWraps)
+	got := fmt.Sprintf("%+.1v", err)
+	want := "^boom\n" +
+		"github.com/objenious/errors.TestSetWrapRendering\n" +
+		"\t.+/collapse_test.go:\\d+\n" +
+		"load: parse: decode\n" +
+		"github.com/objenious/errors.layered\n" +
+		"\t.+/collapse_test.go:13\n" +
+		"import\n" +
+		"github.com/objenious/errors.TestSetWrapRendering\n" +
+		"\t.+/collapse_test.go:\\d+$"
+	if !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("CollapsedWraps:\n got: %s\nwant: %s", got, want)
+	}
+	if err.Error() != "import: load: parse: decode: boom" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	// wrappers separated by WithStack are not collapsed.
+	err = Wrap(WithStack(Wrap(io.EOF, "read")), "load")
+	if got := fmt.Sprintf("%+v", err); strings.Contains(got, "load: read") {
+		t.Errorf("CollapsedWraps collapses wrappers separated by WithStack:\n%s", got)
+	}
+}
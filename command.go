@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// maxStderrTail is the number of bytes of the standard error of commands
+// kept by FromExec.
+const maxStderrTail = 1024
+
+// commandRedactor holds the function set by SetCommandRedactor.
+var commandRedactor atomic.Value
+
+type argsRedactor struct {
+	f func(args []string) []string
+}
+
+// SetCommandRedactor sets a function applied to the command lines recorded
+// by FromExec, to hide secrets passed as arguments. It returns the arguments
+// to record instead, and must not modify args. A nil function disables
+// redaction.
+func SetCommandRedactor(f func(args []string) []string) {
+	commandRedactor.Store(argsRedactor{f})
+}
+
+// FromExec annotates the error err returned by running cmd, with the stack
+// trace at the point FromExec was called and the fields "command" (the
+// command line, see SetCommandRedactor), "exit_code" if the command exited,
+// and "stderr": the last 1 KiB of stderr, or of the standard error captured
+// by cmd.Output if stderr is empty.
+// If err is nil, FromExec returns nil.
+func FromExec(cmd *exec.Cmd, err error, stderr []byte) error {
+	if err == nil {
+		return nil
+	}
+	args := cmd.Args
+	if len(args) == 0 {
+		args = []string{cmd.Path}
+	}
+	if r, _ := commandRedactor.Load().(argsRedactor); r.f != nil {
+		args = r.f(args)
+	}
+	var name string
+	if len(args) > 0 {
+		name = filepath.Base(args[0])
+	}
+	var exitErr *exec.ExitError
+	isExit := As(err, &exitErr)
+	if len(stderr) == 0 && isExit {
+		stderr = exitErr.Stderr
+	}
+	err = WithField(wrapError(err, callers(), "run "+name, nil), "command", strings.Join(args, " "))
+	if isExit && exitErr.ProcessState != nil && exitErr.ExitCode() >= 0 {
+		err = WithField(err, "exit_code", exitErr.ExitCode())
+	}
+	if len(stderr) > 0 {
+		err = WithField(err, "stderr", stderrTail(stderr))
+	}
+	return err
+}
+
+// stderrTail returns the last maxStderrTail bytes of stderr, preceded by
+// "..." if it is longer.
+func stderrTail(stderr []byte) string {
+	if len(stderr) <= maxStderrTail {
+		return string(stderr)
+	}
+	stderr = stderr[len(stderr)-maxStderrTail:]
+	// do not cut a rune in half.
+	for i := 1; i < utf8.UTFMax && len(stderr) > 0 && !utf8.RuneStart(stderr[0]); i++ {
+		stderr = stderr[1:]
+	}
+	return "..." + string(stderr)
+}
@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestFromExec(t *testing.T) {
+	if err := FromExec(exec.Command("true"), nil, nil); err != nil {
+		t.Errorf("FromExec(nil): got %v, want nil", err)
+	}
+
+	cmd := exec.Command("sh", "-c", "echo oops >&2; exit 3", "--token=secret")
+	_, runErr := cmd.Output()
+	if runErr == nil {
+		t.Skip("sh is not available")
+	}
+	defer SetCommandRedactor(nil)
+	SetCommandRedactor(func(args []string) []string {
+		redacted := make([]string, len(args))
+		for i, arg := range args {
+			if strings.HasPrefix(arg, "--token=") {
+				arg = "--token=REDACTED"
+			}
+			redacted[i] = arg
+		}
+		return redacted
+	})
+	err := FromExec(cmd, runErr, nil)
+	if got, want := err.Error(), "run sh: exit status 3"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	fields := Fields(err)
+	if got, want := fields["command"], "sh -c echo oops >&2; exit 3 --token=REDACTED"; got != want {
+		t.Errorf("command: got %q, want %q", got, want)
+	}
+	if got := fields["exit_code"]; got != 3 {
+		t.Errorf("exit_code: got %v, want 3", got)
+	}
+	if got := fields["stderr"]; got != "oops\n" {
+		t.Errorf("stderr: got %q, want \"oops\\n\"", got)
+	}
+	if Cause(err) != runErr {
+		t.Errorf("Cause(): got %v, want %v", Cause(err), runErr)
+	}
+
+	if got := stderrTail([]byte(strings.Repeat("é", maxStderrTail) + "a")); got != "..."+strings.Repeat("é", maxStderrTail/2-1)+"a" {
+		t.Errorf("stderrTail: got %q", got)
+	}
+}
@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"encoding/hex"
+	goerrors "errors"
+	"unicode/utf8"
+)
+
+// MaxCompactSize is the maximum size of the encodings returned by ToCompact.
+const MaxCompactSize = 192
+
+// compactVersion is the version of the encoding of ToCompact, its first
+// byte.
+const compactVersion = 1
+
+// maxCompactCode is the maximum length of the codes encoded by ToCompact.
+const maxCompactCode = 32
+
+// compactKinds are the kinds encoded by ToCompact, by index. Other kinds are
+// encoded as KindUnknown.
+var compactKinds = []Kind{
+	KindUnknown,
+	KindInvalid,
+	KindNotFound,
+	KindAlreadyExists,
+	KindConflict,
+	KindUnauthenticated,
+	KindPermissionDenied,
+	KindRateLimited,
+	KindCanceled,
+	KindTimeout,
+	KindUnavailable,
+	KindInternal,
+}
+
+// ToCompact returns a compact binary encoding of err, at most MaxCompactSize
+// bytes long, for constrained channels such as LoRaWAN downlinks or MQTT
+// messages to devices: its kind, its fingerprint (see Fingerprint), its code
+// and its message, truncated to fit and followed by "..." if it was. Codes
+// longer than 32 bytes are left out. Channels carrying text only can carry
+// the base64 encoding of the result.
+//
+// The encoding is a version byte, the index of the kind, the 8 bytes of the
+// fingerprint, the length of the code followed by the code, and the message.
+// If err is nil, ToCompact returns nil.
+func ToCompact(err error) []byte {
+	if err == nil {
+		return nil
+	}
+	b := make([]byte, 2, MaxCompactSize)
+	b[0] = compactVersion
+	kind := KindOf(err)
+	for i, k := range compactKinds {
+		if k == kind {
+			b[1] = byte(i)
+		}
+	}
+	fp, _ := hex.DecodeString(Fingerprint(err))
+	b = append(b, fp...)
+	code := CodeOf(err)
+	if len(code) > maxCompactCode {
+		code = ""
+	}
+	b = append(append(b, byte(len(code))), code...)
+	msg := err.Error()
+	if len(msg) > MaxCompactSize-len(b) {
+		msg = truncateString(msg, MaxCompactSize-len(b))
+	}
+	return append(b, msg...)
+}
+
+// FromCompact decodes an error encoded by ToCompact: a *RemoteError with the
+// message, kind, code and fingerprint of the original error.
+func FromCompact(data []byte) (*RemoteError, error) {
+	if len(data) < 11 {
+		return nil, goerrors.New("errors: compact encoding too short")
+	}
+	if data[0] != compactVersion {
+		return nil, goerrors.New("errors: unknown compact encoding version")
+	}
+	if int(data[1]) >= len(compactKinds) {
+		return nil, goerrors.New("errors: invalid kind in compact encoding")
+	}
+	n := int(data[10])
+	if n > maxCompactCode || len(data) < 11+n {
+		return nil, goerrors.New("errors: invalid code in compact encoding")
+	}
+	msg := data[11+n:]
+	if !utf8.Valid(data[11:11+n]) || !utf8.Valid(msg) {
+		return nil, goerrors.New("errors: invalid UTF-8 in compact encoding")
+	}
+	return &RemoteError{
+		Message:     string(msg),
+		Kind:        compactKinds[data[1]],
+		Code:        Code(data[11 : 11+n]),
+		Fingerprint: hex.EncodeToString(data[2:10]),
+	}, nil
+}
@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	if got := ToCompact(nil); got != nil {
+		t.Errorf("ToCompact(nil) = %v, want nil", got)
+	}
+
+	err := WithCode(WithKind(Wrap(New("battery low"), "uplink rejected"), KindInvalid), "DEV-0042")
+	data := ToCompact(err)
+	got, decodeErr := FromCompact(data)
+	if decodeErr != nil {
+		t.Fatal(decodeErr)
+	}
+	if got.Message != "uplink rejected: battery low" || got.Kind != KindInvalid || got.Code != "DEV-0042" {
+		t.Errorf("FromCompact() = %+v", got)
+	}
+	if Fingerprint(got) != Fingerprint(err) {
+		t.Errorf("Fingerprint(FromCompact()) = %q, want %q", Fingerprint(got), Fingerprint(err))
+	}
+	if !Is(got, KindInvalid) {
+		t.Error("FromCompact() does not match its kind")
+	}
+
+	long := New(strings.Repeat("é", MaxCompactSize))
+	data = ToCompact(long)
+	if len(data) > MaxCompactSize {
+		t.Errorf("len(ToCompact()) = %d, want at most %d", len(data), MaxCompactSize)
+	}
+	if got, err := FromCompact(data); err != nil || !strings.HasSuffix(got.Message, "...") {
+		t.Errorf("FromCompact() = %v, %v, want a truncated message", got, err)
+	}
+
+	for _, data := range [][]byte{
+		nil,
+		{2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{1, 99, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 5, 'a'},
+		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff},
+	} {
+		if _, err := FromCompact(data); err == nil {
+			t.Errorf("FromCompact(%v): got no error", data)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package errors is a drop-in replacement of github.com/pkg/errors,
+// implemented with github.com/objenious/errors: it exposes the API of
+// pkg/errors, with the same messages, so that a codebase can migrate by
+// changing only its import paths:
+//
+//	import "github.com/objenious/errors/compat"
+//
+// and then move to github.com/objenious/errors package by package. For %+v
+// to print errors exactly as pkg/errors, call
+//
+//	errors.SetFormatCompat(errors.PkgErrors)
+//
+// of github.com/objenious/errors. The errors of this package are errors of
+// github.com/objenious/errors, that its functions, such as ToMap or KindOf,
+// accept.
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io"
+
+	"github.com/objenious/errors"
+)
+
+// Frame represents a program counter inside a stack frame.
+type Frame = errors.Frame
+
+// StackTrace is stack of Frames from innermost (newest) to outermost
+// (oldest).
+type StackTrace = errors.StackTrace
+
+// factory records stack traces from the callers of the functions of this
+// package.
+var factory = errors.NewFactory(errors.CallerSkip(1))
+
+// New returns an error with the supplied message.
+// New also records the stack trace at the point it was called.
+func New(message string) error {
+	return factory.New(message)
+}
+
+// Errorf formats according to a format specifier and returns the string
+// as a value that satisfies error.
+// Errorf also records the stack trace at the point it was called.
+func Errorf(format string, args ...interface{}) error {
+	return factory.Errorf(format, args...)
+}
+
+// WithStack annotates err with a stack trace at the point WithStack was called.
+// If err is nil, WithStack returns nil.
+func WithStack(err error) error {
+	return factory.WithStack(err)
+}
+
+// Wrap returns an error annotating err with a stack trace
+// at the point Wrap is called, and the supplied message.
+// If err is nil, Wrap returns nil.
+func Wrap(err error, message string) error {
+	return factory.Wrap(err, message)
+}
+
+// Wrapf returns an error annotating err with a stack trace
+// at the point Wrapf is called, and the format specifier.
+// If err is nil, Wrapf returns nil.
+func Wrapf(err error, format string, args ...interface{}) error {
+	return factory.Wrapf(err, format, args...)
+}
+
+// WithMessage annotates err with a new message.
+// If err is nil, WithMessage returns nil.
+func WithMessage(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return &withMessage{cause: err, msg: message}
+}
+
+// WithMessagef annotates err with the format specifier.
+// If err is nil, WithMessagef returns nil.
+func WithMessagef(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &withMessage{cause: err, msg: fmt.Sprintf(format, args...)}
+}
+
+type withMessage struct {
+	cause error
+	msg   string
+}
+
+func (w *withMessage) Error() string { return w.msg + ": " + w.cause.Error() }
+
+// Cause returns the annotated error
+func (w *withMessage) Cause() error { return w.cause }
+
+// Unwrap is the same as Cause
+func (w *withMessage) Unwrap() error { return w.cause }
+
+// Format prints the annotated error, followed by the message with %+v
+func (w *withMessage) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v\n", w.Cause())
+			io.WriteString(s, w.msg)
+			return
+		}
+		fallthrough
+	case 's', 'q':
+		io.WriteString(s, w.Error())
+	}
+}
+
+// Cause returns the underlying cause of the error, if possible.
+// An error value has a cause if it implements the following
+// interface:
+//
+//	type causer interface {
+//		Cause() error
+//	}
+//
+// If the error does not implement Cause, the original error will
+// be returned. If the error is nil, nil will be returned without further
+// investigation. Cause stops after errors.MaxDepth levels, on chains with a
+// cycle.
+func Cause(err error) error {
+	type causer interface {
+		Cause() error
+	}
+	for depth := 0; err != nil && depth < errors.MaxDepth; depth++ {
+		cause, ok := err.(causer)
+		if !ok || cause.Cause() == nil {
+			// the errors created by New have a nil cause: as with
+			// pkg/errors, they are the cause of the errors wrapping them.
+			break
+		}
+		err = cause.Cause()
+	}
+	return err
+}
+
+// Is reports whether any error in err's chain matches target, see errors.Is
+// of the standard library. It ends on chains with a cycle, see
+// errors.CheckCycle.
+func Is(err, target error) bool { return errors.Is(err, target) }
+
+// As finds the first error in err's chain that matches target, see errors.As
+// of the standard library. It ends on chains with a cycle, see
+// errors.CheckCycle.
+func As(err error, target interface{}) bool { return errors.As(err, target) }
+
+// Unwrap returns the result of calling the Unwrap method on err, if any, see
+// errors.Unwrap of the standard library.
+func Unwrap(err error) error { return goerrors.Unwrap(err) }
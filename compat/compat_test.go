@@ -0,0 +1,143 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/objenious/errors"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{{
+		New("error"),
+		"^error\n" +
+			"github.com/objenious/errors/compat.TestFormat\n" +
+			"\t.+/compat/compat_test.go:\\d+\n",
+	}, {
+		Errorf("error %d", 1),
+		"^error 1\n" +
+			"github.com/objenious/errors/compat.TestFormat\n",
+	}, {
+		Wrap(io.EOF, "read"),
+		"^EOF\n" +
+			"read\n" +
+			"github.com/objenious/errors/compat.TestFormat\n",
+	}, {
+		Wrapf(New("error"), "read %d", 1),
+		"^error\n" +
+			"github.com/objenious/errors/compat.TestFormat\n" +
+			"(.+\n)+" +
+			"read 1\n" +
+			"github.com/objenious/errors/compat.TestFormat\n",
+	}, {
+		WithStack(io.EOF),
+		"^EOF\n" +
+			"github.com/objenious/errors/compat.TestFormat\n",
+	}, {
+		WithMessage(New("error"), "read"),
+		"^error\n" +
+			"github.com/objenious/errors/compat.TestFormat\n" +
+			"(.+\n)+" +
+			"read$",
+	}, {
+		WithMessagef(io.EOF, "read %d", 1),
+		"^EOF\nread 1$",
+	}}
+	for i, tt := range tests {
+		got := fmt.Sprintf("%+v", tt.err)
+		if !regexp.MustCompile(tt.want).MatchString(got) {
+			t.Errorf("test %d: %%+v:\n got: %s\nwant: %s", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestMessages(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{WithMessage(Wrap(io.EOF, "read"), "load"), "load: read: EOF"},
+		{WithMessagef(io.EOF, "read %s", "config"), "read config: EOF"},
+		{fmt.Errorf("%s", WithMessage(io.EOF, "read")), "read: EOF"},
+		// as with pkg/errors, %q does not quote the messages of WithMessage.
+		{fmt.Errorf("%q", WithMessage(io.EOF, "read")), "read: EOF"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+	for _, fn := range []func() error{
+		func() error { return WithMessage(nil, "read") },
+		func() error { return WithMessagef(nil, "read") },
+		func() error { return Wrap(nil, "read") },
+		func() error { return Wrapf(nil, "read") },
+		func() error { return WithStack(nil) },
+	} {
+		if err := fn(); err != nil {
+			t.Errorf("got %v, want nil", err)
+		}
+	}
+}
+
+func TestCause(t *testing.T) {
+	root := New("error")
+	tests := []struct {
+		err, want error
+	}{
+		{nil, nil},
+		{io.EOF, io.EOF},
+		{root, root},
+		{Wrap(root, "read"), root},
+		{WithMessage(WithStack(io.EOF), "read"), io.EOF},
+	}
+	for i, tt := range tests {
+		if got := Cause(tt.err); got != tt.want {
+			t.Errorf("test %d: Cause(%v) = %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+	err := WithMessage(Wrap(io.EOF, "read"), "load")
+	if !Is(err, io.EOF) || Unwrap(Unwrap(err)) != io.EOF {
+		t.Errorf("Is and Unwrap do not follow the chain of %v", err)
+	}
+	var st interface{ StackTrace() StackTrace }
+	if !As(err, &st) || len(st.StackTrace()) == 0 {
+		t.Errorf("As does not find the stack trace of %v", err)
+	}
+	if errors.KindOf(errors.WithKind(err, errors.KindInternal)) != errors.KindInternal {
+		t.Errorf("errors of compat are not errors of github.com/objenious/errors")
+	}
+}
+
+// loopError is an error whose cause is set after it is wrapped.
+type loopError struct {
+	cause error
+}
+
+func (e *loopError) Error() string { return "loop" }
+
+func (e *loopError) Cause() error { return e.cause }
+
+func (e *loopError) Unwrap() error { return e.cause }
+
+func TestCauseCycle(t *testing.T) {
+	loop := &loopError{}
+	err := Wrap(loop, "read")
+	loop.cause = err
+	if got := Cause(err); got == nil {
+		t.Errorf("Cause() = nil")
+	}
+	if Is(err, io.EOF) {
+		t.Errorf("Is(io.EOF) = true")
+	}
+	var target *os.PathError
+	if As(err, &target) {
+		t.Errorf("As(*os.PathError) = true")
+	}
+}
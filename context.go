@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+)
+
+// factoryKey is the key of the Factory of contexts.
+type factoryKey struct{}
+
+// defaultFactory is the Factory of contexts without one.
+var defaultFactory = &Factory{}
+
+// NewContext returns a copy of ctx carrying f, so that the errors wrapped
+// by WrapCtx and WrapfCtx with the returned context, or contexts derived
+// from it, get the settings of f. This lets request-scoped settings, such as
+// fields identifying the tenant, reach errors created deep in the call tree.
+func NewContext(ctx context.Context, f *Factory) context.Context {
+	return context.WithValue(ctx, factoryKey{}, f)
+}
+
+// FactoryFromContext returns the Factory carried by ctx, or a Factory with
+// the settings of the process.
+func FactoryFromContext(ctx context.Context) *Factory {
+	if f, ok := ctx.Value(factoryKey{}).(*Factory); ok && f != nil {
+		return f
+	}
+	return defaultFactory
+}
+
+// WrapCtx is like Wrap, with the settings of the Factory carried by ctx, see
+// NewContext.
+func WrapCtx(ctx context.Context, err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	f := FactoryFromContext(ctx)
+	return wrapError(err, f.stack(), message, f)
+}
+
+// WrapfCtx is like Wrapf, with the settings of the Factory carried by ctx,
+// see NewContext.
+func WrapfCtx(ctx context.Context, err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	f := FactoryFromContext(ctx)
+	return wrapError(err, f.stack(), fmt.Sprintf(format, args...), f)
+}
@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWrapCtx(t *testing.T) {
+	ctx := context.Background()
+	if got := WrapCtx(ctx, nil, "read"); got != nil {
+		t.Errorf("WrapCtx(nil): got %#v, want nil", got)
+	}
+	if FactoryFromContext(ctx) == nil {
+		t.Fatalf("FactoryFromContext: got nil")
+	}
+	err := WrapCtx(ctx, io.EOF, "read")
+	if got := err.Error(); got != "read: EOF" || Fields(err) != nil {
+		t.Errorf("WrapCtx: got %q with fields %v", got, Fields(err))
+	}
+
+	f := NewFactory(StackDepth(1), DefaultFields(map[string]interface{}{"tenant": "canary", "request": 42}))
+	ctx = NewContext(ctx, f)
+	if got := FactoryFromContext(ctx); got != f {
+		t.Errorf("FactoryFromContext: got %p, want %p", got, f)
+	}
+	err = WrapfCtx(ctx, io.EOF, "read %d", 1)
+	if got := err.Error(); got != "read 1: EOF" {
+		t.Errorf("WrapfCtx: got %q", got)
+	}
+	if got := Fields(err); got["tenant"] != "canary" || got["request"] != 42 {
+		t.Errorf("WrapfCtx: got fields %v", got)
+	}
+	got := fmt.Sprintf("%+v", WrapCtx(ctx, io.EOF, "read"))
+	if !strings.HasPrefix(got, "EOF\nread\ngithub.com/objenious/errors.TestWrapCtx\n") || strings.Count(got, "\n\t") != 1 {
+		t.Errorf("WrapCtx: got %q, want one frame", got)
+	}
+	if got := Fields(f.New("boom")); got["tenant"] != "canary" {
+		t.Errorf("Factory.New: got fields %v", got)
+	}
+}
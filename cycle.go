@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MaxDepth bounds the depth of the chains walked by the package, so that
+// the walks of chains with cycles end. The packages walking chains of errors
+// themselves may stop at it too.
+const MaxDepth = 1 << 12
+
+// maxDepth is MaxDepth, for the walks of the package.
+const maxDepth = MaxDepth
+
+// ErrCycle is matched by errors.Is with the errors returned by CheckCycle and
+// Cause for chains with a cycle.
+const ErrCycle = StringError("errors: cycle in the chain of an error")
+
+// CheckCycle returns an error matching ErrCycle if the chain of err has a
+// cycle: an error wrapping one of the errors that wrap it, as errors of
+// other packages whose causes are set after they were wrapped may do. The
+// error tells where the cycle is:
+//
+//	errors: cycle in the chain of an error: *app.Error at depth 2 wraps the error at depth 0
+//
+// The walks of the package don't loop forever on such chains: those
+// following the first causes, such as KindOf or Fields, stop after 4096
+// levels, while ToMap, Dump and the formatting with %+v, among others, use
+// the error of CheckCycle instead of the chain. Cause, Is and As use it once
+// they walked 4096 errors without finding a match.
+// Otherwise, CheckCycle returns nil.
+func CheckCycle(err error) error {
+	if c := findCycle(err); c != nil {
+		return c
+	}
+	return nil
+}
+
+// cycleError describes a cycle found by findCycle.
+type cycleError struct {
+	typ      string // type of the error closing the cycle
+	depth    int    // depth of the error closing the cycle
+	ancestor int    // depth of the error it wraps
+}
+
+func (e *cycleError) Error() string {
+	return fmt.Sprintf("%s: %s at depth %d wraps the error at depth %d", ErrCycle, e.typ, e.depth, e.ancestor)
+}
+
+// Is reports whether target is ErrCycle.
+func (e *cycleError) Is(target error) bool {
+	return target == ErrCycle
+}
+
+// findCycle returns the first cycle of the chain of err, or nil. Only the
+// errors of comparable types are compared: a cycle has at least one, a
+// pointer.
+func findCycle(err error) *cycleError {
+	if err == nil {
+		return nil
+	}
+	var path [16]error
+	return walkCycle(err, path[:0])
+}
+
+// walkCycle returns the first cycle of the chain of err wrapped by the errors
+// of path.
+func walkCycle(err error, path []error) *cycleError {
+	if reflect.TypeOf(err).Comparable() {
+		for i, e := range path {
+			if e == err {
+				parent := path[len(path)-1]
+				return &cycleError{typ: fmt.Sprintf("%T", parent), depth: len(path) - 1, ancestor: i}
+			}
+		}
+	}
+	if len(path) >= maxDepth {
+		diagnose(Misuse{Kind: DepthExceeded, Err: path[0]})
+		return nil
+	}
+	path = append(path, err)
+	for _, cause := range causes(err) {
+		if c := walkCycle(cause, path); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// acyclic returns err, or the error of CheckCycle if its chain has a cycle,
+// for the walks of trees of errors.
+func acyclic(err error) error {
+	if c := findCycle(err); c != nil {
+		return c
+	}
+	return err
+}
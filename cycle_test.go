@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// loopError is an error whose cause is set after it is wrapped.
+type loopError struct {
+	causes []error
+}
+
+func (e *loopError) Error() string { return "loop" }
+
+func (e *loopError) Unwrap() []error { return e.causes }
+
+func TestCheckCycle(t *testing.T) {
+	if CheckCycle(nil) != nil || CheckCycle(Wrap(io.EOF, "read")) != nil {
+		t.Errorf("CheckCycle() of an error without cycle != nil")
+	}
+	shared := New("shared")
+	if err := CheckCycle(Join(shared, Wrap(shared, "again"))); err != nil {
+		t.Errorf("CheckCycle() of an error wrapped twice = %v", err)
+	}
+
+	loop := &loopError{}
+	err := WithField(Wrap(loop, "wrap"), "id", 1)
+	loop.causes = []error{err, io.EOF}
+	c := CheckCycle(err)
+	if got, want := fmt.Sprint(c), "errors: cycle in the chain of an error: *errors.loopError at depth 2 wraps the error at depth 0"; got != want {
+		t.Errorf("CheckCycle() = %q, want %q", got, want)
+	}
+	if !Is(c, ErrCycle) {
+		t.Errorf("CheckCycle() does not match ErrCycle")
+	}
+
+	// the walks of the package end.
+	if !Is(err, ErrCycle) || Is(err, io.EOF) {
+		t.Errorf("Is() does not match ErrCycle only")
+	}
+	if !Is(Join(io.ErrClosedPipe, err), ErrCycle) || !Is(Join(io.ErrClosedPipe, err), io.ErrClosedPipe) {
+		t.Errorf("Is() of a joined chain with a cycle")
+	}
+	if !Is(Cause(err), ErrCycle) {
+		t.Errorf("Cause() = %v", Cause(err))
+	}
+	var target *loopError
+	if !As(err, &target) || target != loop {
+		t.Errorf("As() did not find the error before the cycle")
+	}
+	var remote *RemoteError
+	if As(err, &remote) {
+		t.Errorf("As() matched an error not in the chain")
+	}
+	if got := Fields(err)["id"]; got != 1 {
+		t.Errorf("Fields() = %v", got)
+	}
+	if got := len(Messages(err)); got == 0 || got > maxDepth {
+		t.Errorf("Messages() has %d messages", got)
+	}
+	if got := ToMap(err)["message"]; got != c.Error() {
+		t.Errorf("ToMap() message = %v", got)
+	}
+	if got := Dump(err).Message; got != c.Error() {
+		t.Errorf("Dump() message = %v", got)
+	}
+	if GetStackTrace(err) != nil {
+		t.Errorf("GetStackTrace() != nil")
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, string(ErrCycle)) {
+		t.Errorf("%%+v = %s", got)
+	}
+	if MatchMessage(err, "wrap") || Ensure(err) == err {
+		t.Errorf("MatchMessage() or Ensure() walked the cycle")
+	}
+	_ = KindOf(err)
+	_ = IsRetryable(err)
+	_ = FindLast(err, HasField("id"))
+}
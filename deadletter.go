@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limits of the attributes built by DeadLetterAttributes, the strictest of
+// Pub/Sub and SQS.
+const (
+	maxDeadLetterAttrs    = 10
+	maxDeadLetterKeyLen   = 256
+	maxDeadLetterValueLen = 1024
+)
+
+// DeadLetterAttributes returns message attributes describing err, for
+// consumers to attach to the messages they send to a dead-letter queue, so
+// that triage tools can filter them without parsing their bodies:
+//
+//	error_message:     query devices: connection refused
+//	error_kind:        unavailable
+//	error_fingerprint: 7c5a0a1c0f4d3b2e
+//	error_retryable:   true
+//
+// The attributes are the message of err, its kind, code, reference ID and
+// fingerprint, whether it is retryable (see IsRetryable), its creation time,
+// and as many of its fields as fit, sorted by key, as "error_field_<key>".
+// They respect the limits of both Cloud Pub/Sub and Amazon SQS message
+// attributes: at most 10 attributes, keys of at most 256 letters, digits, '_'
+// and '-', and values of at most 1024 bytes, truncated with "...".
+// Empty values are left out.
+// If err is nil, DeadLetterAttributes returns nil.
+func DeadLetterAttributes(err error) map[string]string {
+	if err == nil {
+		return nil
+	}
+	attrs := make(map[string]string, maxDeadLetterAttrs)
+	add := func(key, value string) {
+		if value == "" || len(attrs) == maxDeadLetterAttrs {
+			return
+		}
+		if len(key) > maxDeadLetterKeyLen {
+			key = key[:maxDeadLetterKeyLen]
+		}
+		if len(value) > maxDeadLetterValueLen {
+			value = truncateString(value, maxDeadLetterValueLen)
+		}
+		attrs[key] = value
+	}
+	add("error_message", err.Error())
+	add("error_kind", string(KindOf(err)))
+	add("error_code", string(CodeOf(err)))
+	add("error_ref_id", RefID(err))
+	add("error_fingerprint", Fingerprint(err))
+	add("error_retryable", strconv.FormatBool(IsRetryable(err)))
+	if created, ok := CreatedAt(err); ok {
+		add("error_time", created.Format(time.RFC3339Nano))
+	}
+	fields := Fields(err)
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		add("error_field_"+attributeKey(key), fmt.Sprint(fields[key]))
+	}
+	return attrs
+}
+
+// attributeKey returns key with the characters not allowed in the names of
+// SQS message attributes replaced by '_'.
+func attributeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, key)
+}
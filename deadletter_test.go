@@ -0,0 +1,44 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDeadLetterAttributes(t *testing.T) {
+	if got := DeadLetterAttributes(nil); got != nil {
+		t.Errorf("DeadLetterAttributes(nil) = %v, want nil", got)
+	}
+
+	err := WithRetryable(WithKind(WithField(Wrap(io.EOF, "query devices"), "db.name", "devices"), KindUnavailable), true)
+	got := DeadLetterAttributes(err)
+	want := map[string]string{
+		"error_message":       "query devices: EOF",
+		"error_kind":          "unavailable",
+		"error_fingerprint":   Fingerprint(err),
+		"error_retryable":     "true",
+		"error_field_db_name": "devices",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DeadLetterAttributes() = %v, want %v", got, want)
+	}
+
+	fields := make(map[string]interface{})
+	for i := 0; i < 20; i++ {
+		fields[fmt.Sprintf("field%02d", i)] = i
+	}
+	err = WithFields(New(strings.Repeat("a", 2000)), fields)
+	got = DeadLetterAttributes(err)
+	if len(got) != 10 {
+		t.Errorf("len(DeadLetterAttributes()) = %d, want 10", len(got))
+	}
+	if msg := got["error_message"]; len(msg) != 1024 || !strings.HasSuffix(msg, "...") {
+		t.Errorf("error_message = %d bytes, want 1024 bytes, truncated", len(msg))
+	}
+	if got["error_field_field00"] != "0" {
+		t.Errorf("error_field_field00 = %q, want 0", got["error_field_field00"])
+	}
+}
@@ -0,0 +1,61 @@
+package errors
+
+import "strings"
+
+// DeviceContext identifies the LoRaWAN device and frame an error relates to,
+// see WithDevice.
+type DeviceContext struct {
+	// EUI is the DevEUI of the device, in upper case hexadecimal.
+	EUI string
+	// Port is the FPort of the frame.
+	Port uint8
+	// FCnt is the frame counter of the frame.
+	FCnt uint32
+}
+
+// Device fields of WithDevice.
+const (
+	fieldDevEUI = "dev_eui"
+	fieldFPort  = "f_port"
+	fieldFCnt   = "f_cnt"
+)
+
+// WithDevice annotates err with the device of EUI eui and the frame of port
+// and counter fcnt it relates to, as returned by Device. They are the fields
+// "dev_eui", "f_port" and "f_cnt", so that they are named the same by all
+// services. The EUI is normalized to upper case hexadecimal digits, without
+// separators.
+// If err is nil, WithDevice returns nil.
+func WithDevice(err error, eui string, port uint8, fcnt uint32) error {
+	if err == nil {
+		return nil
+	}
+	eui = strings.ToUpper(strings.NewReplacer("-", "", ":", "").Replace(eui))
+	err = WithField(err, fieldFCnt, fcnt)
+	err = WithField(err, fieldFPort, port)
+	return WithField(err, fieldDevEUI, eui)
+}
+
+// Device returns the device and frame the chain of err was annotated with by
+// WithDevice, according to the field merge policy, or false if it was not.
+// With the CollectAll policy, the outermost annotation is used.
+func Device(err error) (DeviceContext, bool) {
+	var d DeviceContext
+	eui, ok := deviceField(err, fieldDevEUI).(string)
+	if !ok {
+		return d, false
+	}
+	d.EUI = eui
+	d.Port, _ = deviceField(err, fieldFPort).(uint8)
+	d.FCnt, _ = deviceField(err, fieldFCnt).(uint32)
+	return d, true
+}
+
+// deviceField returns the value of the field key of err.
+func deviceField(err error, key string) interface{} {
+	v, _ := Field(err, key)
+	if values, ok := v.([]interface{}); ok {
+		return values[0]
+	}
+	return v
+}
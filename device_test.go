@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestWithDevice(t *testing.T) {
+	if err := WithDevice(nil, "70b3d57ed0001234", 1, 2); err != nil {
+		t.Errorf("WithDevice(nil) = %v, want nil", err)
+	}
+	if _, ok := Device(io.EOF); ok {
+		t.Errorf("Device(io.EOF) = true, want false")
+	}
+
+	err := WithDevice(io.EOF, "70-b3-d5-7e-d0-00-12-34", 10, 42)
+	want := DeviceContext{EUI: "70B3D57ED0001234", Port: 10, FCnt: 42}
+	if got, ok := Device(Wrap(err, "decode")); !ok || got != want {
+		t.Errorf("Device() = %+v, %t, want %+v", got, ok, want)
+	}
+	fields := Fields(err)
+	if fields["dev_eui"] != "70B3D57ED0001234" || fields["f_port"] != uint8(10) || fields["f_cnt"] != uint32(42) {
+		t.Errorf("Fields() = %v", fields)
+	}
+
+	outer := WithDevice(Wrap(err, "decode"), "0000000000000001", 2, 3)
+	want = DeviceContext{EUI: "0000000000000001", Port: 2, FCnt: 3}
+	for _, p := range []FieldMergePolicy{OuterWins, CollectAll} {
+		SetFieldMergePolicy(p)
+		if got, _ := Device(outer); got != want {
+			t.Errorf("policy %d: Device() = %+v, want %+v", p, got, want)
+		}
+	}
+	SetFieldMergePolicy(OuterWins)
+}
@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Misuse is a misuse of the package reported by the hook set by
+// SetDiagnostics.
+type Misuse struct {
+	// Kind is the kind of misuse.
+	Kind MisuseKind
+	// Err is the error misused: the new error for the misuses of Wrap,
+	// Wrapf and WithStack, and the error walked for DepthExceeded.
+	Err error
+	// Site is the call site of Wrap, Wrapf or WithStack, or the zero Frame
+	// for DepthExceeded.
+	Site Frame
+}
+
+// String returns the kind of m followed by its call site, if any.
+func (m Misuse) String() string {
+	if m.Site == 0 {
+		return string(m.Kind)
+	}
+	return fmt.Sprintf("%s at %v", m.Kind, m.Site)
+}
+
+// MisuseKind is a kind of Misuse.
+type MisuseKind string
+
+// Kinds of misuses. Those of Wrap, Wrapf and WithStack are also reported for
+// the functions like them: the methods of Factory, WrapCtx, WrapfCtx,
+// Timer.Wrap and FromExec.
+const (
+	// TypedNilCause is a typed nil, see IsNil, wrapped by Wrap, Wrapf or
+	// WithStack.
+	TypedNilCause MisuseKind = "typed_nil_cause"
+	// EmptyMessage is an empty message given to Wrap or Wrapf, which adds a
+	// separator to the message without adding a context.
+	EmptyMessage MisuseKind = "empty_message"
+	// FrozenCause is an error returned by Freeze wrapped by Wrap, Wrapf or
+	// WithStack: Freeze is meant for the errors crossing a boundary, whose
+	// chain is complete.
+	FrozenCause MisuseKind = "frozen_cause"
+	// DepthExceeded is a chain deeper than the 4096 errors the walks of the
+	// package go through, reported by the walks stopping there and by those
+	// checking the chain for a cycle, see CheckCycle.
+	DepthExceeded MisuseKind = "depth_exceeded"
+)
+
+// diagnostics holds the hook set by SetDiagnostics.
+var diagnostics atomic.Value
+
+type diagnosticsHook struct {
+	f func(Misuse)
+}
+
+// SetDiagnostics sets the hook called with the misuses of the package, to
+// find them in staging or in tests. The error of misuses with a typed nil
+// cause often panics when formatted, hooks should not format it. A nil hook
+// disables the reports, as by default.
+func SetDiagnostics(hook func(Misuse)) {
+	diagnostics.Store(diagnosticsHook{hook})
+}
+
+// diagnosing reports whether a hook is set by SetDiagnostics.
+func diagnosing() bool {
+	h, _ := diagnostics.Load().(diagnosticsHook)
+	return h.f != nil
+}
+
+// diagnose calls the hook set by SetDiagnostics with m, if any.
+func diagnose(m Misuse) {
+	if h, _ := diagnostics.Load().(diagnosticsHook); h.f != nil {
+		h.f(m)
+	}
+}
+
+// inDepth reports whether depth, the depth of a level of the chain of err
+// left to walk, is less than maxDepth, and reports a DepthExceeded misuse for
+// err otherwise: the walks following the first causes stop there.
+func inDepth(err error, depth int) bool {
+	if depth < maxDepth {
+		return true
+	}
+	diagnose(Misuse{Kind: DepthExceeded, Err: err})
+	return false
+}
+
+// wrapSite returns the call site of the function returning w.
+func wrapSite(w *withStack) Frame {
+	if w.stack == nil || len(*w.stack) == 0 {
+		return 0
+	}
+	return Frame((*w.stack)[0])
+}
+
+// checkMessage reports w if its message is empty.
+func checkMessage(w *withStack) {
+	if w.msg == "" {
+		diagnose(Misuse{Kind: EmptyMessage, Err: w, Site: wrapSite(w)})
+	}
+}
+
+// checkFrozen reports w if it wraps an error returned by Freeze.
+func checkFrozen(w *withStack) {
+	if _, ok := w.error.(*frozenError); ok {
+		diagnose(Misuse{Kind: FrozenCause, Err: w, Site: wrapSite(w)})
+	}
+}
@@ -0,0 +1,117 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestSetDiagnostics(t *testing.T) {
+	defer SetDiagnostics(nil)
+	var misuses []Misuse
+	SetDiagnostics(func(m Misuse) {
+		misuses = append(misuses, m)
+	})
+	_ = Wrap(io.EOF, "read")
+	_ = Wrap(typedNil(), "read")
+	_ = Wrapf(io.EOF, "")
+	_ = WithStack(Freeze(New("frozen")))
+	want := []MisuseKind{TypedNilCause, EmptyMessage, FrozenCause}
+	if len(misuses) != len(want) {
+		t.Fatalf("got %d misuses, want %d", len(misuses), len(want))
+	}
+	for i, m := range misuses {
+		if m.Kind != want[i] {
+			t.Errorf("misuse %d: got kind %q, want %q", i, m.Kind, want[i])
+		}
+		if _, ok := m.Err.(*withStack); !ok {
+			t.Errorf("misuse %d: got error %T", i, m.Err)
+		}
+		if got := fmt.Sprintf("%n", m.Site); got != "TestSetDiagnostics" {
+			t.Errorf("misuse %d: got site %q", i, got)
+		}
+	}
+	if got := misuses[1].String(); !strings.HasPrefix(got, "empty_message at diagnostics_test.go:") {
+		t.Errorf("String() = %q", got)
+	}
+
+	misuses = nil
+	err := io.EOF
+	for i := 0; i <= maxDepth; i++ {
+		err = WithStack(err)
+	}
+	var target *os.PathError
+	walks := map[string]func(){
+		"Is":       func() { _ = Is(err, io.EOF) },
+		"As":       func() { _ = As(err, &target) },
+		"Cause":    func() { _ = Cause(err) },
+		"KindOf":   func() { _ = KindOf(err) },
+		"Messages": func() { _ = Messages(err) },
+	}
+	for name, walk := range walks {
+		misuses = nil
+		walk()
+		if len(misuses) != 1 || misuses[0].Kind != DepthExceeded || misuses[0].Err != err {
+			t.Errorf("deep chain: %s got misuses %v", name, misuses)
+		}
+	}
+
+	SetDiagnostics(nil)
+	misuses = nil
+	_ = Wrap(io.EOF, "")
+	if len(misuses) != 0 {
+		t.Errorf("disabled: got misuses %v", misuses)
+	}
+}
+
+func TestDiagnosticsEntryPoints(t *testing.T) {
+	defer SetDiagnostics(nil)
+	defer SetDuplicateWrapDetection(false, nil)
+	var misuses []MisuseKind
+	SetDiagnostics(func(m Misuse) {
+		misuses = append(misuses, m.Kind)
+	})
+	var duplicates int
+	SetDuplicateWrapDetection(false, func(error, Frame) {
+		duplicates++
+	})
+	f := NewFactory()
+	ctx := NewContext(context.Background(), f)
+	tests := []struct {
+		name  string
+		wrap  func(err error, msg string) error
+		empty bool // whether an empty message can be given
+	}{
+		{"Factory.WithStack", func(err error, _ string) error { return f.WithStack(err) }, false},
+		{"Factory.Wrap", func(err error, msg string) error { return f.Wrap(err, msg) }, true},
+		{"Factory.Wrapf", func(err error, msg string) error { return f.Wrapf(err, msg) }, true},
+		{"WrapCtx", func(err error, msg string) error { return WrapCtx(ctx, err, msg) }, true},
+		{"WrapfCtx", func(err error, msg string) error { return WrapfCtx(ctx, err, msg) }, true},
+		{"Timer.Wrap", func(err error, msg string) error { return StartTimer().Wrap(err, msg) }, true},
+		{"FromExec", func(err error, _ string) error { return FromExec(exec.Command("true"), err, nil) }, false},
+	}
+	for _, tt := range tests {
+		misuses, duplicates = nil, 0
+		want := []MisuseKind{TypedNilCause, FrozenCause}
+		_ = tt.wrap(typedNil(), "read")
+		_ = tt.wrap(Freeze(New("frozen")), "read")
+		if tt.empty {
+			_ = tt.wrap(io.EOF, "")
+			want = append(want, EmptyMessage)
+		}
+		if fmt.Sprint(misuses) != fmt.Sprint(want) {
+			t.Errorf("%s: got misuses %v, want %v", tt.name, misuses, want)
+		}
+		err := io.EOF
+		for i := 0; i < 2; i++ {
+			err = tt.wrap(err, "read")
+		}
+		if duplicates != 1 {
+			t.Errorf("%s: got %d duplicate wraps, want 1", tt.name, duplicates)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToDOT returns the tree of err as a Graphviz digraph, to visualize the
+// failures aggregated by Join or ErrorsAndWarnings:
+//
+//	digraph error {
+//		node [shape=box];
+//		n0 [label="import batch"];
+//		n1 [label="2 errors"];
+//		n2 [label="invalid row 3\nkind: invalid"];
+//		n1 -> n2;
+//		...
+//	}
+//
+// Each level of the chain is a node labeled with its message, without the
+// message of its cause, and its kind and code if any. The errors joining
+// several errors are labeled with their number. Edges go from errors
+// to their causes, and dashed edges to warnings.
+// If err is nil, ToDOT returns an empty string.
+func ToDOT(err error) string {
+	if err == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("digraph error {\n\tnode [shape=box];\n")
+	n := 0
+	writeDOTNode(&b, ToMap(err), &n)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// writeDOTNode writes the node of the representation m of an error and of its
+// causes and warnings, numbered from *n, and returns its ID.
+func writeDOTNode(b *strings.Builder, m map[string]interface{}, n *int) string {
+	id := fmt.Sprintf("n%d", *n)
+	*n++
+	var children []map[string]interface{}
+	if cause, ok := m["cause"].(map[string]interface{}); ok {
+		children = append(children, cause)
+	}
+	if causes, ok := m["causes"].([]interface{}); ok {
+		for _, cause := range causes {
+			children = append(children, cause.(map[string]interface{}))
+		}
+	}
+
+	label := m["message"].(string)
+	msgs := make([]string, len(children))
+	for i, child := range children {
+		msgs[i] = child["message"].(string)
+	}
+	switch {
+	case len(children) == 1:
+		label = strings.TrimSuffix(label, ": "+msgs[0])
+	case len(children) > 1 && label == strings.Join(msgs, "\n"):
+		label = fmt.Sprintf("%d errors", len(children))
+	}
+	for _, key := range []string{"kind", "code"} {
+		if v, ok := m[key]; ok {
+			label += fmt.Sprintf("\n%s: %v", key, v)
+		}
+	}
+	fmt.Fprintf(b, "\t%s [label=%s];\n", id, dotQuote(label))
+
+	for _, child := range children {
+		fmt.Fprintf(b, "\t%s -> %s;\n", id, writeDOTNode(b, child, n))
+	}
+	if warnings, ok := m["warnings"].([]interface{}); ok {
+		for _, w := range warnings {
+			fmt.Fprintf(b, "\t%s -> %s [style=dashed];\n", id, writeDOTNode(b, w.(map[string]interface{}), n))
+		}
+	}
+	return id
+}
+
+// dotQuote returns s as a quoted DOT string, its newlines as line breaks.
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestToDOT(t *testing.T) {
+	if got := ToDOT(nil); got != "" {
+		t.Errorf("ToDOT(nil) = %q, want empty", got)
+	}
+
+	row := WithCode(WithKind(New(`invalid "row" 3`), KindInvalid), "ROW-1")
+	err := Wrap(Join(row, Wrap(io.EOF, "read")), "import batch")
+	want := `digraph error {
+	node [shape=box];
+	n0 [label="import batch"];
+	n1 [label="2 errors"];
+	n2 [label="invalid \"row\" 3\nkind: invalid\ncode: ROW-1"];
+	n1 -> n2;
+	n3 [label="read"];
+	n4 [label="EOF"];
+	n3 -> n4;
+	n1 -> n3;
+	n0 -> n1;
+}
+`
+	if got := ToDOT(err); got != want {
+		t.Errorf("ToDOT:\n got: %s\nwant: %s", got, want)
+	}
+
+	var ew ErrorsAndWarnings
+	ew.Fail(io.EOF)
+	ew.Warn(io.ErrShortWrite)
+	want = `digraph error {
+	node [shape=box];
+	n0 [label="EOF\nwarning: short write"];
+	n1 [label="EOF"];
+	n0 -> n1;
+	n2 [label="short write"];
+	n0 -> n2 [style=dashed];
+}
+`
+	if got := ToDOT(ew.Err()); got != want {
+		t.Errorf("ToDOT:\n got: %s\nwant: %s", got, want)
+	}
+}
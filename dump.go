@@ -0,0 +1,135 @@
+package errors
+
+import (
+	"strings"
+	"time"
+)
+
+// Node is a level of the tree of an error returned by Dump.
+type Node struct {
+	// Err is the error of the level.
+	Err error
+	// Message is the message added by the level: the message of a
+	// wrapper without the message of its cause, or the whole message of
+	// other errors.
+	Message string
+	// Attrs holds the values the level was annotated with, such as its
+	// kind or duration, under their key in ToMap, Fields its fields and
+	// Values its values set by WithValue, nil if none. The values are not
+	// converted to strings.
+	Attrs  map[string]interface{}
+	Fields map[string]interface{}
+	Values map[interface{}]interface{}
+	// Stack is the stack trace of the level, if any, and RemoteStack the
+	// serialized frames of a decoded error.
+	Stack       StackTrace
+	RemoteStack []string
+	// Time is the time the level was created, if recorded.
+	Time time.Time
+	// Children holds the causes of the level, and Warnings the warnings of
+	// the errors returned by ErrorsAndWarnings.Err.
+	Children []*Node
+	Warnings []*Node
+}
+
+// Dump returns the tree of err, for inspection tools building their own
+// views of errors: unlike ToMap, it keeps the values of annotations as they
+// are, and the stack traces as Frames.
+// If err is nil, Dump returns nil.
+func Dump(err error) *Node {
+	if err == nil {
+		return nil
+	}
+	return dump(acyclic(err))
+}
+
+func dump(err error) *Node {
+	if f, ok := err.(*frozenError); ok && f.err != nil {
+		return dump(f.err)
+	}
+	if _, ok := err.(*withAttr); ok {
+		// like in ToMap, attributes are listed with the error they annotate.
+		var attrs []*withAttr
+		for w, ok := err.(*withAttr); ok; w, ok = err.(*withAttr) {
+			attrs = append(attrs, w)
+			err = w.error
+		}
+		n := dump(err)
+		var fields map[string][]interface{}
+		for _, w := range attrs {
+			val := w.value
+			switch v := val.(type) {
+			case field:
+				if fields == nil {
+					fields = make(map[string][]interface{})
+				}
+				fields[v.key] = append(fields[v.key], v.value)
+				continue
+			case value:
+				if n.Values == nil {
+					n.Values = make(map[interface{}]interface{})
+				}
+				if _, ok := n.Values[v.key]; !ok {
+					n.Values[v.key] = v.value
+				}
+				continue
+			case payload:
+				val = v.data
+			}
+			if n.Attrs == nil {
+				n.Attrs = make(map[string]interface{})
+			}
+			if _, ok := n.Attrs[w.name]; !ok {
+				n.Attrs[w.name] = val
+			}
+		}
+		if fields != nil {
+			n.Fields = make(map[string]interface{}, len(fields))
+			for key, values := range fields {
+				n.Fields[key] = mergeField(values)
+			}
+		}
+		return n
+	}
+
+	n := &Node{Err: err, Message: err.Error()}
+	causes := causes(err)
+	switch e := err.(type) {
+	case *withStack:
+		n.Message = e.Message()
+		n.Stack = e.frames()
+		n.Time = e.created
+	case *RemoteError:
+		n.RemoteStack = e.Stack
+		n.Time = e.Time
+		attrs := make(map[string]interface{})
+		if e.Kind != KindUnknown {
+			attrs["kind"] = e.Kind
+		}
+		if e.Code != "" {
+			attrs["code"] = e.Code
+		}
+		if e.RefID != "" {
+			attrs["ref_id"] = e.RefID
+		}
+		if e.Fingerprint != "" {
+			attrs["fingerprint"] = e.Fingerprint
+		}
+		if len(attrs) > 0 {
+			n.Attrs = attrs
+		}
+	default:
+		if len(causes) == 1 {
+			n.Message = strings.TrimSuffix(n.Message, ": "+causes[0].Error())
+		}
+	}
+	for _, cause := range causes {
+		n.Children = append(n.Children, dump(cause))
+	}
+	if w, ok := err.(*withWarnings); ok {
+		for _, warning := range w.warnings {
+			n.Warnings = append(n.Warnings, dump(warning))
+		}
+	}
+	return n
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDump(t *testing.T) {
+	if n := Dump(nil); n != nil {
+		t.Errorf("Dump(nil) = %+v, want nil", n)
+	}
+
+	type message struct{ ID int }
+	inner := WithDuration(WithField(io.EOF, "msg", message{ID: 1}), time.Second)
+	err := Freeze(WithKind(Wrap(inner, "read"), KindUnavailable))
+	n := Dump(err)
+	if _, ok := n.Err.(*withStack); !ok || n.Message != "read" || len(n.Stack) == 0 {
+		t.Errorf("Dump() = %+v", n)
+	}
+	if want := map[string]interface{}{"kind": KindUnavailable}; !reflect.DeepEqual(n.Attrs, want) || n.Fields != nil {
+		t.Errorf("Dump().Attrs = %v, Fields = %v", n.Attrs, n.Fields)
+	}
+	if len(n.Children) != 1 {
+		t.Fatalf("Dump().Children = %v", n.Children)
+	}
+	c := n.Children[0]
+	if c.Err != io.EOF || c.Message != "EOF" || c.Stack != nil || c.Children != nil {
+		t.Errorf("child = %+v", c)
+	}
+	if !reflect.DeepEqual(c.Attrs, map[string]interface{}{"duration": time.Second}) {
+		t.Errorf("child Attrs = %v", c.Attrs)
+	}
+	if !reflect.DeepEqual(c.Fields, map[string]interface{}{"msg": message{ID: 1}}) {
+		t.Errorf("child Fields = %v", c.Fields)
+	}
+}
+
+func TestDumpTree(t *testing.T) {
+	var ew ErrorsAndWarnings
+	ew.Fail(WithPayload(io.EOF, []byte{1, 2}, 0))
+	ew.Fail(WithStack(io.ErrUnexpectedEOF))
+	ew.Warn(io.ErrShortWrite)
+	n := Dump(ew.Err())
+	if len(n.Children) != 2 || len(n.Warnings) != 1 || n.Warnings[0].Err != io.ErrShortWrite {
+		t.Fatalf("Dump() = %+v", n)
+	}
+	if got := n.Children[0].Attrs["payload"]; !reflect.DeepEqual(got, []byte{1, 2}) {
+		t.Errorf("payload = %v", got)
+	}
+	if c := n.Children[1]; c.Message != "" || len(c.Stack) == 0 || len(c.Children) != 1 {
+		t.Errorf("WithStack level = %+v", c)
+	}
+
+	remote := &RemoteError{Message: "boom", Stack: []string{"main.main main.go:1"}, Kind: KindInternal, Causes: []error{io.EOF}}
+	n = Dump(Wrap(remote, "call"))
+	r := n.Children[0]
+	if n.Message != "call" || r.Message != "boom" || !reflect.DeepEqual(r.RemoteStack, remote.Stack) ||
+		!reflect.DeepEqual(r.Attrs, map[string]interface{}{"kind": KindInternal}) || len(r.Children) != 1 {
+		t.Errorf("Dump() = %+v, remote = %+v", n, r)
+	}
+}
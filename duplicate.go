@@ -0,0 +1,58 @@
+package errors
+
+import "sync/atomic"
+
+// duplicateDetection holds the settings of SetDuplicateWrapDetection.
+var duplicateDetection atomic.Value
+
+type duplicateSettings struct {
+	enabled  bool
+	coalesce bool
+	hook     func(err error, site Frame)
+}
+
+// SetDuplicateWrapDetection enables the detection of errors wrapped by Wrap,
+// Wrapf or WithStack, or the functions like them such as the methods of
+// Factory, at a call site that already wrapped them, typically in
+// retry loops wrapping the same error at each attempt, which makes chains
+// grow without bound. When a duplicate wrap is detected, hook is called with
+// the new error and the call site, if not nil, and the new wrapper is
+// dropped if coalesce is true: the error is returned as is. Detection is
+// disabled by default, and when coalesce is false and hook is nil.
+func SetDuplicateWrapDetection(coalesce bool, hook func(err error, site Frame)) {
+	duplicateDetection.Store(duplicateSettings{
+		enabled:  coalesce || hook != nil,
+		coalesce: coalesce,
+		hook:     hook,
+	})
+}
+
+// dedupe returns w, or its cause if w is a duplicate wrap to coalesce. It
+// also reports the typed nils wrapped by w, see SetTypedNilDetection, and
+// the frozen errors wrapped by w, see SetDiagnostics.
+func dedupe(w *withStack) error {
+	checkNil(w)
+	checkFrozen(w)
+	d, _ := duplicateDetection.Load().(duplicateSettings)
+	if !d.enabled || len(*w.stack) == 0 {
+		return w
+	}
+	pc := (*w.stack)[0]
+	for err, depth := w.error, 0; err != nil && inDepth(w.error, depth); depth++ {
+		if prev, ok := err.(*withStack); ok && prev.wrapped && prev.stack != nil && len(*prev.stack) > 0 && (*prev.stack)[0] == pc {
+			if d.hook != nil {
+				d.hook(w, Frame(pc))
+			}
+			if d.coalesce {
+				return w.error
+			}
+			return w
+		}
+		causes := causes(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return w
+}
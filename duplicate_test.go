@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func retryWraps(n int) error {
+	err := io.EOF
+	for i := 0; i < n; i++ {
+		err = Wrapf(err, "attempt %d", i+1)
+	}
+	return err
+}
+
+func TestSetDuplicateWrapDetection(t *testing.T) {
+	defer SetDuplicateWrapDetection(false, nil)
+	if got := retryWraps(3).Error(); got != "attempt 3: attempt 2: attempt 1: EOF" {
+		t.Errorf("disabled: got %q", got)
+	}
+
+	var sites []string
+	SetDuplicateWrapDetection(false, func(err error, site Frame) {
+		sites = append(sites, fmt.Sprintf("%n", site))
+	})
+	if got := retryWraps(3).Error(); got != "attempt 3: attempt 2: attempt 1: EOF" {
+		t.Errorf("hook: got %q", got)
+	}
+	if len(sites) != 2 || sites[0] != "retryWraps" {
+		t.Errorf("hook: got sites %q, want retryWraps twice", sites)
+	}
+
+	SetDuplicateWrapDetection(true, nil)
+	if got := retryWraps(3).Error(); got != "attempt 1: EOF" {
+		t.Errorf("coalesce: got %q, want \"attempt 1: EOF\"", got)
+	}
+	if got := Wrap(Wrap(io.EOF, "a"), "b").Error(); got != "b: a: EOF" {
+		t.Errorf("coalesce: got %q for different call sites", got)
+	}
+}
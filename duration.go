@@ -0,0 +1,56 @@
+package errors
+
+import "time"
+
+// WithDuration annotates err with the duration of the operation that
+// failed, so that timeouts can be told apart from immediate failures. The
+// duration is returned by Duration and included by ToMap.
+// If err is nil, WithDuration returns nil.
+func WithDuration(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &withAttr{error: err, name: "duration", value: d}
+}
+
+// Duration returns the duration err was annotated with by WithDuration or a
+// Timer, if any.
+func Duration(err error) (time.Duration, bool) {
+	d, ok := lookupAttr(err, "duration")
+	if !ok {
+		return 0, false
+	}
+	return d.(time.Duration), true
+}
+
+// Timer measures the duration of an operation, to annotate the errors it
+// returns:
+//
+//	t := errors.StartTimer()
+//	rows, err := db.Query(query)
+//	if err != nil {
+//		return t.Wrap(err, "query")
+//	}
+type Timer struct {
+	start time.Time
+}
+
+// StartTimer returns a Timer started now.
+func StartTimer() Timer {
+	return Timer{start: time.Now()}
+}
+
+// Elapsed returns the time elapsed since the Timer was started.
+func (t Timer) Elapsed() time.Duration {
+	return time.Since(t.start)
+}
+
+// Wrap is like Wrap, and also annotates err with the time elapsed since the
+// Timer was started.
+// If err is nil, Wrap returns nil.
+func (t Timer) Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return WithDuration(wrapError(err, callers(), message, nil), t.Elapsed())
+}
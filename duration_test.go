@@ -0,0 +1,55 @@
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWithDuration(t *testing.T) {
+	if got := WithDuration(nil, time.Second); got != nil {
+		t.Errorf("WithDuration(nil): got %#v, want nil", got)
+	}
+	if _, ok := Duration(io.EOF); ok {
+		t.Errorf("Duration(io.EOF): got a duration")
+	}
+
+	err := Wrap(WithDuration(io.EOF, 1500*time.Millisecond), "query")
+	if d, ok := Duration(err); !ok || d != 1500*time.Millisecond {
+		t.Errorf("Duration: got %v, %t, want 1.5s", d, ok)
+	}
+	if got, want := err.Error(), "query: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if !goerrors.Is(err, io.EOF) || Cause(err) != io.EOF {
+		t.Errorf("WithDuration hides its cause")
+	}
+	if got := ToMap(err)["cause"].(map[string]interface{})["duration"]; got != "1.5s" {
+		t.Errorf("ToMap: got duration %v, want 1.5s", got)
+	}
+	testFormatRegexp(t, 0, WithDuration(Wrap(io.EOF, "read"), time.Second), "%+v", "EOF\n"+
+		"read\n"+
+		"github.com/objenious/errors.TestWithDuration\n"+
+		"\t.+/github.com/objenious/errors/duration_test.go:32")
+	if got := fmt.Sprintf("%q", WithDuration(io.EOF, time.Second)); got != `"EOF"` {
+		t.Errorf("%%q: got %s, want \"EOF\"", got)
+	}
+}
+
+func TestTimer(t *testing.T) {
+	timer := StartTimer()
+	if got := timer.Wrap(nil, "query"); got != nil {
+		t.Errorf("Timer.Wrap(nil): got %#v, want nil", got)
+	}
+	time.Sleep(10 * time.Millisecond)
+	err := timer.Wrap(io.EOF, "query")
+	if d, ok := Duration(err); !ok || d < 10*time.Millisecond || d > timer.Elapsed() {
+		t.Errorf("Duration(Timer.Wrap()): got %v, %t", d, ok)
+	}
+	testFormatRegexp(t, 0, err, "%+v", "EOF\n"+
+		"query\n"+
+		"github.com/objenious/errors.TestTimer\n"+
+		"\t.+/github.com/objenious/errors/duration_test.go:47")
+}
@@ -0,0 +1,54 @@
+package errors
+
+// Ensure returns err with a stack trace, for the layers receiving errors from
+// third-party packages, at the boundaries of an application: if no error of
+// the chain of err carries a stack trace, local or remote, err is annotated
+// with the stack trace at the point Ensure was called, as by WithStack.
+// Otherwise, err is returned as-is, so that the traces recorded deeper are
+// not hidden by the one of the boundary.
+// If err is nil, Ensure returns nil.
+func Ensure(err error) error {
+	if err == nil || hasStack(acyclic(err)) {
+		return err
+	}
+	return &withStack{
+		error:   err,
+		stack:   callers(),
+		created: timestamp(),
+		wrapped: true,
+	}
+}
+
+// Ensure is like Ensure, with the settings of f.
+func (f *Factory) Ensure(err error) error {
+	if err == nil || hasStack(acyclic(err)) {
+		return err
+	}
+	return f.annotate(&withStack{
+		error:   err,
+		stack:   f.stack(),
+		created: timestamp(),
+		wrapped: true,
+		factory: f,
+	})
+}
+
+// hasStack reports whether an error of the chain of err, or of its causes,
+// carries a stack trace, local or remote. This includes the errors of other
+// packages implementing StackTracer, such as those of github.com/pkg/errors.
+func hasStack(err error) bool {
+	switch e := err.(type) {
+	case StackTracer:
+		return true
+	case *RemoteError:
+		if len(e.Stack) > 0 {
+			return true
+		}
+	}
+	for _, cause := range causes(err) {
+		if hasStack(cause) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestEnsure(t *testing.T) {
+	if err := Ensure(nil); err != nil {
+		t.Errorf("Ensure(nil) = %v, want nil", err)
+	}
+
+	plain := fmt.Errorf("decode: %w", io.ErrUnexpectedEOF)
+	err := Ensure(plain)
+	if err.Error() != plain.Error() || !Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Ensure() = %v, want %v", err, plain)
+	}
+	if got := fmt.Sprintf("%v", GetStackTrace(err).StackTrace()[0]); got != "ensure_test.go:15" {
+		t.Errorf("Ensure() stack starts at %s, want ensure_test.go:15", got)
+	}
+
+	for _, traced := range []error{
+		err,
+		fmt.Errorf("call: %w", New("boom")),
+		Join(io.EOF, New("boom")),
+		&RemoteError{Message: "boom", Stack: []string{"main.main main.go:1"}},
+	} {
+		if got := Ensure(traced); got != traced {
+			t.Errorf("Ensure(%v) = %v, want it unchanged", traced, got)
+		}
+	}
+
+	f := NewFactory(DefaultFields(map[string]interface{}{"service": "api"}))
+	err = f.Ensure(io.EOF)
+	if v, _ := Field(err, "service"); v != "api" || GetStackTrace(err) == nil {
+		t.Errorf("Factory.Ensure() = %+v, want a stack trace and the default fields", err)
+	}
+}
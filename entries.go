@@ -0,0 +1,62 @@
+package errors
+
+// Entry is a level of the chain of an error adding a message, as returned by
+// Entries.
+type Entry struct {
+	// Msg is the message added by the level, see Messages.
+	Msg string
+	// Stack is the stack trace recorded by the level or, failing that, by
+	// the outermost of the levels above it adding no message, if any.
+	Stack StackTrace
+	// Fields are the fields set by the levels above it adding no message,
+	// such as WithField, if any.
+	Fields map[string]interface{}
+}
+
+// Entries returns the levels of the chain of err adding a message, from the
+// outermost to the innermost, following the first cause of each level, with
+// the stack trace and fields attached to each of them. The stacks recorded
+// and fields set by levels adding no message, such as WithStack and
+// WithField, are attributed to the next level adding one, the outermost
+// value of a field winning. Like Messages, messages composed with
+// fmt.Errorf are not repeated.
+// If err is nil, Entries returns nil.
+func Entries(err error) []Entry {
+	var (
+		entries []Entry
+		next    Entry
+	)
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		causes := causes(err)
+		msg := levelMessage(err, causes)
+		switch e := err.(type) {
+		case *withAttr:
+			if f, ok := e.value.(field); ok {
+				if next.Fields == nil {
+					next.Fields = make(map[string]interface{})
+				}
+				if _, ok := next.Fields[f.key]; !ok {
+					next.Fields[f.key] = f.value
+				}
+			}
+		case *withStack:
+			if msg != "" || next.Stack == nil {
+				next.Stack = e.frames()
+			}
+		}
+		if msg != "" {
+			next.Msg = msg
+			entries = append(entries, next)
+			next = Entry{}
+		}
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	if next.Stack != nil || next.Fields != nil {
+		entries = append(entries, next)
+	}
+	return entries
+}
@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestEntries(t *testing.T) {
+	if got := Entries(nil); got != nil {
+		t.Errorf("Entries(nil) = %v, want nil", got)
+	}
+
+	inner := New("boom")
+	stacked := WithStack(fmt.Errorf("read: %w", inner))
+	wrapped := Wrap(WithField(stacked, "file", "a.txt"), "load")
+	err := WithField(WithField(wrapped, "user", 1), "user", 2)
+
+	got := Entries(err)
+	want := []Entry{
+		{Msg: "load", Stack: wrapped.(*withStack).frames(), Fields: map[string]interface{}{"user": 2}},
+		{Msg: "read", Stack: stacked.(*withStack).frames(), Fields: map[string]interface{}{"file": "a.txt"}},
+		{Msg: "boom", Stack: inner.(*withStack).frames()},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %+v, want %+v", got, want)
+	}
+
+	got = Entries(WithField(io.EOF, "key", "value"))
+	want = []Entry{{Msg: "EOF", Fields: map[string]interface{}{"key": "value"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,286 @@
+// Package errgrpc integrates github.com/objenious/errors with gRPC: its
+// interceptors convert the errors returned by servers to gRPC statuses, and
+// the statuses received by clients back to errors, annotated with the method
+// called and the peer.
+package errgrpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/objenious/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// detailsKey is the trailer metadata key of the representation of errors
+// sent by servers with SendDetails.
+const detailsKey = "x-error-bin"
+
+// kindCodes maps kinds to gRPC codes.
+var kindCodes = map[errors.Kind]codes.Code{
+	errors.KindInvalid:          codes.InvalidArgument,
+	errors.KindNotFound:         codes.NotFound,
+	errors.KindAlreadyExists:    codes.AlreadyExists,
+	errors.KindConflict:         codes.Aborted,
+	errors.KindUnauthenticated:  codes.Unauthenticated,
+	errors.KindPermissionDenied: codes.PermissionDenied,
+	errors.KindRateLimited:      codes.ResourceExhausted,
+	errors.KindCanceled:         codes.Canceled,
+	errors.KindTimeout:          codes.DeadlineExceeded,
+	errors.KindUnavailable:      codes.Unavailable,
+	errors.KindInternal:         codes.Internal,
+}
+
+// codeKinds maps gRPC codes to kinds.
+var codeKinds = map[codes.Code]errors.Kind{
+	codes.InvalidArgument:    errors.KindInvalid,
+	codes.OutOfRange:         errors.KindInvalid,
+	codes.FailedPrecondition: errors.KindInvalid,
+	codes.NotFound:           errors.KindNotFound,
+	codes.AlreadyExists:      errors.KindAlreadyExists,
+	codes.Aborted:            errors.KindConflict,
+	codes.Unauthenticated:    errors.KindUnauthenticated,
+	codes.PermissionDenied:   errors.KindPermissionDenied,
+	codes.ResourceExhausted:  errors.KindRateLimited,
+	codes.Canceled:           errors.KindCanceled,
+	codes.DeadlineExceeded:   errors.KindTimeout,
+	codes.Unavailable:        errors.KindUnavailable,
+	codes.Internal:           errors.KindInternal,
+	codes.DataLoss:           errors.KindInternal,
+}
+
+// Option configures the server interceptors.
+type Option func(*options)
+
+type options struct {
+	details bool
+	report  func(error)
+}
+
+// SendDetails makes the server interceptors send the representation of the
+// errors built by errors.ToJSON in the trailer metadata, so that the client
+// interceptors rebuild their whole chain, with their fields and stack traces.
+// It should only be enabled between services trusting each other.
+func SendDetails() Option {
+	return func(o *options) { o.details = true }
+}
+
+// Report sets a function the server interceptors call with the errors
+// returned by handlers, annotated with the fields "method" and "peer", before
+// converting them to statuses.
+func Report(report func(error)) Option {
+	return func(o *options) { o.report = report }
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// UnaryServerInterceptor returns an interceptor converting the errors
+// returned by unary handlers to statuses, see ToStatus.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		st, trailer := o.convert(ctx, info.FullMethod, err)
+		if trailer != nil {
+			_ = grpc.SetTrailer(ctx, trailer)
+		}
+		return resp, st.Err()
+	}
+}
+
+// StreamServerInterceptor returns an interceptor converting the errors
+// returned by stream handlers to statuses, see ToStatus.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		if err == nil {
+			return nil
+		}
+		st, trailer := o.convert(ss.Context(), info.FullMethod, err)
+		if trailer != nil {
+			ss.SetTrailer(trailer)
+		}
+		return st.Err()
+	}
+}
+
+// convert returns the status and the trailer metadata sending err, returned
+// by the handler of method.
+func (o *options) convert(ctx context.Context, method string, err error) (*status.Status, metadata.MD) {
+	var trailer metadata.MD
+	if o.details {
+		if data, jsonErr := errors.ToJSON(err); jsonErr == nil {
+			trailer = metadata.Pairs(detailsKey, string(data))
+		}
+	}
+	p, _ := peer.FromContext(ctx)
+	err = annotate(err, method, p)
+	if o.report != nil {
+		o.report(err)
+	}
+	return ToStatus(err), trailer
+}
+
+// UnaryClientInterceptor returns an interceptor converting the statuses
+// received by unary calls to errors, see FromStatus, annotated with the
+// fields "method" and "peer".
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var (
+			trailer metadata.MD
+			p       peer.Peer
+		)
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Trailer(&trailer), grpc.Peer(&p))...)
+		if err == nil {
+			return nil
+		}
+		return annotate(fromError(err, trailer), method, &p)
+	}
+}
+
+// StreamClientInterceptor returns an interceptor converting the statuses
+// received by streams to errors, see FromStatus, annotated with the fields
+// "method" and "peer". The io.EOF errors ending streams are returned as-is.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		p := &peer.Peer{}
+		cs, err := streamer(ctx, desc, cc, method, append(opts, grpc.Peer(p))...)
+		if err != nil {
+			return nil, annotate(fromError(err, nil), method, p)
+		}
+		return &clientStream{ClientStream: cs, method: method, peer: p}, nil
+	}
+}
+
+// clientStream is a stream converting the statuses it receives to errors.
+type clientStream struct {
+	grpc.ClientStream
+	method string
+	peer   *peer.Peer
+}
+
+// SendMsg sends m, converting the status received to an error
+func (s *clientStream) SendMsg(m interface{}) error {
+	return s.convert(s.ClientStream.SendMsg(m))
+}
+
+// RecvMsg receives m, converting the status received to an error
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return s.convert(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) convert(err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+	return annotate(fromError(err, s.Trailer()), s.method, s.peer)
+}
+
+// fromError converts err, returned by a call, to an error, if it has a
+// status.
+func fromError(err error, trailer metadata.MD) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return FromStatus(st, trailer)
+}
+
+// annotate annotates err with the fields "method" and "peer".
+func annotate(err error, method string, p *peer.Peer) error {
+	err = errors.WithField(err, "method", method)
+	if p != nil && p.Addr != nil {
+		err = errors.WithField(err, "peer", p.Addr.String())
+	}
+	return err
+}
+
+// ToStatus returns the status corresponding to err, with the message of err
+// and the code given by, in order:
+//
+//   - the kind err is annotated with, see errors.KindOf,
+//   - the status of the chain of err, if it wraps one,
+//   - the kind inferred from the chain of err, see errors.InferKind,
+//
+// or codes.Unknown. err is checked by errors.CheckPolicy. If err is nil,
+// ToStatus returns an OK status.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	errors.CheckPolicy(err, "errgrpc.ToStatus")
+	return status.New(Code(err), err.Error())
+}
+
+// Code returns the code of the status corresponding to err, see ToStatus.
+func Code(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	if code, ok := kindCodes[errors.KindOf(err)]; ok {
+		return code
+	}
+	if st, ok := status.FromError(err); ok {
+		return st.Code()
+	}
+	if code, ok := kindCodes[errors.InferKind(err)]; ok {
+		return code
+	}
+	return codes.Unknown
+}
+
+// FromStatus returns the error described by st and the trailer metadata
+// received with it: a *errors.RemoteError, rebuilt with its whole chain if
+// the server sent its representation (see SendDetails), or with the message
+// of st otherwise. Its kind is the one matching the code of st, unless the
+// representation has one. The error still has the status st, for
+// status.FromError and status.Code.
+// If st is nil or OK, FromStatus returns nil.
+func FromStatus(st *status.Status, trailer metadata.MD) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	var remote *errors.RemoteError
+	if values := trailer.Get(detailsKey); len(values) > 0 {
+		remote, _ = errors.FromJSON([]byte(values[0]))
+	}
+	if remote == nil {
+		remote = &errors.RemoteError{Message: st.Message()}
+	}
+	if remote.Kind == errors.KindUnknown {
+		remote.Kind = codeKinds[st.Code()]
+	}
+	return &statusError{remote: remote, st: st}
+}
+
+// statusError is a remote error received with a status.
+type statusError struct {
+	remote *errors.RemoteError
+	st     *status.Status
+}
+
+func (e *statusError) Error() string { return e.remote.Error() }
+
+// Unwrap returns the remote error
+func (e *statusError) Unwrap() error { return e.remote }
+
+// GRPCStatus returns the status the error was received with
+func (e *statusError) GRPCStatus() *status.Status { return e.st }
+
+// Format formats the remote error
+func (e *statusError) Format(s fmt.State, verb rune) { e.remote.Format(s, verb) }
@@ -0,0 +1,158 @@
+package errgrpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/objenious/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// healthServer fails every call with err.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	err error
+}
+
+func (s *healthServer) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	return nil, s.err
+}
+
+func (s *healthServer) Watch(*healthpb.HealthCheckRequest, healthpb.Health_WatchServer) error {
+	return s.err
+}
+
+func dial(t *testing.T, handlerErr error, opts ...Option) (healthpb.HealthClient, func()) {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(opts...)),
+		grpc.StreamInterceptor(StreamServerInterceptor(opts...)),
+	)
+	healthpb.RegisterHealthServer(srv, &healthServer{err: handlerErr})
+	go func() { _ = srv.Serve(lis) }()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(StreamClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return healthpb.NewHealthClient(conn), func() {
+		_ = conn.Close()
+		srv.Stop()
+	}
+}
+
+func TestUnary(t *testing.T) {
+	var reported error
+	handlerErr := errors.WithKind(errors.New("device not found"), errors.KindNotFound)
+	client, stop := dial(t, handlerErr, Report(func(err error) { reported = err }))
+	defer stop()
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if got := status.Code(err); got != codes.NotFound {
+		t.Errorf("status.Code(): got %v, want %v", got, codes.NotFound)
+	}
+	if got := errors.KindOf(err); got != errors.KindNotFound {
+		t.Errorf("KindOf(): got %q, want %q", got, errors.KindNotFound)
+	}
+	var remote *errors.RemoteError
+	if !errors.As(err, &remote) || remote.Message != "device not found" {
+		t.Errorf("As(*RemoteError): got %v", remote)
+	}
+	if v, _ := errors.Field(err, "method"); v != "/grpc.health.v1.Health/Check" {
+		t.Errorf("client method: got %v", v)
+	}
+	if v, _ := errors.Field(err, "peer"); v != "bufconn" {
+		t.Errorf("client peer: got %v", v)
+	}
+	if v, _ := errors.Field(reported, "method"); v != "/grpc.health.v1.Health/Check" {
+		t.Errorf("server method: got %v", v)
+	}
+	if !errors.Is(reported, errors.KindNotFound) {
+		t.Errorf("reported: got %v", reported)
+	}
+}
+
+func TestSendDetails(t *testing.T) {
+	handlerErr := errors.Wrap(errors.WithField(errors.New("connection refused"), "db", "devices"), "query devices")
+	client, stop := dial(t, handlerErr, SendDetails())
+	defer stop()
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if got := status.Code(err); got != codes.Unknown {
+		t.Errorf("status.Code(): got %v, want %v", got, codes.Unknown)
+	}
+	if got := errors.Messages(err); len(got) != 2 || got[0] != "query devices" || got[1] != "connection refused" {
+		t.Errorf("Messages(): got %q", got)
+	}
+	var remote *errors.RemoteError
+	if !errors.As(err, &remote) || len(remote.Stack) == 0 {
+		t.Errorf("As(*RemoteError): got no remote stack")
+	}
+}
+
+func TestStream(t *testing.T) {
+	client, stop := dial(t, errors.WithKind(errors.New("overloaded"), errors.KindUnavailable))
+	defer stop()
+
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = stream.Recv()
+	if got := status.Code(err); got != codes.Unavailable {
+		t.Errorf("status.Code(): got %v, want %v", got, codes.Unavailable)
+	}
+	if got := errors.KindOf(err); got != errors.KindUnavailable {
+		t.Errorf("KindOf(): got %q, want %q", got, errors.KindUnavailable)
+	}
+	if v, _ := errors.Field(err, "method"); v != "/grpc.health.v1.Health/Watch" {
+		t.Errorf("method: got %v", v)
+	}
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{nil, codes.OK},
+		{errors.New("boom"), codes.Unknown},
+		{errors.WithKind(errors.New("boom"), errors.KindInvalid), codes.InvalidArgument},
+		{errors.WithKind(errors.New("boom"), errors.Kind("quota_exceeded")), codes.Unknown},
+		{errors.WithKind(status.Error(codes.ResourceExhausted, "boom"), errors.Kind("quota_exceeded")), codes.ResourceExhausted},
+		{errors.Wrap(status.Error(codes.FailedPrecondition, "boom"), "call"), codes.FailedPrecondition},
+		{errors.Wrap(context.DeadlineExceeded, "call"), codes.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		if got := Code(tt.err); got != tt.want {
+			t.Errorf("Code(%v): got %v, want %v", tt.err, got, tt.want)
+		}
+	}
+	if ToStatus(errors.WithKind(errors.New("boom"), errors.Kind("quota_exceeded"))).Err() == nil {
+		t.Errorf("ToStatus(): got a nil error for an unmapped kind")
+	}
+	if st := ToStatus(errors.New("boom")); !strings.Contains(st.Message(), "boom") {
+		t.Errorf("ToStatus(): got message %q", st.Message())
+	}
+	var boundary string
+	errors.SetPolicyHook(func(err error, b string) { boundary = b })
+	defer errors.SetPolicyHook(nil)
+	ToStatus(context.Canceled)
+	if boundary != "errgrpc.ToStatus" {
+		t.Errorf("ToStatus(): got policy boundary %q, want errgrpc.ToStatus", boundary)
+	}
+	if err := FromStatus(status.New(codes.OK, ""), nil); err != nil {
+		t.Errorf("FromStatus(OK): got %v, want nil", err)
+	}
+}
@@ -0,0 +1,20 @@
+module github.com/objenious/errors/errgrpc
+
+go 1.21
+
+require (
+	github.com/objenious/errors v0.0.0-20261014111601-3423ab4da54f
+	google.golang.org/grpc v1.64.1
+)
+
+require (
+	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+	google.golang.org/protobuf v1.33.0 // indirect
+)
+
+// The replacement builds errgrpc with the checkout it is part of, it is
+// ignored by the modules requiring errgrpc.
+replace github.com/objenious/errors => ../
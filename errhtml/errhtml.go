@@ -0,0 +1,281 @@
+// Package errhtml renders errors of github.com/objenious/errors as HTML
+// pages, showing their chain with the messages, fields and stack traces of
+// each level, for the 500 pages of servers in development.
+//
+// The pages include the source code and stack traces of the program, they
+// must not be served in production.
+package errhtml
+
+import (
+	"bufio"
+	"bytes"
+	goerrors "errors"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/objenious/errors"
+)
+
+// Renderer renders errors as HTML pages. The zero value renders errors
+// without source code.
+type Renderer struct {
+	// SourceLines is the number of lines of source code shown around the
+	// line of each local frame, none if 0.
+	SourceLines int
+}
+
+// Render writes the page for err to w with the zero Renderer.
+func Render(w io.Writer, err error) error {
+	var r Renderer
+	return r.Render(w, err)
+}
+
+// Error responds to a request with the page for err and the status code,
+// with the zero Renderer.
+func Error(w http.ResponseWriter, err error, code int) {
+	var r Renderer
+	r.Error(w, err, code)
+}
+
+// Render writes the page for err to w.
+func (r *Renderer) Render(w io.Writer, err error) error {
+	p := page{Title: "<nil>"}
+	if err != nil {
+		p.Title = err.Error()
+		// chains with a cycle are rendered as the error of CheckCycle.
+		if c := errors.CheckCycle(err); c != nil {
+			err = c
+		}
+		p.Levels = r.chain(err)
+		openDeepest(p.Levels)
+	}
+	return pageTemplate.Execute(w, p)
+}
+
+// Error responds to a request with the page for err and the status code. If
+// the page can't be rendered, it responds with the message of err as plain
+// text instead.
+func (r *Renderer) Error(w http.ResponseWriter, err error, code int) {
+	var b bytes.Buffer
+	if rerr := r.Render(&b, err); rerr != nil {
+		msg := "<nil>"
+		if err != nil {
+			msg = err.Error()
+		}
+		http.Error(w, msg, code)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+	_, _ = b.WriteTo(w)
+}
+
+type page struct {
+	Title  string
+	Levels []level
+}
+
+// level is an error of the chain, with the annotations wrapping it.
+type level struct {
+	Type     string
+	Message  string
+	Fields   []field
+	Frames   []frame
+	Open     bool
+	Branches [][]level
+}
+
+type field struct {
+	Key, Value string
+}
+
+type frame struct {
+	Func   string
+	File   string
+	Line   int
+	Source []line
+}
+
+type line struct {
+	Number  int
+	Code    template.HTML
+	Current bool
+}
+
+// chain returns the levels of the chain of err, following the first cause
+// of each level but for the errors wrapping several errors, rendered as
+// branches.
+func (r *Renderer) chain(err error) []level {
+	var levels []level
+	var fields []field
+	for err != nil {
+		if a, ok := err.(errors.Annotation); ok {
+			key, value := a.Annotation()
+			fields = append(fields, field{key, fmt.Sprint(value)})
+			err = a.Unwrap()
+			continue
+		}
+		l := level{Fields: fields}
+		fields = nil
+		var causes []error
+		switch e := err.(type) {
+		case errors.Wrapper:
+			l.Message = e.Message()
+			l.Frames = r.frames(errors.StackText(e))
+			causes = []error{e.Unwrap()}
+		case *errors.RemoteError:
+			l.Type = "remote"
+			l.Message = e.Message
+			l.Frames = remoteFrames(e.Stack)
+			causes = e.Causes
+		default:
+			l.Type = fmt.Sprintf("%T", err)
+			l.Message = err.Error()
+			l.Frames = r.frames(errors.StackText(err))
+			if u, ok := err.(interface{ Unwrap() []error }); ok {
+				causes = u.Unwrap()
+			} else {
+				causes = []error{goerrors.Unwrap(err)}
+			}
+		}
+		err = nil
+		switch len(causes) {
+		case 0:
+		case 1:
+			err = causes[0]
+		default:
+			for _, cause := range causes {
+				l.Branches = append(l.Branches, r.chain(cause))
+			}
+		}
+		levels = append(levels, l)
+	}
+	return levels
+}
+
+// openDeepest marks the deepest level with frames of the chain as open,
+// the stack trace of the origin of the error being the most relevant.
+func openDeepest(levels []level) bool {
+	for i := len(levels) - 1; i >= 0; i-- {
+		for _, b := range levels[i].Branches {
+			if openDeepest(b) {
+				return true
+			}
+		}
+		if len(levels[i].Frames) > 0 {
+			levels[i].Open = true
+			return true
+		}
+	}
+	return false
+}
+
+// frames returns the frames of a stack trace serialized as by errors.ToMap,
+// redacted and in the mode of the process, with their source code if the
+// file of the frame can be read.
+func (r *Renderer) frames(stack []string) []frame {
+	frames := remoteFrames(stack)
+	if r.SourceLines > 0 {
+		for i, fr := range frames {
+			if fr.File != "" {
+				frames[i].Source = source(fr.File, fr.Line, r.SourceLines)
+			}
+		}
+	}
+	return frames
+}
+
+// remoteFrames returns the frames of the stack trace of a RemoteError,
+// serialized as "function file:line".
+func remoteFrames(stack []string) []frame {
+	frames := make([]frame, 0, len(stack))
+	for _, s := range stack {
+		fr := frame{Func: s}
+		if i := strings.LastIndexByte(s, ' '); i >= 0 {
+			fr.Func, fr.File = s[:i], s[i+1:]
+			if j := strings.LastIndexByte(fr.File, ':'); j >= 0 {
+				if n, err := strconv.Atoi(fr.File[j+1:]); err == nil {
+					fr.File, fr.Line = fr.File[:j], n
+				}
+			}
+		}
+		frames = append(frames, fr)
+	}
+	return frames
+}
+
+// source returns the lines of file around line, highlighted, or nil if the
+// file can't be read.
+func source(file string, current, around int) []line {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var lines []line
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan() && n <= current+around; n++ {
+		if n >= current-around {
+			lines = append(lines, line{
+				Number:  n,
+				Code:    highlight(sc.Text()),
+				Current: n == current,
+			})
+		}
+	}
+	return lines
+}
+
+// highlight returns the line of Go source code src as HTML, keywords,
+// literals and comments being in spans of the classes kw, lit and com.
+func highlight(src string) template.HTML {
+	var b strings.Builder
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	s.Init(file, []byte(src), func(token.Position, string) {}, scanner.ScanComments)
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		off := file.Offset(pos)
+		if tok == token.SEMICOLON && lit != ";" || off < last {
+			continue
+		}
+		text := lit
+		if text == "" || tok.IsOperator() {
+			text = tok.String()
+		}
+		if off+len(text) > len(src) {
+			break
+		}
+		b.WriteString(template.HTMLEscapeString(src[last:off]))
+		class := ""
+		switch {
+		case tok.IsKeyword():
+			class = "kw"
+		case tok == token.COMMENT:
+			class = "com"
+		case tok.IsLiteral() && tok != token.IDENT:
+			class = "lit"
+		}
+		if class != "" {
+			fmt.Fprintf(&b, `<span class="%s">%s</span>`, class, template.HTMLEscapeString(text))
+		} else {
+			b.WriteString(template.HTMLEscapeString(text))
+		}
+		last = off + len(text)
+	}
+	b.WriteString(template.HTMLEscapeString(src[last:]))
+	return template.HTML(b.String())
+}
@@ -0,0 +1,151 @@
+package errhtml
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/objenious/errors"
+)
+
+func TestRender(t *testing.T) {
+	err := errors.WithField(errors.Wrap(errors.New("<boom>"), "read config"), "path", "/etc/app")
+	var b strings.Builder
+	r := Renderer{SourceLines: 1}
+	if err := r.Render(&b, err); err != nil {
+		t.Fatal(err)
+	}
+	page := b.String()
+	for _, want := range []string{
+		"<title>read config: &lt;boom&gt;</title>",
+		`<div class="message">read config</div>`,
+		`<div class="message">&lt;boom&gt;</div>`,
+		"<tr><td>path</td><td>/etc/app</td></tr>",
+		`<span class="func">github.com/objenious/errors/errhtml.TestRender</span>`,
+		`<div class="current">`,
+		`<span class="lit">&#34;&lt;boom&gt;&#34;</span>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page does not contain %s:\n%s", want, page)
+		}
+	}
+	if n := strings.Count(page, "<details open>"); n != 1 {
+		t.Errorf("page has %d open stack traces, want 1", n)
+	}
+	if strings.Contains(page, "<boom>") {
+		t.Errorf("page contains unescaped message:\n%s", page)
+	}
+}
+
+func TestRenderRedacted(t *testing.T) {
+	errors.SetFrameRedactor(func(f errors.Frame) (errors.Frame, bool) {
+		return f, fmt.Sprintf("%n", f) != "TestRenderRedacted"
+	})
+	defer errors.SetFrameRedactor(nil)
+	var b strings.Builder
+	if err := Render(&b, errors.New("boom")); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "TestRenderRedacted") {
+		t.Errorf("page contains a redacted frame:\n%s", b.String())
+	}
+}
+
+func TestRenderBranches(t *testing.T) {
+	err := errors.Join(errors.New("first"), io.EOF)
+	var b strings.Builder
+	if err := Render(&b, err); err != nil {
+		t.Fatal(err)
+	}
+	page := b.String()
+	for _, want := range []string{
+		`<span class="type">*errors.joinError</span>`,
+		`<div class="message">first</div>`,
+		`<span class="type">*errors.errorString</span>`,
+		`<div class="message">EOF</div>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page does not contain %s:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, `class="source"`) {
+		t.Errorf("zero Renderer shows source code:\n%s", page)
+	}
+}
+
+func TestRenderRemote(t *testing.T) {
+	remote := &errors.RemoteError{
+		Message: "boom",
+		Stack:   []string{"main.main /src/main.go:12"},
+	}
+	var b strings.Builder
+	if err := Render(&b, remote); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<span class="func">main.main</span><br><span class="file">/src/main.go:12</span>`; !strings.Contains(b.String(), want) {
+		t.Errorf("page does not contain %s:\n%s", want, b.String())
+	}
+}
+
+func TestError(t *testing.T) {
+	w := httptest.NewRecorder()
+	Error(w, errors.New("boom"), http.StatusInternalServerError)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if got := w.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if !strings.Contains(w.Body.String(), "<title>boom</title>") {
+		t.Errorf("body = %s", w.Body.String())
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{
+			`	return x < 1 // done`,
+			`	<span class="kw">return</span> x &lt; <span class="lit">1</span> <span class="com">// done</span>`,
+		},
+		{
+			`s := "a" + b`,
+			`s := <span class="lit">&#34;a&#34;</span> + b`,
+		},
+		{
+			"`unterminated",
+			"<span class=\"lit\">`unterminated</span>",
+		},
+	}
+	for _, tt := range tests {
+		if got := string(highlight(tt.src)); got != tt.want {
+			t.Errorf("highlight(%q) = %s, want %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+// loopError is an error whose causes are set after it is wrapped.
+type loopError struct {
+	causes []error
+}
+
+func (e *loopError) Error() string { return "loop" }
+
+func (e *loopError) Unwrap() []error { return e.causes }
+
+func TestRenderCycle(t *testing.T) {
+	loop := &loopError{}
+	err := errors.Wrap(loop, "wrap")
+	loop.causes = []error{err, io.EOF}
+	var b strings.Builder
+	if err := (&Renderer{}).Render(&b, err); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "cycle in the chain of an error") {
+		t.Errorf("Render() = %s", b.String())
+	}
+}
@@ -0,0 +1,53 @@
+package errhtml
+
+import "html/template"
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.4em; color: #b00; white-space: pre-wrap; }
+ol.chain { list-style: none; padding: 0; }
+ol.chain > li { border-left: 3px solid #b00; margin: 1em 0; padding: 0 1em; }
+.type { color: #777; font-family: monospace; }
+.message { font-weight: bold; white-space: pre-wrap; }
+table.fields { border-collapse: collapse; margin: .5em 0; }
+table.fields td { border: 1px solid #ddd; padding: .2em .6em; font-family: monospace; }
+summary { cursor: pointer; color: #555; }
+ol.frames { font-family: monospace; font-size: .9em; }
+ol.frames li { margin: .4em 0; }
+.func { color: #05a; }
+.file { color: #777; }
+pre.source { background: #f6f6f6; margin: .3em 0; padding: .3em 0; }
+pre.source span.n { display: inline-block; width: 4em; color: #aaa; text-align: right; padding-right: 1em; }
+pre.source div.current { background: #fee; }
+.kw { color: #a0a; }
+.lit { color: #080; }
+.com { color: #888; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{template "chain" .Levels}}
+</body>
+</html>
+{{define "chain"}}<ol class="chain">
+{{range .}}<li>
+{{if .Type}}<span class="type">{{.Type}}</span>{{end}}
+{{if .Message}}<div class="message">{{.Message}}</div>{{end}}
+{{if .Fields}}<table class="fields">
+{{range .Fields}}<tr><td>{{.Key}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Frames}}<details{{if .Open}} open{{end}}>
+<summary>stack trace ({{len .Frames}} frames)</summary>
+<ol class="frames">
+{{range .Frames}}<li><span class="func">{{.Func}}</span><br><span class="file">{{.File}}{{if .Line}}:{{.Line}}{{end}}</span>
+{{if .Source}}<pre class="source">{{range .Source}}<div{{if .Current}} class="current"{{end}}><span class="n">{{.Number}}</span>{{.Code}}</div>{{end}}</pre>{{end}}</li>
+{{end}}</ol>
+</details>{{end}}
+{{range .Branches}}{{template "chain" .}}{{end}}
+</li>
+{{end}}</ol>{{end}}`))
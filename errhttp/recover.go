@@ -0,0 +1,59 @@
+// Package errhttp integrates github.com/objenious/errors with net/http.
+package errhttp
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/objenious/errors"
+)
+
+// Recovery is an http.Handler recovering from the panics of the handler it
+// wraps, built by Recoverer.
+type Recovery struct {
+	next   http.Handler
+	report func(error)
+
+	// ReferenceID adds the fingerprint of the error to the body of the
+	// response, as a reference to the incident for support.
+	ReferenceID bool
+}
+
+// Recoverer returns a handler calling next, that converts its panics to
+// errors with the stack trace of the panic (see errors.FromPanic), reports
+// them with report, and responds with a 500 status and a body that does not
+// leak them. The errors have the fields "method" and "path" of the request.
+//
+// As for net/http servers, the panics with http.ErrAbortHandler are not
+// recovered.
+func Recoverer(next http.Handler, report func(error)) *Recovery {
+	return &Recovery{next: next, report: report}
+}
+
+// ServeHTTP calls the wrapped handler, recovering from its panics.
+func (h *Recovery) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+		if v == http.ErrAbortHandler {
+			panic(v)
+		}
+		err := errors.FromPanic(v)
+		err = errors.WithField(err, "method", r.Method)
+		err = errors.WithField(err, "path", r.URL.Path)
+		if h.report != nil {
+			h.report(err)
+		}
+		body := http.StatusText(http.StatusInternalServerError)
+		if h.ReferenceID {
+			body = fmt.Sprintf("%s (reference: %s)", body, errors.Fingerprint(err))
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintln(w, body)
+	}()
+	h.next.ServeHTTP(w, r)
+}
@@ -0,0 +1,64 @@
+package errhttp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/objenious/errors"
+)
+
+func TestRecoverer(t *testing.T) {
+	var reported error
+	h := Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/panic" {
+			panic("secret database password")
+		}
+		fmt.Fprint(w, "ok")
+	}), func(err error) { reported = err })
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/ok", nil))
+	if rec.Code != http.StatusOK || reported != nil {
+		t.Errorf("no panic: got status %d and reported %v", rec.Code, reported)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/panic", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("panic: got status %d, want 500", rec.Code)
+	}
+	if got, want := rec.Body.String(), "Internal Server Error\n"; got != want {
+		t.Errorf("panic: got body %q, want %q", got, want)
+	}
+	if reported == nil || reported.Error() != "panic: secret database password" {
+		t.Fatalf("panic: got reported %v", reported)
+	}
+	if fields := errors.Fields(reported); fields["method"] != "POST" || fields["path"] != "/panic" {
+		t.Errorf("panic: got fields %v", fields)
+	}
+	if got := fmt.Sprintf("%+v", reported); !strings.Contains(got, "errhttp.TestRecoverer.func") {
+		t.Errorf("panic: got %q, want the stack trace of the panic", got)
+	}
+
+	h.ReferenceID = true
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/panic", nil))
+	if got, want := rec.Body.String(), "Internal Server Error (reference: "+errors.Fingerprint(reported)+")\n"; got != want {
+		t.Errorf("panic: got body %q, want %q", got, want)
+	}
+}
+
+func TestRecovererAbort(t *testing.T) {
+	h := Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}), nil)
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("got panic %v, want http.ErrAbortHandler", v)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
@@ -0,0 +1,89 @@
+package errhttp
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+
+	"github.com/objenious/errors"
+)
+
+// Failures of round trips, the values of the field "failure" of the errors
+// returned by Transport.
+const (
+	FailureCanceled = "canceled"
+	FailureTimeout  = "timeout"
+	FailureDNS      = "dns"
+	FailureTLS      = "tls"
+	FailureConnect  = "connect"
+)
+
+// Transport is an http.RoundTripper classifying the errors of the round trips
+// of the RoundTripper it wraps, so that the clients using it get annotated
+// errors without classifying them at every call site:
+//
+//	client := &http.Client{Transport: &errhttp.Transport{}}
+//
+// The errors have the stack trace of the round trip and a snapshot of the
+// request (see errors.WithHTTPExchange). The errors of known failures are
+// also annotated with the field "failure", a kind and whether they are
+// retryable:
+//
+//   - "canceled" if the context of the request was canceled, of kind
+//     errors.KindCanceled, not retryable,
+//   - "timeout" for timeouts, of kind errors.KindTimeout, retryable,
+//   - "dns" for the failures to resolve the host, of kind
+//     errors.KindUnavailable, retryable unless the host was not found,
+//   - "tls" for the failures of TLS handshakes and certificate
+//     verifications, of kind errors.KindUnavailable, not retryable,
+//   - "connect" for the failures to connect to the server, of kind
+//     errors.KindUnavailable, retryable.
+type Transport struct {
+	// Base is the RoundTripper making the round trips, http.DefaultTransport
+	// if nil.
+	Base http.RoundTripper
+}
+
+// RoundTrip makes the round trip of req with the base RoundTripper,
+// classifying its error.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, classify(req, err)
+	}
+	return resp, nil
+}
+
+// classify annotates err, returned by the round trip of req.
+func classify(req *http.Request, err error) error {
+	err = errors.WithHTTPExchange(errors.WithStack(err), req, nil, 0)
+	var (
+		failure   string
+		kind      = errors.KindUnavailable
+		retryable bool
+		dnsErr    *net.DNSError
+		opErr     *net.OpError
+		timeout   interface{ Timeout() bool }
+	)
+	switch {
+	case stderrors.Is(err, context.Canceled):
+		failure, kind = FailureCanceled, errors.KindCanceled
+	case stderrors.Is(err, context.DeadlineExceeded) || stderrors.As(err, &timeout) && timeout.Timeout():
+		failure, kind, retryable = FailureTimeout, errors.KindTimeout, true
+	case stderrors.As(err, &dnsErr):
+		failure, retryable = FailureDNS, !dnsErr.IsNotFound
+	case errors.IsTLSError(err):
+		failure = FailureTLS
+	case stderrors.As(err, &opErr) && opErr.Op == "dial":
+		failure, retryable = FailureConnect, true
+	default:
+		return err
+	}
+	err = errors.WithField(err, "failure", failure)
+	return errors.WithRetryable(errors.WithKind(err, kind), retryable)
+}
@@ -0,0 +1,80 @@
+package errhttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/objenious/errors"
+)
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	tlsSrv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsSrv.Close()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := "http://" + lis.Addr().String()
+	_ = lis.Close()
+
+	client := &http.Client{Transport: &Transport{}}
+	resp, err := client.Get(srv.URL)
+	if err != nil || resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Get(): got %v, %v", resp, err)
+	}
+	_ = resp.Body.Close()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	timeout, cancelTimeout := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelTimeout()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		url       string
+		failure   string
+		kind      errors.Kind
+		retryable bool
+	}{
+		{"canceled", canceled, srv.URL, FailureCanceled, errors.KindCanceled, false},
+		{"timeout", timeout, srv.URL + "/slow", FailureTimeout, errors.KindTimeout, true},
+		{"tls", context.Background(), tlsSrv.URL, FailureTLS, errors.KindUnavailable, false},
+		{"connect", context.Background(), closed, FailureConnect, errors.KindUnavailable, true},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", tt.url+"?token=secret", nil)
+		_, err := client.Do(req.WithContext(tt.ctx))
+		if err == nil {
+			t.Errorf("%s: got no error", tt.name)
+			continue
+		}
+		if v, _ := errors.Field(err, "failure"); v != tt.failure {
+			t.Errorf("%s: got failure %v, want %v (%v)", tt.name, v, tt.failure, err)
+		}
+		if got := errors.KindOf(err); got != tt.kind {
+			t.Errorf("%s: got kind %q, want %q", tt.name, got, tt.kind)
+		}
+		if got := errors.IsRetryable(err); got != tt.retryable {
+			t.Errorf("%s: got retryable %t, want %t", tt.name, got, tt.retryable)
+		}
+		if x, ok := errors.HTTPExchange(err); !ok || x.Method != "GET" || x.URL != tt.url+"?token=REDACTED" {
+			t.Errorf("%s: got exchange %+v", tt.name, x)
+		}
+		if errors.GetStackTrace(err) == nil {
+			t.Errorf("%s: got no stack trace", tt.name)
+		}
+	}
+}
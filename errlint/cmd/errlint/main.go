@@ -0,0 +1,14 @@
+// Command errlint runs the analyzers of github.com/objenious/errors/errlint,
+// as a tool of go vet:
+//
+//	go vet -vettool=$(which errlint) ./...
+package main
+
+import (
+	"github.com/objenious/errors/errlint"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(errlint.Wrapping, errlint.Constructors)
+}
@@ -0,0 +1,144 @@
+package errlint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Constructors reports, in function bodies, the calls to fmt.Errorf without a
+// %w verb and to the New function of the standard errors package: the errors
+// they create have no stack trace, and fmt.Errorf without %w loses the cause.
+// Package level declarations of sentinel errors are not reported.
+//
+// The -allow flag lists the import paths, or prefixes followed by "/...", of
+// the packages that may use them, and the -exempt flag the patterns, matched
+// as by path.Match against the base name of the files, or against their
+// trailing elements if the pattern contains a separator, of the files not
+// checked. Test files are exempt by default.
+var Constructors = &analysis.Analyzer{
+	Name:     "errconstructors",
+	Doc:      "report fmt.Errorf without %w and errors.New of the standard library in function bodies",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runConstructors,
+}
+
+var (
+	allowed listFlag
+	exempt  = listFlag{"*_test.go"}
+)
+
+func init() {
+	Constructors.Flags.Var(&allowed, "allow", "comma-separated import paths, or prefixes followed by /..., of the packages allowed to use fmt.Errorf and errors.New")
+	Constructors.Flags.Var(&exempt, "exempt", "comma-separated patterns of the files not checked")
+}
+
+// listFlag is a flag.Value holding a comma-separated list.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(s string) error {
+	*l = nil
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+func runConstructors(pass *analysis.Pass) (interface{}, error) {
+	if isAllowed(pass.Pkg.Path()) {
+		return nil, nil
+	}
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push || !inFunc(stack) {
+			return true
+		}
+		if isExempt(pass.Fset.File(n.Pos()).Name()) {
+			return false
+		}
+		call := n.(*ast.CallExpr)
+		fn, _ := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if fn == nil || fn.Pkg() == nil {
+			return true
+		}
+		switch {
+		case fn.Pkg().Path() == "errors" && fn.Name() == "New":
+			pass.Reportf(call.Pos(), "errors.New of the standard library creates an error without a stack trace; use github.com/objenious/errors.New")
+		case fn.Pkg().Path() == "fmt" && fn.Name() == "Errorf" && !wrapsCause(pass, call):
+			pass.Reportf(call.Pos(), "fmt.Errorf without %%w loses the cause; use github.com/objenious/errors.Errorf or Wrapf")
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// wrapsCause reports whether the format of a call to fmt.Errorf contains a
+// %w verb, or is not a constant.
+func wrapsCause(pass *analysis.Pass, call *ast.CallExpr) bool {
+	if len(call.Args) == 0 {
+		return false
+	}
+	tv := pass.TypesInfo.Types[call.Args[0]]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return true
+	}
+	return strings.Contains(strings.ReplaceAll(constant.StringVal(tv.Value), "%%", ""), "%w")
+}
+
+// inFunc reports whether the last node of stack is in a function.
+func inFunc(stack []ast.Node) bool {
+	for _, n := range stack {
+		switch n.(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return true
+		}
+	}
+	return false
+}
+
+// isAllowed reports whether the package pkg is allowed by the -allow
+// flag.
+func isAllowed(pkg string) bool {
+	for _, p := range allowed {
+		if prefix := strings.TrimSuffix(p, "/..."); prefix != p {
+			if pkg == prefix || strings.HasPrefix(pkg, prefix+"/") {
+				return true
+			}
+		} else if pkg == p {
+			return true
+		}
+	}
+	return false
+}
+
+// isExempt reports whether the file name matches a pattern of the -exempt
+// flag. Patterns with separators are matched against as many trailing
+// elements of name as they have.
+func isExempt(name string) bool {
+	elems := strings.Split(filepath.ToSlash(name), "/")
+	for _, p := range exempt {
+		p = filepath.ToSlash(p)
+		n := strings.Count(p, "/") + 1
+		if n > len(elems) {
+			continue
+		}
+		if ok, _ := path.Match(p, strings.Join(elems[len(elems)-n:], "/")); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+// Package errlint provides analyzers enforcing the conventions of
+// github.com/objenious/errors, for go vet or any driver of
+// golang.org/x/tools/go/analysis:
+//
+//	go install github.com/objenious/errors/errlint/cmd/errlint@latest
+//	go vet -vettool=$(which errlint) ./...
+package errlint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// errorsPath is the import path of github.com/objenious/errors.
+const errorsPath = "github.com/objenious/errors"
+
+// Wrapping reports the errors returned by functions of other packages that
+// are returned without being wrapped or annotated, and the calls to Wrap and
+// Wrapf with an empty message, or with the same message as another call of
+// the same function.
+var Wrapping = &analysis.Analyzer{
+	Name:     "errwrapping",
+	Doc:      "report errors of other packages returned without wrapping, and Wrap calls with empty or duplicate messages",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runWrapping,
+}
+
+func runWrapping(pass *analysis.Pass) (interface{}, error) {
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node) {
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			if fn.Body != nil {
+				checkFunc(pass, fn.Body)
+			}
+		case *ast.FuncLit:
+			checkFunc(pass, fn.Body)
+		}
+	})
+	return nil, nil
+}
+
+// checkFunc checks the body of a function, without the function literals it
+// contains.
+func checkFunc(pass *analysis.Pass, body *ast.BlockStmt) {
+	origins := make(map[types.Object]*types.Func)
+	messages := make(map[string]token.Pos)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			recordOrigins(pass, n, origins)
+		case *ast.ReturnStmt:
+			for _, result := range n.Results {
+				var fn *types.Func
+				switch r := ast.Unparen(result).(type) {
+				case *ast.Ident:
+					fn = origins[pass.TypesInfo.Uses[r]]
+				case *ast.CallExpr:
+					if isError(pass.TypesInfo.TypeOf(r)) {
+						fn = foreignCallee(pass, r)
+					}
+				}
+				if fn != nil {
+					pass.Reportf(result.Pos(), "error returned by %s is not wrapped", funcName(fn))
+				}
+			}
+		case *ast.CallExpr:
+			checkWrapMessage(pass, n, messages)
+		}
+		return true
+	})
+}
+
+// recordOrigins records in origins the error variables assigned by assign to
+// the results of functions of other packages, and forgets the others.
+func recordOrigins(pass *analysis.Pass, assign *ast.AssignStmt, origins map[types.Object]*types.Func) {
+	for i, lhs := range assign.Lhs {
+		id, ok := lhs.(*ast.Ident)
+		if !ok || id.Name == "_" {
+			continue
+		}
+		obj := pass.TypesInfo.ObjectOf(id)
+		if obj == nil || !isError(obj.Type()) {
+			continue
+		}
+		var rhs ast.Expr
+		if len(assign.Rhs) == len(assign.Lhs) {
+			rhs = assign.Rhs[i]
+		} else if len(assign.Rhs) == 1 {
+			rhs = assign.Rhs[0]
+		}
+		call, _ := ast.Unparen(rhs).(*ast.CallExpr)
+		if fn := foreignCallee(pass, call); fn != nil {
+			origins[obj] = fn
+		} else {
+			delete(origins, obj)
+		}
+	}
+}
+
+// foreignCallee returns the function called by call if it belongs to another
+// package, and does not create errors nor annotate them: the functions of
+// the packages errors and github.com/objenious/errors and its subpackages,
+// and fmt.Errorf, are not foreign.
+func foreignCallee(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	if call == nil {
+		return nil
+	}
+	fn, _ := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg() == pass.Pkg {
+		return nil
+	}
+	switch path := fn.Pkg().Path(); {
+	case path == "errors", path == errorsPath, strings.HasPrefix(path, errorsPath+"/"):
+		return nil
+	case path == "fmt" && fn.Name() == "Errorf":
+		return nil
+	}
+	return fn
+}
+
+// checkWrapMessage reports the calls to Wrap and Wrapf with an empty message,
+// or with a message already used in the same function, recorded in messages.
+func checkWrapMessage(pass *analysis.Pass, call *ast.CallExpr, messages map[string]token.Pos) {
+	fn, _ := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if fn == nil || fn.Pkg() == nil || !isErrorsPackage(fn.Pkg().Path()) || len(call.Args) < 2 {
+		return
+	}
+	if fn.Name() != "Wrap" && fn.Name() != "Wrapf" {
+		return
+	}
+	tv := pass.TypesInfo.Types[call.Args[1]]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return
+	}
+	msg := constant.StringVal(tv.Value)
+	if strings.TrimSpace(msg) == "" {
+		pass.Reportf(call.Args[1].Pos(), "%s with an empty message", fn.Name())
+		return
+	}
+	if pos, ok := messages[msg]; ok {
+		pass.Reportf(call.Args[1].Pos(), "%s with the message %q, already used at %s", fn.Name(), msg, pass.Fset.Position(pos))
+		return
+	}
+	messages[msg] = call.Args[1].Pos()
+}
+
+// isErrorsPackage reports whether path is the import path of
+// github.com/objenious/errors or of its compat package.
+func isErrorsPackage(path string) bool {
+	return path == errorsPath || path == errorsPath+"/compat"
+}
+
+// isError reports whether t is the error type.
+func isError(t types.Type) bool {
+	return t != nil && types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// funcName returns the name of fn qualified by its package, or by its type
+// for methods.
+func funcName(fn *types.Func) string {
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		t := recv.Type()
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem()
+		}
+		if named, ok := t.(*types.Named); ok {
+			return fn.Pkg().Name() + "." + named.Obj().Name() + "." + fn.Name()
+		}
+	}
+	return fn.Pkg().Name() + "." + fn.Name()
+}
@@ -0,0 +1,21 @@
+package errlint
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestWrapping(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Wrapping, "a")
+}
+
+func TestConstructors(t *testing.T) {
+	defer func(a, e listFlag) { allowed, exempt = a, e }(allowed, exempt)
+	for name, value := range map[string]string{"allow": "c", "exempt": "*_test.go,b/gen/*.go"} {
+		if err := Constructors.Flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	analysistest.Run(t, analysistest.TestData(), Constructors, "b", "b/gen", "c")
+}
@@ -0,0 +1,60 @@
+package a
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/objenious/errors"
+)
+
+func open(path string) (*os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err // want `error returned by os.Open is not wrapped`
+	}
+	return f, nil
+}
+
+func remove(path string) error {
+	return os.Remove(path) // want `error returned by os.Remove is not wrapped`
+}
+
+func read(r io.Reader, buf []byte) error {
+	_, err := r.Read(buf)
+	return err // want `error returned by io.Reader.Read is not wrapped`
+}
+
+func wrapped(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "open")
+	}
+	if _, err = f.Stat(); err != nil {
+		err = errors.WithField(err, "path", path)
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+	return stderrors.New("done")
+}
+
+func local() error {
+	err := helper()
+	return err
+}
+
+func helper() error { return nil }
+
+func messages(err error) error {
+	if err != nil {
+		return errors.Wrap(err, "") // want `Wrap with an empty message`
+	}
+	err = errors.Wrap(err, "read")
+	err = errors.Wrapf(err, "read") // want `Wrapf with the message "read", already used at .*`
+	return func() error {
+		return errors.Wrap(err, "read")
+	}()
+}
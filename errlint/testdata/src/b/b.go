@@ -0,0 +1,27 @@
+package b
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrSentinel = errors.New("sentinel")
+
+func f(err error, format string) error {
+	if err == nil {
+		return errors.New("none") // want `errors.New of the standard library creates an error without a stack trace`
+	}
+	if format != "" {
+		return fmt.Errorf(format, err)
+	}
+	if err == ErrSentinel {
+		return fmt.Errorf("sentinel: %v", err) // want `fmt.Errorf without %w loses the cause`
+	}
+	return func() error {
+		return fmt.Errorf("100%%w: %w", err)
+	}()
+}
+
+func g(err error) error {
+	return fmt.Errorf("100%%w: %v", err) // want `fmt.Errorf without %w loses the cause`
+}
@@ -0,0 +1,7 @@
+package gen
+
+import "errors"
+
+func f() error {
+	return errors.New("generated")
+}
@@ -0,0 +1,7 @@
+package c
+
+import "errors"
+
+func f() error {
+	return errors.New("allowed")
+}
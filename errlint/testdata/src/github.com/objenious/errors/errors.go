@@ -0,0 +1,10 @@
+// Package errors is a stub of github.com/objenious/errors.
+package errors
+
+func New(message string) error { return nil }
+
+func Wrap(err error, message string) error { return err }
+
+func Wrapf(err error, format string, args ...interface{}) error { return err }
+
+func WithField(err error, key string, value interface{}) error { return err }
@@ -0,0 +1,211 @@
+// Package errnotify sends notifications of errors of
+// github.com/objenious/errors to Slack or to generic webhooks, for on-call
+// alerts of critical errors.
+package errnotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/objenious/errors"
+)
+
+const (
+	// DefaultInterval is the default minimum interval between the
+	// notifications of errors with the same fingerprint.
+	DefaultInterval = 5 * time.Minute
+	// DefaultMaxFrames is the default number of frames of the stack trace
+	// sent in notifications.
+	DefaultMaxFrames = 10
+)
+
+// Alert is the description of an error sent by a Notifier.
+type Alert struct {
+	// Fingerprint identifies the origin of the error, see
+	// errors.Fingerprint.
+	Fingerprint string `json:"fingerprint"`
+	// Message is the message of the error.
+	Message string `json:"message"`
+	// Origin is the innermost frame of the deepest stack trace of the
+	// error, if any.
+	Origin string `json:"origin,omitempty"`
+	// Stack holds the first frames of the deepest stack trace of the error.
+	Stack []string `json:"stack,omitempty"`
+	// Time is the time of the notification.
+	Time time.Time `json:"time"`
+	// Suppressed is the number of errors with the same fingerprint that
+	// were not notified since the previous notification.
+	Suppressed int `json:"suppressed,omitempty"`
+}
+
+// Notifier posts the errors it is notified of to a webhook, at most once per
+// interval for errors with the same fingerprint. It is safe for concurrent
+// use.
+type Notifier struct {
+	url     string
+	payload func(Alert) interface{}
+
+	// Client is the client used to post notifications, http.DefaultClient
+	// if nil.
+	Client *http.Client
+	// Interval is the minimum interval between the notifications of errors
+	// with the same fingerprint, no limit if 0.
+	Interval time.Duration
+	// MaxFrames is the number of frames of the stack trace sent in
+	// notifications, all of them if 0.
+	MaxFrames int
+
+	mu    sync.Mutex
+	sent  map[string]time.Time
+	count map[string]int
+	now   func() time.Time
+}
+
+// New returns a Notifier posting alerts as JSON objects to the webhook at url.
+func New(url string) *Notifier {
+	return newNotifier(url, func(a Alert) interface{} { return a })
+}
+
+// NewSlack returns a Notifier posting alerts to the Slack incoming webhook at
+// url.
+func NewSlack(url string) *Notifier {
+	return newNotifier(url, slackPayload)
+}
+
+func newNotifier(url string, payload func(Alert) interface{}) *Notifier {
+	return &Notifier{
+		url:       url,
+		payload:   payload,
+		Interval:  DefaultInterval,
+		MaxFrames: DefaultMaxFrames,
+		sent:      make(map[string]time.Time),
+		count:     make(map[string]int),
+		now:       time.Now,
+	}
+}
+
+// Notify posts an alert for err, unless err is nil or an error with the same
+// fingerprint was notified less than Interval ago, in which case it only
+// counts it for the next alert. It returns an error if the alert could not be
+// posted.
+func (n *Notifier) Notify(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	alert, ok := n.alert(err)
+	if !ok {
+		return nil
+	}
+	body, jerr := json.Marshal(n.payload(alert))
+	if jerr != nil {
+		return errors.Wrap(jerr, "errnotify")
+	}
+	req, rerr := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if rerr != nil {
+		return requestError(rerr)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, rerr := client.Do(req.WithContext(ctx))
+	if rerr != nil {
+		return requestError(rerr)
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// the URL of webhooks is a secret, it is not part of the error.
+		return errors.Errorf("errnotify: webhook responded %s", resp.Status)
+	}
+	return nil
+}
+
+// requestError wraps err, an error of the request to the webhook. The
+// *url.Error of net/http gives the URL of the webhook, a secret: only the
+// error it wraps is kept.
+func requestError(err error) error {
+	if uerr, ok := err.(*url.Error); ok {
+		return errors.Wrapf(uerr.Err, "errnotify: %s webhook", strings.ToLower(uerr.Op))
+	}
+	return errors.Wrap(err, "errnotify")
+}
+
+// alert returns the alert for err, or false if it must not be notified yet.
+func (n *Notifier) alert(err error) (Alert, bool) {
+	fp := errors.Fingerprint(err)
+	now := n.now()
+	n.mu.Lock()
+	if last, ok := n.sent[fp]; ok && now.Sub(last) < n.Interval {
+		n.count[fp]++
+		n.mu.Unlock()
+		return Alert{}, false
+	}
+	suppressed := n.count[fp]
+	delete(n.count, fp)
+	n.sent[fp] = now
+	n.prune(now)
+	n.mu.Unlock()
+
+	stack := errors.DeepestStack(err)
+	a := Alert{
+		Fingerprint: fp,
+		Message:     err.Error(),
+		Time:        now,
+		Suppressed:  suppressed,
+	}
+	if len(stack) > 0 {
+		a.Origin = stack[0]
+		if n.MaxFrames > 0 && len(stack) > n.MaxFrames {
+			stack = stack[:n.MaxFrames]
+		}
+		a.Stack = stack
+	}
+	return a, true
+}
+
+// maxTracked is the number of fingerprints above which the expired ones are
+// forgotten.
+const maxTracked = 1000
+
+// prune forgets the fingerprints notified more than Interval before now
+// without errors since, once there are more than maxTracked.
+func (n *Notifier) prune(now time.Time) {
+	if len(n.sent) <= maxTracked {
+		return
+	}
+	for fp, last := range n.sent {
+		if now.Sub(last) >= n.Interval && n.count[fp] == 0 {
+			delete(n.sent, fp)
+		}
+	}
+}
+
+// slackEscaper escapes the control characters of Slack messages.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackPayload returns the payload of a Slack message for a.
+func slackPayload(a Alert) interface{} {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s*\nfingerprint `%s`", slackEscaper.Replace(a.Message), a.Fingerprint)
+	if a.Origin != "" {
+		fmt.Fprintf(&b, " at `%s`", slackEscaper.Replace(a.Origin))
+	}
+	if a.Suppressed > 0 {
+		fmt.Fprintf(&b, "\n%d similar errors since the previous alert", a.Suppressed)
+	}
+	if len(a.Stack) > 0 {
+		fmt.Fprintf(&b, "\n```\n%s\n```", slackEscaper.Replace(strings.Join(a.Stack, "\n")))
+	}
+	return map[string]string{"text": b.String()}
+}
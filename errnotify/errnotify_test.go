@@ -0,0 +1,122 @@
+package errnotify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/objenious/errors"
+)
+
+// webhook starts a server recording the bodies posted to it.
+func webhook(t *testing.T, status int) (*httptest.Server, *[]map[string]interface{}) {
+	var bodies []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s request with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+	}))
+	return srv, &bodies
+}
+
+func origin() error {
+	return errors.New("disk <full>")
+}
+
+func TestNotify(t *testing.T) {
+	srv, bodies := webhook(t, http.StatusOK)
+	defer srv.Close()
+	n := New(srv.URL)
+	n.MaxFrames = 1
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	n.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := n.Notify(ctx, errors.Wrap(origin(), "save")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := n.Notify(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(DefaultInterval)
+	if err := n.Notify(ctx, origin()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*bodies) != 2 {
+		t.Fatalf("got %d notifications, want 2", len(*bodies))
+	}
+	first, second := (*bodies)[0], (*bodies)[1]
+	if first["message"] != "save: disk <full>" || first["fingerprint"] != errors.Fingerprint(origin()) {
+		t.Errorf("first notification = %v", first)
+	}
+	origin, _ := first["origin"].(string)
+	if !strings.HasPrefix(origin, "github.com/objenious/errors/errnotify.origin ") {
+		t.Errorf("origin = %q", origin)
+	}
+	if stack, _ := first["stack"].([]interface{}); len(stack) != 1 || stack[0] != origin {
+		t.Errorf("stack = %v, want [%s]", stack, origin)
+	}
+	if _, ok := first["suppressed"]; ok {
+		t.Errorf("first notification has suppressed errors: %v", first)
+	}
+	if second["suppressed"] != 2.0 || second["time"] != "2020-01-01T00:05:00Z" {
+		t.Errorf("second notification = %v", second)
+	}
+}
+
+func TestNotifySlack(t *testing.T) {
+	srv, bodies := webhook(t, http.StatusOK)
+	defer srv.Close()
+	n := NewSlack(srv.URL)
+	n.MaxFrames = 1
+	if err := n.Notify(context.Background(), origin()); err != nil {
+		t.Fatal(err)
+	}
+	text, _ := (*bodies)[0]["text"].(string)
+	want := "*disk &lt;full&gt;*\nfingerprint `" + errors.Fingerprint(origin()) + "` at `github.com/objenious/errors/errnotify.origin "
+	if !strings.HasPrefix(text, want) || !strings.HasSuffix(text, "\n```") {
+		t.Errorf("text = %q, want prefix %q", text, want)
+	}
+}
+
+func TestNotifyStatus(t *testing.T) {
+	srv, _ := webhook(t, http.StatusForbidden)
+	defer srv.Close()
+	err := New(srv.URL+"/secret").Notify(context.Background(), origin())
+	if err == nil || err.Error() != "errnotify: webhook responded 403 Forbidden" {
+		t.Errorf("Notify() = %v", err)
+	}
+}
+
+func TestNotifyRequestError(t *testing.T) {
+	srv, _ := webhook(t, http.StatusOK)
+	srv.Close()
+	for _, url := range []string{srv.URL + "/secret", "http://[::1/secret"} {
+		err := New(url).Notify(context.Background(), origin())
+		if err == nil {
+			t.Fatalf("Notify(%s) = nil", url)
+		}
+		if !strings.HasPrefix(err.Error(), "errnotify: ") {
+			t.Errorf("Notify(%s) = %q", url, err)
+		}
+		for _, s := range []string{err.Error(), fmt.Sprintf("%+v", err)} {
+			if strings.Contains(s, "/secret") {
+				t.Errorf("Notify(%s) = %q, want no webhook URL", url, s)
+			}
+		}
+	}
+}
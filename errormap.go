@@ -0,0 +1,176 @@
+//go:build go1.18
+// +build go1.18
+
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrorMap collects errors by key, such as the EUI of a device or the name
+// of a file, when the association of the errors to their keys matters:
+//
+//	var m errors.ErrorMap[string]
+//	for _, name := range files {
+//		m.Add(name, process(name))
+//	}
+//	return m.ErrorOrNil() // "a.csv: EOF\nb.csv: permission denied"
+//
+// Its errors are formatted sorted by key, each one on its own line, and
+// errors.Is and errors.As match them. The zero ErrorMap is empty and ready
+// to use.
+type ErrorMap[K comparable] struct {
+	errs map[K]error
+}
+
+// Add records the error err of key. Nil errors are ignored, and errors added
+// to a key that already has one are joined to it.
+func (m *ErrorMap[K]) Add(key K, err error) {
+	if err == nil {
+		return
+	}
+	if m.errs == nil {
+		m.errs = make(map[K]error)
+	}
+	if prev, ok := m.errs[key]; ok {
+		err = Join(prev, err)
+	}
+	m.errs[key] = err
+}
+
+// Len returns the number of keys with an error.
+func (m *ErrorMap[K]) Len() int {
+	return len(m.errs)
+}
+
+// ByKey returns the error of key, or nil.
+func (m *ErrorMap[K]) ByKey(key K) error {
+	return m.errs[key]
+}
+
+// Keys returns the keys with an error, sorted. Keys of numeric or string
+// types are sorted by value, others by their representation with %v.
+func (m *ErrorMap[K]) Keys() []K {
+	keys := make([]K, 0, len(m.errs))
+	for k := range m.errs {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keyLess(keys[i], keys[j]) })
+	return keys
+}
+
+// ErrorOrNil returns nil if m has no error. Otherwise, it returns an error
+// wrapping a copy of m, with the stack trace at the point ErrorOrNil was
+// called.
+func (m *ErrorMap[K]) ErrorOrNil() error {
+	if len(m.errs) == 0 {
+		return nil
+	}
+	c := &ErrorMap[K]{errs: make(map[K]error, len(m.errs))}
+	for k, err := range m.errs {
+		c.errs[k] = err
+	}
+	return &withStack{
+		error:   c,
+		stack:   callers(),
+		created: timestamp(),
+	}
+}
+
+// Error returns the errors of m prefixed with their keys, sorted by key and
+// separated by newlines.
+func (m *ErrorMap[K]) Error() string {
+	keys := m.Keys()
+	msgs := make([]string, len(keys))
+	for i, k := range keys {
+		msgs[i] = fmt.Sprintf("%v: %s", k, m.errs[k])
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors of m, sorted by key
+func (m *ErrorMap[K]) Unwrap() []error {
+	keys := m.Keys()
+	errs := make([]error, len(keys))
+	for i, k := range keys {
+		errs[i] = m.errs[k]
+	}
+	return errs
+}
+
+// matchesCauses marks the Is and As methods as only matching the causes.
+func (m *ErrorMap[K]) matchesCauses() {}
+
+// mutableCauses marks m as getting more errors after it is wrapped.
+func (m *ErrorMap[K]) mutableCauses() {}
+
+// Is reports whether an error of m matches target, whatever the version of
+// Go.
+func (m *ErrorMap[K]) Is(target error) bool {
+	for _, err := range m.errs {
+		if goerrors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of m, sorted by key, that matches target,
+// whatever the version of Go.
+func (m *ErrorMap[K]) As(target interface{}) bool {
+	for _, err := range m.Unwrap() {
+		if goerrors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Format formats the errors of m sorted by key, with their stack traces for
+// %+v
+func (m *ErrorMap[K]) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatIndented(s, m) {
+				return
+			}
+			for i, k := range m.Keys() {
+				if i > 0 {
+					_, _ = io.WriteString(s, "\n")
+				}
+				_, _ = fmt.Fprintf(s, "%v: ", k)
+				formatCause(s, m.errs[k])
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, m.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", m.Error())
+	}
+}
+
+// keyLess reports whether the key a sorts before b.
+func keyLess(a, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.IsValid() && vb.IsValid() && va.Kind() == vb.Kind() {
+		switch va.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return va.Int() < vb.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return va.Uint() < vb.Uint()
+		case reflect.Float32, reflect.Float64:
+			return va.Float() < vb.Float()
+		case reflect.String:
+			return va.String() < vb.String()
+		}
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
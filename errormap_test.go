@@ -0,0 +1,87 @@
+//go:build go1.18
+// +build go1.18
+
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestErrorMap(t *testing.T) {
+	var m ErrorMap[string]
+	if m.ErrorOrNil() != nil || m.Len() != 0 || m.ByKey("a") != nil {
+		t.Errorf("empty ErrorMap has errors")
+	}
+	m.Add("c.csv", nil)
+	m.Add("b.csv", &os.PathError{Op: "open", Path: "b.csv", Err: os.ErrPermission})
+	m.Add("a.csv", io.EOF)
+	err := m.ErrorOrNil()
+	m.Add("a.csv", io.ErrUnexpectedEOF)
+	if got, want := err.Error(), "a.csv: EOF\nb.csv: open b.csv: permission denied"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !Is(err, io.EOF) || !Is(err, os.ErrPermission) || Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Is(%v) does not match the errors of the map", err)
+	}
+	var pathErr *os.PathError
+	if !As(err, &pathErr) || pathErr.Path != "b.csv" {
+		t.Errorf("As(%v, *os.PathError) = %v", err, pathErr)
+	}
+	if got := m.ByKey("a.csv"); !Is(got, io.ErrUnexpectedEOF) || !Is(got, io.EOF) {
+		t.Errorf("ByKey() = %v, want the joined errors", got)
+	}
+	if got, want := m.Keys(), []string{"a.csv", "b.csv"}; !reflect.DeepEqual(got, want) || m.Len() != 2 {
+		t.Errorf("Keys() = %q, want %q", got, want)
+	}
+}
+
+func TestErrorMapKeys(t *testing.T) {
+	var ints ErrorMap[int]
+	for _, k := range []int{10, -1, 9} {
+		ints.Add(k, io.EOF)
+	}
+	if got, want := ints.Keys(), []int{-1, 9, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+	type key struct{ a, b int }
+	var structs ErrorMap[key]
+	structs.Add(key{2, 1}, io.EOF)
+	structs.Add(key{1, 2}, io.EOF)
+	if got, want := structs.Error(), "{1 2}: EOF\n{2 1}: EOF"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestErrorMapFormat(t *testing.T) {
+	var m ErrorMap[string]
+	m.Add("b", io.EOF)
+	m.Add("a", New("timeout"))
+	got := fmt.Sprintf("%+v", &m)
+	want := "^a: timeout\n" +
+		"github.com/objenious/errors.TestErrorMapFormat\n" +
+		"\t.+/github.com/objenious/errors/errormap_test.go:63\n" +
+		"(?s:.*)\n" +
+		"b: EOF$"
+	if !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("%%+v:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestErrorMapIsCache(t *testing.T) {
+	var m ErrorMap[string]
+	err := Wrap(&m, "process")
+	var pathErr *os.PathError
+	if Is(err, io.EOF) || As(err, &pathErr) {
+		t.Fatalf("Is and As match an empty ErrorMap")
+	}
+	m.Add("a.csv", io.EOF)
+	m.Add("b.csv", &os.PathError{Op: "open", Path: "b.csv", Err: os.ErrNotExist})
+	if !Is(err, io.EOF) || !As(err, &pathErr) {
+		t.Errorf("Is and As return the results cached before the errors were added")
+	}
+}
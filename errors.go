@@ -53,12 +53,20 @@
 //     %+v   extended format. Each Frame of the error's StackTrace will
 //           be printed in detail.
 //
+// The precision and width of %+v alter the extended format:
+//
+//     %+.3v  print at most 3 frames of each stack trace, none for %+.0v.
+//     %+4v   indent every line but the first by 4 spaces, to print errors
+//            in indented blocks of text. Before, a width only added a
+//            newline before the errors created by WithStack; SetFormatCompat
+//            with LegacyWidth restores this behaviour.
+//
 // Retrieving the stack trace of an error or wrapper
 //
 // New, Errorf, Wrap, and Wrapf record a stack trace at the point they are
-// invoked. This information can be retrieved with the following interface:
+// invoked. This information can be retrieved with the StackTracer interface:
 //
-//     type stackTracer interface {
+//     type StackTracer interface {
 //             StackTrace() errors.StackTrace
 //     }
 //
@@ -70,14 +78,13 @@
 // the fmt.Formatter interface that can be used for printing information about
 // the stack trace of this error. For example:
 //
-//     if err, ok := err.(stackTracer); ok {
+//     if err, ok := errors.AsStackTracer(err); ok {
 //             for _, f := range err.StackTrace() {
 //                     fmt.Printf("%+s:%d\n", f, f)
 //             }
 //     }
 //
-// Although the stackTracer interface is not exported by this package, it is
-// considered a part of its stable public interface.
+// AsStackTracer also looks for a stack trace in the causes of err.
 //
 // See the documentation for Frame.Format for more details.
 package errors
@@ -86,15 +93,19 @@ import (
 	goerrors "errors"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // New returns an error with the supplied message.
 // New also records the stack trace at the point it was called.
 func New(message string) error {
 	return &withStack{
-		goerrors.New(message),
-		callers(),
-		"",
+		error:   goerrors.New(message),
+		stack:   callers(),
+		created: timestamp(),
 	}
 }
 
@@ -103,9 +114,9 @@ func New(message string) error {
 // Errorf also records the stack trace at the point it was called.
 func Errorf(format string, args ...interface{}) error {
 	return &withStack{
-		fmt.Errorf(format, args...),
-		callers(),
-		"",
+		error:   fmt.Errorf(format, args...),
+		stack:   callers(),
+		created: timestamp(),
 	}
 }
 
@@ -115,21 +126,50 @@ func WithStack(err error) error {
 	if err == nil {
 		return nil
 	}
-	return &withStack{
-		err,
-		callers(),
-		"",
-	}
+	return stackError(err, callers(), nil)
 }
 
 type withStack struct {
 	error
 	*stack
 	msg string
+	// lazy replaces msg for errors created by Wraplazy.
+	lazy *lazyMessage
+	// wrapped is set when error was supplied by the caller, rather than
+	// created by this package. Its message is then prefixed by msg.
+	wrapped bool
+	// created is the time the error was created, when timestamps are
+	// recorded.
+	created time.Time
+	// factory is the Factory that created the error, if any.
+	factory *Factory
+	// matches holds the *matchCache of the results of Is and As, allocated
+	// by the first of them.
+	matches atomic.Value
+
+	// rendered caches the result of Error, so that an error logged at
+	// several layers only pays for its message once.
+	once     sync.Once
+	rendered string
+}
+
+// Error returns the message of the error, rendering it on the first call only.
+func (w *withStack) Error() string {
+	w.once.Do(func() {
+		if msg := w.message(); msg != "" {
+			w.rendered = msg + messageSeparator() + w.error.Error()
+		} else {
+			w.rendered = w.error.Error()
+		}
+	})
+	return w.rendered
 }
 
 // Unwrap unwraps one level of this error
 func (w *withStack) Unwrap() error {
+	if w.wrapped {
+		return w.error
+	}
 	return goerrors.Unwrap(w.error)
 }
 
@@ -138,53 +178,125 @@ func (w *withStack) Cause() error {
 	return w.Unwrap()
 }
 
+// multi returns the error created by Errorf, if it wraps several errors.
+// Unwrap can't return them, so Is and As look them up instead.
+func (w *withStack) multi() (error, bool) {
+	if w.wrapped {
+		return nil, false
+	}
+	_, ok := w.error.(interface{ Unwrap() []error })
+	return w.error, ok
+}
+
+// matchesCauses marks the Is and As methods as only matching the causes.
+func (w *withStack) matchesCauses() {}
+
+// Is reports whether any error wrapped by this error matches target, when it
+// wraps several errors.
+func (w *withStack) Is(target error) bool {
+	if err, ok := w.multi(); ok {
+		return goerrors.Is(err, target)
+	}
+	return false
+}
+
+// As finds the first error wrapped by this error that matches target, when it
+// wraps several errors.
+func (w *withStack) As(target interface{}) bool {
+	if err, ok := w.multi(); ok {
+		return goerrors.As(err, target)
+	}
+	return false
+}
+
+// message returns the message added by this wrapper, if any.
+func (w *withStack) message() string {
+	if w.lazy != nil {
+		return w.lazy.String()
+	}
+	return w.msg
+}
+
 // Format formats the error with stack trace if available
 func (w *withStack) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		if s.Flag('+') {
-			if w.msg == "" {
-				br := ""
-				if w, ok := s.Width(); ok && w > 0 {
-					br = "\n"
-				}
-				_, _ = fmt.Fprintf(s, "%s%+v", br, w.error) // recursive : go to bottom
-			} else {
-				if cause := w.Cause(); cause != nil {
-					_, _ = fmt.Fprintf(s, "%+v", cause) // recursive : go to bottom
-
-					if causeWithStack, ok := cause.(*withStack); ok && w.msg != "" && causeWithStack.msg != w.msg || w.msg != "" && cause.Error() != w.msg {
-						_, _ = fmt.Fprintf(s, "\n%+v", w.msg)
-					}
-				} else {
-					// root format
-					_, _ = fmt.Fprintf(s, "%+v", w.error)
-				}
+			if pkgErrorsFormat() {
+				w.formatPkgErrors(s)
+				return
 			}
-			w.stack.Format(s, verb)
+			if formatIndented(s, w) {
+				return
+			}
+			formatChain(s, w)
 			return
 		}
 		fallthrough
 	case 's':
-		_, _ = io.WriteString(s, w.error.Error())
+		_, _ = io.WriteString(s, w.Error())
 	case 'q':
 		_, _ = fmt.Fprintf(s, "%q", w.Error())
 	}
 }
 
+// formatExtended prints err with %+v. Errors that don't implement
+// fmt.Formatter, such as those created by fmt.Errorf with %w or by
+// errors.Join, but whose causes carry a stack trace are expanded, so that
+// those stack traces are not lost: the causes are printed first, in order,
+// followed by the message added by err.
+func formatExtended(s io.Writer, err error) {
+	if _, ok := err.(fmt.Formatter); !ok {
+		if c := findCycle(err); c != nil {
+			_, _ = fmt.Fprintf(s, "%s\n%s", err, c)
+			return
+		}
+		if causes := causes(err); hasStackTrace(causes) {
+			msgs := make([]string, len(causes))
+			for i, cause := range causes {
+				if i > 0 {
+					_, _ = io.WriteString(s, "\n")
+				}
+				formatCause(s, cause)
+				msgs[i] = cause.Error()
+			}
+			msg := err.Error()
+			if len(causes) == 1 {
+				msg = strings.TrimSuffix(msg, ": "+msgs[0])
+			}
+			if msg != strings.Join(msgs, "\n") {
+				_, _ = fmt.Fprintf(s, "\n%s", msg)
+			}
+			return
+		}
+	}
+	if _, ok := err.(fmt.Formatter); ok {
+		_, _ = fmt.Fprintf(s, extendedVerb(s), err)
+		return
+	}
+	_, _ = fmt.Fprintf(s, "%+v", err)
+}
+
+// hasStackTrace reports whether any of errs carries a stack trace.
+func hasStackTrace(errs []error) bool {
+	for _, err := range errs {
+		if GetStackTrace(err) != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // Wrap returns an error annotating err with a stack trace
 // at the point Wrap is called, and the supplied message.
+// The message of the returned error is the supplied message, followed by
+// the message separator and the message of err.
 // If err is nil, Wrap returns nil.
 func Wrap(err error, message string) error {
 	if err == nil {
 		return nil
 	}
-	err = fmt.Errorf("%s: %w", message, err)
-	return &withStack{
-		err,
-		callers(),
-		message,
-	}
+	return wrapError(err, callers(), message, nil)
 }
 
 // Wrapf returns an error annotating err with a stack trace
@@ -194,14 +306,65 @@ func Wrapf(err error, format string, args ...interface{}) error {
 	if err == nil {
 		return nil
 	}
-	msg := fmt.Sprintf(format, args...)
-	args = append(args, err)
-	err = fmt.Errorf(format+": %w", args...)
-	return &withStack{
-		err,
-		callers(),
-		msg,
+	return wrapError(err, callers(), fmt.Sprintf(format, args...), nil)
+}
+
+// wrapError returns a wrapper of err with the stack trace st and the message
+// msg, as Wrap and Wrapf do, for the factory f if not nil. All the functions
+// wrapping errors with a message build their wrappers with it, so that they
+// are checked the same way: see checkMessage and checkWrap.
+func wrapError(err error, st *stack, msg string, f *Factory) error {
+	w := &withStack{
+		error:   err,
+		stack:   st,
+		created: timestamp(),
+		msg:     msg,
+		wrapped: true,
+		factory: f,
 	}
+	checkMessage(w)
+	return checkWrap(w)
+}
+
+// stackError returns a wrapper of err with the stack trace st, as WithStack
+// does, for the factory f if not nil: see checkWrap.
+func stackError(err error, st *stack, f *Factory) error {
+	return checkWrap(&withStack{
+		error:   err,
+		stack:   st,
+		created: timestamp(),
+		wrapped: true,
+		factory: f,
+	})
+}
+
+// checkWrap returns w, annotated with the default fields of its factory if
+// any, or its cause if w is a duplicate wrap to coalesce, see dedupe.
+func checkWrap(w *withStack) error {
+	err := dedupe(w)
+	if err != w || w.factory == nil {
+		return err
+	}
+	return w.factory.annotate(w)
+}
+
+// separator holds the string set by SetMessageSeparator.
+var separator atomic.Value
+
+// SetMessageSeparator sets the string inserted between the message of a
+// wrapper and the message of its cause, ": " by default. Messages are
+// rendered once, errors created before calling SetMessageSeparator may keep
+// the previous separator. It does not apply to the messages formatted by
+// Errorf and fmt.Errorf.
+func SetMessageSeparator(sep string) {
+	separator.Store(sep)
+}
+
+func messageSeparator() string {
+	if sep, ok := separator.Load().(string); ok {
+		return sep
+	}
+	return ": "
 }
 
 // Cause returns the underlying cause of the error, if possible.
@@ -214,16 +377,40 @@ func Wrapf(err error, format string, args ...interface{}) error {
 //
 // If the error does not implement Cause, the original error will
 // be returned. If the error is nil, nil will be returned without further
-// investigation.
+// investigation. For errors wrapping several errors, such as those
+// created by errors.Join, the cause of the first one is returned.
+// For chains with a cycle, the error of CheckCycle is returned.
 func Cause(err error) error {
-	for {
-		unwrap := goerrors.Unwrap(err)
-		if unwrap == nil {
+	chain := err
+	for depth := 0; ; depth++ {
+		causes := causes(err)
+		if len(causes) == 0 {
 			if wrap, ok := err.(*withStack); ok {
 				return wrap.error
 			}
 			return err
 		}
-		err = unwrap
+		if depth == maxDepth {
+			return acyclic(chain)
+		}
+		err = causes[0]
+	}
+}
+
+// causes returns the errors wrapped by err: none, one, or several for errors
+// such as those created by errors.Join or by fmt.Errorf with several %w verbs.
+func causes(err error) []error {
+	switch err := err.(type) {
+	case *withStack:
+		if err.wrapped {
+			return []error{err.error}
+		}
+		return causes(err.error)
+	case interface{ Unwrap() []error }:
+		return err.Unwrap()
+	}
+	if cause := goerrors.Unwrap(err); cause != nil {
+		return []error{cause}
 	}
+	return nil
 }
@@ -211,3 +211,92 @@ func TestErrorEquality(t *testing.T) {
 		}
 	}
 }
+
+type countingError struct{ calls int }
+
+func (e *countingError) Error() string {
+	e.calls++
+	return fmt.Sprintf("call %d", e.calls)
+}
+
+func TestErrorCached(t *testing.T) {
+	cause := &countingError{}
+	err := Wrap(WithStack(cause), "wrapped")
+	for i := 0; i < 3; i++ {
+		if got, want := err.Error(), "wrapped: call 1"; got != want {
+			t.Fatalf("Error(): got %q, want %q", got, want)
+		}
+		if got, want := fmt.Sprintf("%s", err), "wrapped: call 1"; got != want {
+			t.Fatalf("Sprintf(%%s): got %q, want %q", got, want)
+		}
+	}
+	if cause.calls != 1 {
+		t.Errorf("cause.Error() called %d times, want 1", cause.calls)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = err.Error()
+	})
+	if allocs != 0 {
+		t.Errorf("Error() allocates %v times per call, want 0", allocs)
+	}
+}
+
+func TestWithStackIs(t *testing.T) {
+	err := WithStack(io.EOF)
+	if !goerrors.Is(err, io.EOF) {
+		t.Errorf("errors.Is(WithStack(io.EOF), io.EOF) = false")
+	}
+	if got := goerrors.Unwrap(err); got != io.EOF {
+		t.Errorf("errors.Unwrap(WithStack(io.EOF)): got %v, want io.EOF", got)
+	}
+	var target *countingError
+	if !goerrors.As(WithStack(&countingError{}), &target) {
+		t.Errorf("errors.As(WithStack(&countingError{}), &target) = false")
+	}
+}
+
+func TestSetMessageSeparator(t *testing.T) {
+	SetMessageSeparator(" | ")
+	defer SetMessageSeparator(": ")
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{Wrap(io.EOF, "read: file"), "read: file | EOF"},
+		{Wrapf(Wrap(io.EOF, "read"), "client %d", 1), "client 1 | read | EOF"},
+		{Wraplazy(io.EOF, "key=%s", "value"), "key=value | EOF"},
+		{WithStack(Wrap(io.EOF, "read")), "read | EOF"},
+		{Errorf("read: %w", io.EOF), "read: EOF"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("got: %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	if got := Join(); got != nil {
+		t.Errorf("Join(): got %#v, want nil", got)
+	}
+	if got := Join(nil, nil); got != nil {
+		t.Errorf("Join(nil, nil): got %#v, want nil", got)
+	}
+	first, second := New("first"), io.EOF
+	err := Join(first, nil, second)
+	if got, want := err.Error(), "first\nEOF"; got != want {
+		t.Errorf("Join.Error(): got %q, want %q", got, want)
+	}
+	if got := Cause(Wrap(err, "wrapped")); got != Cause(first) {
+		t.Errorf("Cause(Wrap(Join())): got %v, want the cause of the first error", got)
+	}
+	if got := fmt.Sprintf("%q", err); got != `"first\nEOF"` {
+		t.Errorf("Join %%q: got %s", got)
+	}
+	got, _ := parseBlocks(fmt.Sprintf("%+v", err), false)
+	if len(got) != 3 || got[0] != "first" || got[2] != "EOF" || GetStackTrace(err) != GetStackTrace(first) {
+		t.Errorf("Join %%+v: got %q, want each error with its stack trace", got)
+	}
+}
@@ -0,0 +1,77 @@
+// Package errretry retries operations whose errors are marked as retryable
+// with github.com/objenious/errors.
+package errretry
+
+import (
+	"context"
+	"time"
+
+	"github.com/objenious/errors"
+)
+
+// Policy configures how Do retries an operation.
+type Policy struct {
+	// MaxAttempts is the maximum number of calls to the operation; values
+	// below 1 mean a single attempt.
+	MaxAttempts int
+	// Delay is the wait before the first retry, unless the error has a
+	// RetryAfter delay.
+	Delay time.Duration
+	// Multiplier scales the delay after each retry; values below 1 keep the
+	// delay constant.
+	Multiplier float64
+	// MaxDelay caps the delay between attempts, if positive.
+	MaxDelay time.Duration
+}
+
+// Do calls fn until it succeeds, it returns an error that is not retryable
+// according to errors.IsRetryable, ctx is done, or policy.MaxAttempts is
+// reached. Between attempts, it waits for the errors.RetryAfter delay of
+// the last error if any, or else the backoff delay of policy.
+//
+// The error returned joins the errors of all attempts, each wrapped with its
+// attempt number and a stack trace, with the error of ctx if it ended the
+// retries.
+func Do(ctx context.Context, fn func(ctx context.Context) error, policy Policy) error {
+	var errs []error
+	delay := policy.Delay
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, errors.Wrapf(err, "attempt %d", attempt))
+		if attempt >= policy.MaxAttempts || !errors.IsRetryable(err) {
+			break
+		}
+		wait := delay
+		if d, ok := errors.RetryAfter(err); ok {
+			wait = d
+		}
+		if policy.MaxDelay > 0 && wait > policy.MaxDelay {
+			wait = policy.MaxDelay
+		}
+		if err := sleep(ctx, wait); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if policy.Multiplier > 1 {
+			delay = time.Duration(float64(delay) * policy.Multiplier)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
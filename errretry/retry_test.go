@@ -0,0 +1,89 @@
+package errretry
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/objenious/errors"
+)
+
+func failing(errs ...error) (func(context.Context) error, *int) {
+	calls := 0
+	return func(context.Context) error {
+		calls++
+		if calls > len(errs) {
+			return nil
+		}
+		return errs[calls-1]
+	}, &calls
+}
+
+func TestDoSuccess(t *testing.T) {
+	retryable := errors.WithRetryable(io.EOF, true)
+	fn, calls := failing(retryable, retryable)
+	if err := Do(context.Background(), fn, Policy{MaxAttempts: 3}); err != nil {
+		t.Fatalf("Do: got %v, want nil", err)
+	}
+	if *calls != 3 {
+		t.Errorf("Do: got %d calls, want 3", *calls)
+	}
+}
+
+func TestDoMaxAttempts(t *testing.T) {
+	retryable := errors.WithRetryable(io.EOF, true)
+	fn, calls := failing(retryable, retryable, retryable)
+	err := Do(context.Background(), fn, Policy{MaxAttempts: 2})
+	if *calls != 2 {
+		t.Errorf("Do: got %d calls, want 2", *calls)
+	}
+	if got, want := err.Error(), "attempt 1: EOF\nattempt 2: EOF"; got != want {
+		t.Errorf("Do: got %q, want %q", got, want)
+	}
+	if !stderrors.Is(err, io.EOF) {
+		t.Errorf("Do: errors.Is(err, io.EOF) = false")
+	}
+	if got := fmt.Sprintf("%+v", err); strings.Count(got, "errretry.Do") != 2 {
+		t.Errorf("Do: got %q, want a stack trace per attempt", got)
+	}
+}
+
+func TestDoNotRetryable(t *testing.T) {
+	fn, calls := failing(io.EOF, io.EOF)
+	err := Do(context.Background(), fn, Policy{MaxAttempts: 3})
+	if *calls != 1 {
+		t.Errorf("Do: got %d calls, want 1", *calls)
+	}
+	if got, want := err.Error(), "attempt 1: EOF"; got != want {
+		t.Errorf("Do: got %q, want %q", got, want)
+	}
+}
+
+func TestDoRetryAfter(t *testing.T) {
+	fn, _ := failing(errors.WithRetryAfter(io.EOF, 20*time.Millisecond))
+	start := time.Now()
+	if err := Do(context.Background(), fn, Policy{MaxAttempts: 2, Delay: time.Hour}); err != nil {
+		t.Fatalf("Do: got %v, want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond || elapsed > time.Minute {
+		t.Errorf("Do: waited %v, want the RetryAfter delay", elapsed)
+	}
+}
+
+func TestDoContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	retryable := errors.WithRetryable(io.EOF, true)
+	fn, calls := failing(retryable, retryable)
+	err := Do(ctx, fn, Policy{MaxAttempts: 3, Delay: time.Hour})
+	if *calls != 1 {
+		t.Errorf("Do: got %d calls, want 1", *calls)
+	}
+	if !stderrors.Is(err, context.DeadlineExceeded) || !stderrors.Is(err, io.EOF) {
+		t.Errorf("Do: got %v, want the attempt and context errors", err)
+	}
+}
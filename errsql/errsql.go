@@ -0,0 +1,194 @@
+// Package errsql classifies the errors of SQL drivers, such as unique or
+// foreign key violations, without depending on the drivers: it recognizes
+// the errors of github.com/lib/pq, github.com/jackc/pgx and
+// github.com/go-sql-driver/mysql by their SQLSTATE codes and error numbers.
+//
+// Importing the package also teaches errors.InferKind that sql.ErrNoRows is
+// of kind errors.KindNotFound.
+package errsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	stderrors "errors"
+	"reflect"
+	"strings"
+
+	"github.com/objenious/errors"
+)
+
+func init() {
+	errors.RegisterKindFunc(inferKind)
+}
+
+// inferKind returns the kind of sql.ErrNoRows, for errors.InferKind.
+func inferKind(err error) (errors.Kind, bool) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.KindNotFound, true
+	}
+	return errors.KindUnknown, false
+}
+
+// MySQL error numbers.
+const (
+	mysqlDupEntry        = 1062
+	mysqlRowIsReferenced = 1451
+	mysqlNoReferencedRow = 1452
+	mysqlLockWaitTimeout = 1205
+	mysqlLockDeadlock    = 1213
+)
+
+// SQLState returns the SQLSTATE code of the first driver error of the chain
+// of err that has one: errors with a SQLState method, as in pgx and recent
+// versions of lib/pq, or with a Code string field, as in lib/pq.
+func SQLState(err error) (string, bool) {
+	var state string
+	found := walk(err, func(err error) bool {
+		if s, ok := err.(interface{ SQLState() string }); ok {
+			state = s.SQLState()
+			return state != ""
+		}
+		if f, ok := field(err, "Code"); ok && f.Kind() == reflect.String && f.Len() == 5 {
+			state = f.String()
+			return true
+		}
+		return false
+	})
+	return state, found
+}
+
+// mysqlNumber returns the error number of the first MySQL error of the chain
+// of err: an error with a Number uint16 field.
+func mysqlNumber(err error) (uint64, bool) {
+	var number uint64
+	found := walk(err, func(err error) bool {
+		if f, ok := field(err, "Number"); ok && f.Kind() == reflect.Uint16 {
+			number = f.Uint()
+			return true
+		}
+		return false
+	})
+	return number, found
+}
+
+// IsUniqueViolation reports whether err is caused by the violation of a
+// unique constraint.
+func IsUniqueViolation(err error) bool {
+	if state, ok := SQLState(err); ok {
+		return state == "23505"
+	}
+	n, ok := mysqlNumber(err)
+	return ok && n == mysqlDupEntry
+}
+
+// IsForeignKeyViolation reports whether err is caused by the violation of a
+// foreign key constraint.
+func IsForeignKeyViolation(err error) bool {
+	if state, ok := SQLState(err); ok {
+		return state == "23503"
+	}
+	n, ok := mysqlNumber(err)
+	return ok && (n == mysqlRowIsReferenced || n == mysqlNoReferencedRow)
+}
+
+// IsSerializationFailure reports whether err is caused by a transaction
+// that could not be serialized with concurrent transactions, including
+// deadlocks: the transaction can be retried.
+func IsSerializationFailure(err error) bool {
+	if state, ok := SQLState(err); ok {
+		return state == "40001" || state == "40P01"
+	}
+	n, ok := mysqlNumber(err)
+	return ok && (n == mysqlLockDeadlock || n == mysqlLockWaitTimeout)
+}
+
+// IsConnectionError reports whether err is caused by a failed or lost
+// connection to the database: the SQLSTATE class 08, the shutdown of the
+// server, driver.ErrBadConn, sql.ErrConnDone, or the errors of MySQL
+// connections such as "invalid connection".
+func IsConnectionError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	if state, ok := SQLState(err); ok {
+		return strings.HasPrefix(state, "08") || state == "57P01" || state == "57P02" || state == "57P03"
+	}
+	return walk(err, func(err error) bool {
+		// mysql.ErrInvalidConn is not exported as a type.
+		return err.Error() == "invalid connection"
+	})
+}
+
+// Classify annotates err with its kind and retryability, according to the
+// helpers of this package:
+//
+//   - sql.ErrNoRows is of kind errors.KindNotFound,
+//   - unique violations are of kind errors.KindAlreadyExists,
+//   - foreign key violations are of kind errors.KindConflict,
+//   - serialization failures are of kind errors.KindConflict, and retryable,
+//   - connection errors are of kind errors.KindUnavailable, and retryable.
+//
+// Other errors are returned unchanged, as is nil.
+func Classify(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, sql.ErrNoRows):
+		return errors.WithKind(err, errors.KindNotFound)
+	case IsUniqueViolation(err):
+		return errors.WithKind(err, errors.KindAlreadyExists)
+	case IsForeignKeyViolation(err):
+		return errors.WithKind(err, errors.KindConflict)
+	case IsSerializationFailure(err):
+		return errors.WithRetryable(errors.WithKind(err, errors.KindConflict), true)
+	case IsConnectionError(err):
+		return errors.WithRetryable(errors.WithKind(err, errors.KindUnavailable), true)
+	}
+	return err
+}
+
+// walk calls f with the errors of the chain of err, until f returns true. It
+// calls f with errors.MaxDepth errors at most, so that it ends on chains
+// with a cycle.
+func walk(err error, f func(error) bool) bool {
+	n := errors.MaxDepth
+	return walkN(err, f, &n)
+}
+
+// walkN is walk, decrementing n for each error walked, until it is negative.
+func walkN(err error, f func(error) bool, n *int) bool {
+	for err != nil {
+		if *n--; *n < 0 {
+			return false
+		}
+		if f(err) {
+			return true
+		}
+		if multi, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, err := range multi.Unwrap() {
+				if walkN(err, f, n) {
+					return true
+				}
+			}
+			return false
+		}
+		err = stderrors.Unwrap(err)
+	}
+	return false
+}
+
+// field returns the exported field name of the struct err points to.
+func field(err error, name string) (reflect.Value, bool) {
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	f := v.FieldByName(name)
+	return f, f.IsValid()
+}
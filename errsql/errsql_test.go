@@ -0,0 +1,117 @@
+package errsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/objenious/errors"
+)
+
+// pqError mimics *pq.Error.
+type pqError struct {
+	Code    string
+	Message string
+}
+
+func (e *pqError) Error() string { return "pq: " + e.Message }
+
+// pgError mimics *pgconn.PgError.
+type pgError struct {
+	Code    string
+	Message string
+}
+
+func (e *pgError) Error() string    { return "ERROR: " + e.Message }
+func (e *pgError) SQLState() string { return e.Code }
+
+// mysqlError mimics *mysql.MySQLError.
+type mysqlError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *mysqlError) Error() string { return fmt.Sprintf("Error %d: %s", e.Number, e.Message) }
+
+func TestInferKind(t *testing.T) {
+	if got := errors.InferKind(errors.Wrap(sql.ErrNoRows, "get device")); got != errors.KindNotFound {
+		t.Errorf("InferKind(sql.ErrNoRows) = %q, want %q", got, errors.KindNotFound)
+	}
+	if got := errors.InferKind(io.EOF); got != errors.KindUnknown {
+		t.Errorf("InferKind(io.EOF) = %q, want %q", got, errors.KindUnknown)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err                             error
+		unique, fk, serialization, conn bool
+		kind                            errors.Kind
+		retryable                       bool
+	}{
+		{err: io.EOF},
+		{err: sql.ErrNoRows, kind: errors.KindNotFound},
+		{err: &pqError{Code: "23505"}, unique: true, kind: errors.KindAlreadyExists},
+		{err: errors.Wrap(&pgError{Code: "23505"}, "insert"), unique: true, kind: errors.KindAlreadyExists},
+		{err: &mysqlError{Number: 1062}, unique: true, kind: errors.KindAlreadyExists},
+		{err: &pgError{Code: "23503"}, fk: true, kind: errors.KindConflict},
+		{err: &mysqlError{Number: 1452}, fk: true, kind: errors.KindConflict},
+		{err: &pqError{Code: "40001"}, serialization: true, kind: errors.KindConflict, retryable: true},
+		{err: &mysqlError{Number: 1213}, serialization: true, kind: errors.KindConflict, retryable: true},
+		{err: &pgError{Code: "08006"}, conn: true, kind: errors.KindUnavailable, retryable: true},
+		{err: errors.Wrap(driver.ErrBadConn, "query"), conn: true, kind: errors.KindUnavailable, retryable: true},
+		{err: errors.New("invalid connection"), conn: true, kind: errors.KindUnavailable, retryable: true},
+		{err: &pgError{Code: "42601"}},
+		{err: &mysqlError{Number: 1064}},
+	}
+	for i, tt := range tests {
+		if got := IsUniqueViolation(tt.err); got != tt.unique {
+			t.Errorf("test %d: IsUniqueViolation(%v): got %t", i+1, tt.err, got)
+		}
+		if got := IsForeignKeyViolation(tt.err); got != tt.fk {
+			t.Errorf("test %d: IsForeignKeyViolation(%v): got %t", i+1, tt.err, got)
+		}
+		if got := IsSerializationFailure(tt.err); got != tt.serialization {
+			t.Errorf("test %d: IsSerializationFailure(%v): got %t", i+1, tt.err, got)
+		}
+		if got := IsConnectionError(tt.err); got != tt.conn {
+			t.Errorf("test %d: IsConnectionError(%v): got %t", i+1, tt.err, got)
+		}
+		err := Classify(tt.err)
+		if got := errors.KindOf(err); got != tt.kind {
+			t.Errorf("test %d: KindOf(Classify(%v)): got %q, want %q", i+1, tt.err, got, tt.kind)
+		}
+		if got := errors.IsRetryable(err); got != tt.retryable {
+			t.Errorf("test %d: IsRetryable(Classify(%v)): got %t", i+1, tt.err, got)
+		}
+	}
+	if err := Classify(nil); err != nil {
+		t.Errorf("Classify(nil): got %v, want nil", err)
+	}
+	if state, ok := SQLState(errors.Wrap(&pqError{Code: "23505"}, "insert")); !ok || state != "23505" {
+		t.Errorf("SQLState: got %q, %t, want 23505", state, ok)
+	}
+}
+
+// loopError is an error whose causes are set after it is wrapped.
+type loopError struct {
+	causes []error
+}
+
+func (e *loopError) Error() string { return "loop" }
+
+func (e *loopError) Unwrap() []error { return e.causes }
+
+func TestClassifyCycle(t *testing.T) {
+	loop := &loopError{}
+	err := errors.Wrap(loop, "query")
+	loop.causes = []error{err, io.EOF}
+	if _, ok := SQLState(err); ok || IsUniqueViolation(err) || IsConnectionError(err) {
+		t.Errorf("a chain with a cycle is classified")
+	}
+	if got := Classify(err); got != err {
+		t.Errorf("Classify() = %v", got)
+	}
+}
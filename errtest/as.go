@@ -0,0 +1,20 @@
+//go:build go1.18
+// +build go1.18
+
+package errtest
+
+import (
+	"errors"
+	"testing"
+)
+
+// AssertAs reports a test error unless an error in the chain of err is a T,
+// and returns it.
+func AssertAs[T error](t testing.TB, err error) T {
+	t.Helper()
+	var target T
+	if !errors.As(err, &target) {
+		t.Errorf("errors.As(%q, %T) = false", message(err), &target)
+	}
+	return target
+}
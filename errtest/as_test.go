@@ -0,0 +1,25 @@
+//go:build go1.18
+// +build go1.18
+
+package errtest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/objenious/errors"
+)
+
+func TestAssertAs(t *testing.T) {
+	r := &recorder{TB: t}
+	pathErr := &os.PathError{Op: "open", Path: "file", Err: os.ErrNotExist}
+	if got := AssertAs[*os.PathError](r, errors.Wrap(pathErr, "load")); got != pathErr {
+		t.Errorf("AssertAs: got %v, want %v", got, pathErr)
+	}
+	if got := AssertAs[*os.PathError](r, errors.New("error")); got != nil {
+		t.Errorf("AssertAs: got %v, want nil", got)
+	}
+	if len(r.failures) != 1 {
+		t.Errorf("got %d failures, want 1: %q", len(r.failures), r.failures)
+	}
+}
@@ -0,0 +1,79 @@
+// Package errtest provides helpers to test errors built with
+// github.com/objenious/errors, without matching their formatted stack traces.
+package errtest
+
+import (
+	goerrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/objenious/errors"
+)
+
+// AssertIs reports a test error unless errors.Is(err, target).
+func AssertIs(t testing.TB, err, target error) bool {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Errorf("errors.Is(%q, %q) = false", message(err), message(target))
+		return false
+	}
+	return true
+}
+
+// AssertMessage reports a test error unless err.Error() is want.
+func AssertMessage(t testing.TB, err error, want string) bool {
+	t.Helper()
+	if err == nil {
+		t.Errorf("got nil error, want %q", want)
+		return false
+	}
+	if got := err.Error(); got != want {
+		t.Errorf("got error %q, want %q", got, want)
+		return false
+	}
+	return true
+}
+
+// Diff compares the chains of got and want, level by level, and describes
+// their differences. It returns an empty string if the chains carry the same
+// messages. Stack traces are ignored, as are levels that only add a stack
+// trace to their cause.
+func Diff(got, want error) string {
+	gotChain, wantChain := chain(got), chain(want)
+	var diffs []string
+	for i := 0; i < len(gotChain) || i < len(wantChain); i++ {
+		g, w := "<none>", "<none>"
+		if i < len(gotChain) {
+			g = fmt.Sprintf("%q", gotChain[i])
+		}
+		if i < len(wantChain) {
+			w = fmt.Sprintf("%q", wantChain[i])
+		}
+		if g != w {
+			diffs = append(diffs, fmt.Sprintf("depth %d:\n- want: %s\n+ got:  %s", i, w, g))
+		}
+	}
+	return strings.Join(diffs, "\n")
+}
+
+// chain returns the messages of each level of err, from outermost to
+// innermost, skipping levels which don't change the message. It stops after
+// errors.MaxDepth levels, on chains with a cycle.
+func chain(err error) []string {
+	var msgs []string
+	for depth := 0; err != nil && depth < errors.MaxDepth; depth++ {
+		if msg := err.Error(); len(msgs) == 0 || msgs[len(msgs)-1] != msg {
+			msgs = append(msgs, msg)
+		}
+		err = goerrors.Unwrap(err)
+	}
+	return msgs
+}
+
+func message(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
@@ -0,0 +1,94 @@
+package errtest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/objenious/errors"
+)
+
+// recorder is a testing.TB recording failures instead of reporting them.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertIs(t *testing.T) {
+	r := &recorder{TB: t}
+	if !AssertIs(r, errors.Wrap(io.EOF, "read"), io.EOF) {
+		t.Errorf("AssertIs(Wrap(io.EOF), io.EOF) = false")
+	}
+	if AssertIs(r, errors.New("EOF"), io.EOF) {
+		t.Errorf("AssertIs(New(\"EOF\"), io.EOF) = true")
+	}
+	if len(r.failures) != 1 {
+		t.Errorf("got %d failures, want 1: %q", len(r.failures), r.failures)
+	}
+}
+
+func TestAssertMessage(t *testing.T) {
+	r := &recorder{TB: t}
+	if !AssertMessage(r, errors.Wrap(io.EOF, "read"), "read: EOF") {
+		t.Errorf("AssertMessage(Wrap(io.EOF, \"read\"), \"read: EOF\") = false")
+	}
+	if AssertMessage(r, nil, "EOF") {
+		t.Errorf("AssertMessage(nil, \"EOF\") = true")
+	}
+	if AssertMessage(r, io.EOF, "read: EOF") {
+		t.Errorf("AssertMessage(io.EOF, \"read: EOF\") = true")
+	}
+	if len(r.failures) != 2 {
+		t.Errorf("got %d failures, want 2: %q", len(r.failures), r.failures)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		got, want error
+		diff      string
+	}{
+		{nil, nil, ""},
+		{errors.Wrap(io.EOF, "read"), errors.Wrap(io.EOF, "read"), ""},
+		{errors.Wrap(errors.WithStack(io.EOF), "read"), errors.Wrap(io.EOF, "read"), ""},
+		{errors.New("error"), fmt.Errorf("error"), ""},
+		{errors.Wrap(io.EOF, "read"), errors.Wrap(io.EOF, "write"),
+			"depth 0:\n- want: \"write: EOF\"\n+ got:  \"read: EOF\""},
+		{errors.Wrap(io.EOF, "read"), io.EOF,
+			"depth 0:\n- want: \"EOF\"\n+ got:  \"read: EOF\"\n" +
+				"depth 1:\n- want: <none>\n+ got:  \"EOF\""},
+	}
+	for i, tt := range tests {
+		if got := Diff(tt.got, tt.want); got != tt.diff {
+			t.Errorf("test %d: Diff(%v, %v):\n got: %q\nwant: %q", i+1, tt.got, tt.want, got, tt.diff)
+		}
+	}
+}
+
+// loopError is an error whose cause is set after it is wrapped.
+type loopError struct {
+	cause error
+}
+
+func (e *loopError) Error() string { return "loop" }
+
+func (e *loopError) Unwrap() error { return e.cause }
+
+func TestDiffCycle(t *testing.T) {
+	loop := &loopError{}
+	err := errors.Wrap(loop, "read")
+	loop.cause = err
+	if got := Diff(err, err); got != "" {
+		t.Errorf("Diff() = %q", got)
+	}
+	if got := Diff(err, loop); !strings.HasPrefix(got, "depth 0:") {
+		t.Errorf("Diff() = %q", got)
+	}
+}
@@ -0,0 +1,44 @@
+package errtest
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// frameFileR matches the file line of a frame printed with %+v.
+var frameFileR = regexp.MustCompile(`^\t(.+?)(:\d+)?$`)
+
+// Normalize rewrites the stack frames of an error formatted with %+v so that
+// the output doesn't depend on where the sources are located, making it
+// suitable for golden files. The file of each frame is replaced by the
+// import path of its function's package followed by the file name, and line
+// numbers are removed:
+//
+//	github.com/objenious/errors.TestNormalize
+//		/home/user/src/errors/normalize_test.go:12
+//
+// becomes
+//
+//	github.com/objenious/errors.TestNormalize
+//		github.com/objenious/errors/normalize_test.go
+func Normalize(s string) string {
+	lines := strings.Split(s, "\n")
+	for i := 1; i < len(lines); i++ {
+		m := frameFileR.FindStringSubmatch(lines[i])
+		if m == nil || strings.HasPrefix(lines[i-1], "\t") {
+			continue
+		}
+		lines[i] = "\t" + path.Join(pkgpath(lines[i-1]), path.Base(m[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pkgpath returns the import path of the package of the function name.
+func pkgpath(name string) string {
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
@@ -0,0 +1,50 @@
+package errtest
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/objenious/errors"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"error", "error"},
+		{"error\n" +
+			"github.com/objenious/errors/errtest.TestNormalize\n" +
+			"\t/home/user/src/github.com/objenious/errors/errtest/normalize_test.go:25\n" +
+			"main.main\n" +
+			"\t/tmp/main.go:10\n" +
+			"runtime.main\n" +
+			"\tC:/go/src/runtime/proc.go:203",
+			"error\n" +
+				"github.com/objenious/errors/errtest.TestNormalize\n" +
+				"\tgithub.com/objenious/errors/errtest/normalize_test.go\n" +
+				"main.main\n" +
+				"\tmain/main.go\n" +
+				"runtime.main\n" +
+				"\truntime/proc.go"},
+		{"github.com/objenious/errors.(*X).ptr\n\t/src/stack_test.go:20",
+			"github.com/objenious/errors.(*X).ptr\n\tgithub.com/objenious/errors/stack_test.go"},
+	}
+	for i, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("test %d: Normalize(%q):\n got: %q\nwant: %q", i+1, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeError(t *testing.T) {
+	got := Normalize(fmt.Sprintf("%+v", errors.Wrap(io.EOF, "read")))
+	want := "EOF\n" +
+		"read\n" +
+		"github.com/objenious/errors/errtest.TestNormalizeError\n" +
+		"\tgithub.com/objenious/errors/errtest/normalize_test.go\n"
+	if len(got) < len(want) || got[:len(want)] != want {
+		t.Errorf("Normalize(%%+v):\n got: %q\nwant prefix: %q", got, want)
+	}
+}
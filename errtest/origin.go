@@ -0,0 +1,36 @@
+package errtest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/objenious/errors"
+)
+
+// closureR matches the suffixes the compiler gives to closures.
+var closureR = regexp.MustCompile(`(\.func\d+)+$`)
+
+// AssertOrigin reports a test error unless the deepest stack trace of err
+// originates in the function fn of the package pkg. pkg is matched against
+// the end of the import path ("pkg/store" matches
+// "github.com/user/app/pkg/store"), and fn may be a function ("Insert") or
+// a method ("Insert" or "(*DB).Insert"). Closures are attributed to their
+// enclosing function.
+func AssertOrigin(t testing.TB, err error, pkg, fn string) bool {
+	t.Helper()
+	st := errors.GetStackTrace(err)
+	if st == nil || len(st.StackTrace()) == 0 {
+		t.Errorf("%q has no stack trace, want origin %s.%s", message(err), pkg, fn)
+		return false
+	}
+	name := strings.SplitN(fmt.Sprintf("%+s", st.StackTrace()[0]), "\n", 2)[0]
+	p := pkgpath(name)
+	f := closureR.ReplaceAllString(strings.TrimPrefix(name, p+"."), "")
+	if (p != pkg && !strings.HasSuffix(p, "/"+pkg)) || (f != fn && !strings.HasSuffix(f, "."+fn)) {
+		t.Errorf("%q originates in %s, want %s.%s", message(err), name, pkg, fn)
+		return false
+	}
+	return true
+}
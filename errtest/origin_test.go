@@ -0,0 +1,43 @@
+package errtest
+
+import (
+	"io"
+	"testing"
+
+	"github.com/objenious/errors"
+)
+
+type store struct{}
+
+func (*store) Insert() error {
+	return errors.New("insert failed")
+}
+
+func TestAssertOrigin(t *testing.T) {
+	var s store
+	closure := func() error { return errors.WithStack(io.EOF) }
+
+	r := &recorder{TB: t}
+	tests := []struct {
+		err     error
+		pkg, fn string
+		ok      bool
+	}{
+		{errors.Wrap(s.Insert(), "wrapped"), "errors/errtest", "Insert", true},
+		{s.Insert(), "errtest", "(*store).Insert", true},
+		{s.Insert(), "github.com/objenious/errors/errtest", "Insert", true},
+		{closure(), "errtest", "TestAssertOrigin", true},
+		{s.Insert(), "store", "Insert", false},
+		{s.Insert(), "errtest", "sert", false},
+		{s.Insert(), "test", "Insert", false},
+		{io.EOF, "errtest", "Insert", false},
+	}
+	for i, tt := range tests {
+		if got := AssertOrigin(r, tt.err, tt.pkg, tt.fn); got != tt.ok {
+			t.Errorf("test %d: AssertOrigin(%v, %q, %q) = %t, want %t", i+1, tt.err, tt.pkg, tt.fn, got, tt.ok)
+		}
+	}
+	if len(r.failures) != 4 {
+		t.Errorf("got %d failures, want 4: %q", len(r.failures), r.failures)
+	}
+}
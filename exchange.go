@@ -0,0 +1,214 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Exchange is a snapshot of an HTTP request and of its response, as recorded
+// by WithHTTPExchange.
+type Exchange struct {
+	Method         string
+	URL            string
+	RequestHeader  http.Header
+	RequestBody    string
+	Status         int
+	ResponseHeader http.Header
+	ResponseBody   string
+}
+
+// defaultExchangeHeaders are the headers recorded by WithHTTPExchange until
+// SetExchangeHeaders is called.
+var defaultExchangeHeaders = []string{
+	"Content-Type",
+	"Content-Length",
+	"Content-Encoding",
+	"Location",
+	"Retry-After",
+	"X-Request-Id",
+}
+
+// sensitiveHeaders are the headers whose values WithHTTPExchange always
+// redacts.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// exchangeHeaders holds the headers set by SetExchangeHeaders.
+var exchangeHeaders atomic.Value
+
+// SetExchangeHeaders sets the headers of requests and responses recorded by
+// WithHTTPExchange. The values of the Authorization, Proxy-Authorization,
+// Cookie and Set-Cookie headers are always replaced by "REDACTED". The
+// default headers are Content-Type, Content-Length, Content-Encoding,
+// Location, Retry-After and X-Request-Id.
+func SetExchangeHeaders(names ...string) {
+	canonical := make([]string, len(names))
+	for i, name := range names {
+		canonical[i] = http.CanonicalHeaderKey(name)
+	}
+	exchangeHeaders.Store(canonical)
+}
+
+// WithHTTPExchange annotates err, typically returned by an HTTP client, with
+// a snapshot of req and resp, as returned by HTTPExchange: the method, the
+// URL with its query redacted, the headers selected by SetExchangeHeaders,
+// the status, and the first bodyLimit bytes of the bodies, followed by "..."
+// if they were truncated. The snapshot is printed by %+v in its own section
+// and included by ToMap as "http_exchange".
+//
+// The body of the request is only recorded if req.GetBody is set, as it is
+// for the requests created by http.NewRequest with an in-memory body. The
+// body of resp is read, then restored for the caller to read from the start
+// and close. Either req or resp can be nil; if req is nil, resp.Request is
+// used. If bodyLimit is 0 or less, the bodies are not recorded.
+// If err is nil, WithHTTPExchange returns nil.
+func WithHTTPExchange(err error, req *http.Request, resp *http.Response, bodyLimit int) error {
+	if err == nil {
+		return nil
+	}
+	if req == nil && resp != nil {
+		req = resp.Request
+	}
+	var x Exchange
+	if req != nil {
+		x.Method = req.Method
+		if req.URL != nil {
+			x.URL = redactURL(req.URL)
+		}
+		x.RequestHeader = selectHeaders(req.Header)
+		if bodyLimit > 0 && req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				x.RequestBody, _ = readSnippet(body, bodyLimit)
+				_ = body.Close()
+			}
+		}
+	}
+	if resp != nil {
+		x.Status = resp.StatusCode
+		x.ResponseHeader = selectHeaders(resp.Header)
+		if bodyLimit > 0 && resp.Body != nil {
+			var read []byte
+			x.ResponseBody, read = readSnippet(resp.Body, bodyLimit)
+			resp.Body = restoredBody{io.MultiReader(bytes.NewReader(read), resp.Body), resp.Body}
+		}
+	}
+	return &withAttr{error: err, name: "http_exchange", value: x}
+}
+
+// HTTPExchange returns the snapshot of the outermost error of the chain of
+// err annotated with WithHTTPExchange.
+func HTTPExchange(err error) (Exchange, bool) {
+	v, ok := lookupAttr(err, "http_exchange")
+	x, _ := v.(Exchange)
+	return x, ok
+}
+
+// restoredBody is the body of a response read by WithHTTPExchange.
+type restoredBody struct {
+	io.Reader
+	io.Closer
+}
+
+// selectHeaders returns the headers of h selected by SetExchangeHeaders,
+// redacted, or nil if there are none.
+func selectHeaders(h http.Header) http.Header {
+	names, _ := exchangeHeaders.Load().([]string)
+	if names == nil {
+		names = defaultExchangeHeaders
+	}
+	var selected http.Header
+	for _, name := range names {
+		values, ok := h[name]
+		if !ok {
+			continue
+		}
+		if selected == nil {
+			selected = make(http.Header)
+		}
+		if sensitiveHeaders[name] {
+			selected[name] = []string{"REDACTED"}
+		} else {
+			selected[name] = append([]string(nil), values...)
+		}
+	}
+	return selected
+}
+
+// mapValue returns the representation of x in ToMap.
+func (x Exchange) mapValue() map[string]interface{} {
+	m := map[string]interface{}{}
+	if x.Method != "" {
+		m["method"] = x.Method
+	}
+	if x.URL != "" {
+		m["url"] = x.URL
+	}
+	if x.RequestHeader != nil {
+		m["request_headers"] = headerMap(x.RequestHeader)
+	}
+	if x.RequestBody != "" {
+		m["request_body"] = x.RequestBody
+	}
+	if x.Status != 0 {
+		m["status"] = x.Status
+	}
+	if x.ResponseHeader != nil {
+		m["response_headers"] = headerMap(x.ResponseHeader)
+	}
+	if x.ResponseBody != "" {
+		m["response_body"] = x.ResponseBody
+	}
+	return m
+}
+
+// headerMap returns h with the values of each header joined by ", ".
+func headerMap(h http.Header) map[string]interface{} {
+	m := make(map[string]interface{}, len(h))
+	for name, values := range h {
+		m[name] = strings.Join(values, ", ")
+	}
+	return m
+}
+
+// format prints x in the section of an error printed with %+v:
+//
+//	http request: POST https://api.example.com/v1/devices
+//	    Content-Type: application/json
+//	    {"eui":"0004A30B001C0530"}
+//	http response: 502 Bad Gateway
+//	    Content-Type: text/plain
+//	    upstream unavailable
+func (x Exchange) format(w io.Writer) {
+	if x.Method != "" || x.URL != "" {
+		_, _ = fmt.Fprintf(w, "\nhttp request: %s", strings.TrimSpace(x.Method+" "+x.URL))
+		formatMessage(w, x.RequestHeader, x.RequestBody)
+	}
+	if x.Status != 0 {
+		_, _ = fmt.Fprintf(w, "\nhttp response: %d %s", x.Status, http.StatusText(x.Status))
+		formatMessage(w, x.ResponseHeader, x.ResponseBody)
+	}
+}
+
+// formatMessage prints the headers and the body of an HTTP message, indented.
+func formatMessage(w io.Writer, h http.Header, body string) {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "\n    %s: %s", name, strings.Join(h[name], ", "))
+	}
+	if body != "" {
+		_, _ = io.WriteString(w, "\n    "+strings.Replace(body, "\n", "\n    ", -1))
+	}
+}
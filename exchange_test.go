@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithHTTPExchange(t *testing.T) {
+	if err := WithHTTPExchange(nil, nil, nil, 10); err != nil {
+		t.Errorf("WithHTTPExchange(nil): got %v, want nil", err)
+	}
+
+	req, _ := http.NewRequest("POST", "https://api/devices?token=abc", strings.NewReader(`{"eui":"0004A30B001C0530"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer secret")
+	resp := response(http.StatusBadGateway, "POST", "https://api/devices", "upstream\nunavailable")
+	resp.Header.Set("Retry-After", "30")
+	resp.Header.Set("Server", "nginx")
+
+	err := WithHTTPExchange(io.EOF, req, resp, 12)
+	if body, _ := ioutil.ReadAll(resp.Body); string(body) != "upstream\nunavailable" {
+		t.Errorf("response body: got %q, want it restored", body)
+	}
+
+	x, ok := HTTPExchange(err)
+	want := Exchange{
+		Method:         "POST",
+		URL:            "https://api/devices?token=REDACTED",
+		RequestHeader:  http.Header{"Content-Type": {"application/json"}},
+		RequestBody:    `{"eui":"0004...`,
+		Status:         http.StatusBadGateway,
+		ResponseHeader: http.Header{"Retry-After": {"30"}},
+		ResponseBody:   "upstream\nuna...",
+	}
+	if !ok || !reflect.DeepEqual(x, want) {
+		t.Errorf("HTTPExchange(): got %#v, %t, want %#v", x, ok, want)
+	}
+
+	got := fmt.Sprintf("%+v", err)
+	wantText := "EOF" +
+		"\nhttp request: POST https://api/devices?token=REDACTED" +
+		"\n    Content-Type: application/json" +
+		"\n    {\"eui\":\"0004..." +
+		"\nhttp response: 502 Bad Gateway" +
+		"\n    Retry-After: 30" +
+		"\n    upstream" +
+		"\n    una..."
+	if got != wantText {
+		t.Errorf("%%+v: got %q, want %q", got, wantText)
+	}
+
+	m := ToMap(err)["http_exchange"].(map[string]interface{})
+	if m["status"] != http.StatusBadGateway || m["method"] != "POST" {
+		t.Errorf("ToMap(): got %v", m)
+	}
+	if h := m["request_headers"].(map[string]interface{}); h["Content-Type"] != "application/json" {
+		t.Errorf("ToMap() request headers: got %v", h)
+	}
+}
+
+func TestSetExchangeHeaders(t *testing.T) {
+	defer SetExchangeHeaders(defaultExchangeHeaders...)
+	SetExchangeHeaders("authorization", "x-trace-id")
+
+	req, _ := http.NewRequest("GET", "https://api/devices", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Trace-Id", "abc")
+	req.Header.Set("Content-Type", "application/json")
+	x, _ := HTTPExchange(WithHTTPExchange(io.EOF, req, nil, 0))
+	want := http.Header{"Authorization": {"REDACTED"}, "X-Trace-Id": {"abc"}}
+	if !reflect.DeepEqual(x.RequestHeader, want) {
+		t.Errorf("RequestHeader: got %v, want %v", x.RequestHeader, want)
+	}
+	if x.Status != 0 || x.ResponseHeader != nil {
+		t.Errorf("HTTPExchange() without response: got %#v", x)
+	}
+}
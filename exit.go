@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// kindExitCodes maps kinds to the exit codes of sysexits.h.
+var kindExitCodes = map[Kind]int{
+	KindInvalid:          65, // EX_DATAERR
+	KindNotFound:         66, // EX_NOINPUT
+	KindUnauthenticated:  77, // EX_NOPERM
+	KindPermissionDenied: 77, // EX_NOPERM
+	KindUnavailable:      69, // EX_UNAVAILABLE
+	KindRateLimited:      75, // EX_TEMPFAIL
+	KindTimeout:          75, // EX_TEMPFAIL
+	KindInternal:         70, // EX_SOFTWARE
+	KindCanceled:         130,
+}
+
+// WithExitCode annotates err with the status a command failing with err
+// should exit with, as returned by ExitCode.
+// If err is nil, WithExitCode returns nil.
+func WithExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &withAttr{error: err, name: "exit_code", value: code}
+}
+
+// ExitCode returns the status a command failing with err should exit with:
+// the code set by WithExitCode in the chain of err if any, or else a code of
+// sysexits.h depending on the kind of err, or else 1. ExitCode returns 0 if
+// err is nil.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if code, ok := lookupAttr(err, "exit_code"); ok {
+		return code.(int)
+	}
+	if code, ok := kindExitCodes[KindOf(err)]; ok {
+		return code
+	}
+	return 1
+}
+
+// VerboseEnv is the environment variable that makes HandleMain print stack
+// traces, when set to a non-empty value.
+const VerboseEnv = "ERRORS_VERBOSE"
+
+// stderr and exit are replaced in tests.
+var (
+	stderr io.Writer = os.Stderr
+	exit             = os.Exit
+)
+
+// HandleMain prints err to the standard error and exits with ExitCode(err),
+// unless err is nil. It is meant to end the main function of commands:
+//
+//	func main() {
+//		errors.HandleMain(run())
+//	}
+//
+// err is printed with %v, or with %+v if the VerboseEnv environment variable
+// is set.
+func HandleMain(err error) {
+	if err == nil {
+		return
+	}
+	format := "%v\n"
+	if os.Getenv(VerboseEnv) != "" {
+		format = "%+v\n"
+	}
+	_, _ = fmt.Fprintf(stderr, format, err)
+	exit(ExitCode(err))
+}
@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{io.EOF, 1},
+		{WithKind(io.EOF, KindNotFound), 66},
+		{Wrap(WithKind(io.EOF, KindPermissionDenied), "open"), 77},
+		{WithExitCode(WithKind(io.EOF, KindNotFound), 3), 3},
+		{Wrap(WithExitCode(io.EOF, 0), "read"), 0},
+	}
+	for i, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("test %d: ExitCode(%v): got %d, want %d", i+1, tt.err, got, tt.want)
+		}
+	}
+	if got := WithExitCode(nil, 2); got != nil {
+		t.Errorf("WithExitCode(nil): got %#v, want nil", got)
+	}
+}
+
+func TestHandleMain(t *testing.T) {
+	defer func() { stderr, exit = os.Stderr, os.Exit }()
+	var buf bytes.Buffer
+	code := -1
+	stderr, exit = &buf, func(c int) { code = c }
+
+	HandleMain(nil)
+	if code != -1 || buf.Len() != 0 {
+		t.Errorf("HandleMain(nil): got exit %d and %q, want nothing", code, buf.String())
+	}
+
+	err := WithExitCode(New("boom"), 2)
+	HandleMain(err)
+	if code != 2 || buf.String() != "boom\n" {
+		t.Errorf("HandleMain: got exit %d and %q, want 2 and \"boom\\n\"", code, buf.String())
+	}
+
+	buf.Reset()
+	defer os.Unsetenv(VerboseEnv)
+	os.Setenv(VerboseEnv, "1")
+	HandleMain(err)
+	if !strings.HasPrefix(buf.String(), "boom\ngithub.com/objenious/errors.TestHandleMain\n") {
+		t.Errorf("HandleMain verbose: got %q, want a stack trace", buf.String())
+	}
+}
@@ -0,0 +1,226 @@
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"sort"
+)
+
+// Factory creates errors with its own settings, rather than the settings of
+// the process, so that a library can choose how its errors record and print
+// stack traces without changing them for the whole program:
+//
+//	var errs = errors.NewFactory(errors.StackDepth(8), errors.TrimPaths())
+//
+//	func (c *Client) Get(id string) error {
+//		...
+//		return errs.Wrap(err, "get")
+//	}
+//
+// The zero Factory, like a Factory without options, uses the settings of the
+// process.
+type Factory struct {
+	depth     int
+	skip      int
+	trimPaths bool
+	redactor  redactor
+	fields    []field
+}
+
+// Option is a setting of a Factory.
+type Option func(*Factory)
+
+// StackDepth limits the stack traces recorded by a Factory to their n
+// innermost frames.
+func StackDepth(n int) Option {
+	return func(f *Factory) { f.depth = n }
+}
+
+// CallerSkip skips the n innermost frames of the stack traces recorded by a
+// Factory, for helpers creating errors on behalf of their callers: with
+// CallerSkip(1), the stack traces start at the caller of the function calling
+// the methods of the Factory.
+func CallerSkip(n int) Option {
+	return func(f *Factory) { f.skip = n }
+}
+
+// TrimPaths trims the paths of the source files of the errors of a Factory
+// to the path of their package, as in Production mode (see SetMode).
+func TrimPaths() Option {
+	return func(f *Factory) { f.trimPaths = true }
+}
+
+// RedactFrames replaces the frame redactor of the process, see
+// SetFrameRedactor, by r for the errors of a Factory.
+func RedactFrames(r func(Frame) (Frame, bool)) Option {
+	return func(f *Factory) { f.redactor = redactor{r} }
+}
+
+// DefaultFields annotates the errors of a Factory with fields, see
+// WithField.
+func DefaultFields(fields map[string]interface{}) Option {
+	return func(f *Factory) {
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			f.fields = append(f.fields, field{key: key, value: fields[key]})
+		}
+	}
+}
+
+// NewFactory returns a Factory with the given options.
+func NewFactory(opts ...Option) *Factory {
+	f := &Factory{}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// stack returns the stack trace of the caller of the method of f calling
+// stack, skipping the frames and limited to the depth of f.
+func (f *Factory) stack() *stack {
+	st := capture(4 + f.skip)
+	if f.depth > 0 && len(*st) > f.depth {
+		*st = (*st)[:f.depth]
+	}
+	return st
+}
+
+// annotate annotates w with the default fields of f.
+func (f *Factory) annotate(w *withStack) error {
+	var err error = w
+	for _, fd := range f.fields {
+		err = &withAttr{error: err, name: "fields", value: fd}
+	}
+	return err
+}
+
+// New is like New, with the settings of f.
+func (f *Factory) New(message string) error {
+	return f.annotate(&withStack{
+		error:   goerrors.New(message),
+		stack:   f.stack(),
+		created: timestamp(),
+		factory: f,
+	})
+}
+
+// Errorf is like Errorf, with the settings of f.
+func (f *Factory) Errorf(format string, args ...interface{}) error {
+	return f.annotate(&withStack{
+		error:   fmt.Errorf(format, args...),
+		stack:   f.stack(),
+		created: timestamp(),
+		factory: f,
+	})
+}
+
+// WithStack is like WithStack, with the settings of f.
+func (f *Factory) WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return stackError(err, f.stack(), f)
+}
+
+// Wrap is like Wrap, with the settings of f.
+func (f *Factory) Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return wrapError(err, f.stack(), message, f)
+}
+
+// Wrapf is like Wrapf, with the settings of f.
+func (f *Factory) Wrapf(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return wrapError(err, f.stack(), fmt.Sprintf(format, args...), f)
+}
+
+// frames returns the frames of w to print or serialize, redacted according
+// to its factory or the process.
+func (w *withStack) frames() StackTrace {
+	if w.factory != nil && w.factory.redactor.f != nil {
+		return w.StackTrace().redactedWith(w.factory.redactor)
+	}
+	return w.StackTrace().redacted()
+}
+
+// stackText returns the frames of w formatted by frameText in the mode of w,
+// or nil if it has none.
+func (w *withStack) stackText(frameText func(Frame, modeSettings) string) []string {
+	st := w.frames()
+	if len(st) == 0 {
+		return nil
+	}
+	frames := make([]string, len(st))
+	mode := w.mode()
+	for i, f := range st {
+		frames[i] = frameText(f, mode)
+	}
+	return frames
+}
+
+// StackText returns the stack trace recorded by err, not by its causes, as
+// ToMap serializes it: "function file:line" frames, redacted by the frame
+// redactor set by SetFrameRedactor or by the Factory creating err, in the
+// current Mode. The stack of a RemoteError is returned as received. It
+// returns nil if err carries no stack trace.
+func StackText(err error) []string {
+	switch e := err.(type) {
+	case *withStack:
+		return e.stackText(Frame.text)
+	case *RemoteError:
+		return e.Stack
+	case StackTracer:
+		st := e.StackTrace().redacted()
+		if len(st) == 0 {
+			return nil
+		}
+		frames := make([]string, len(st))
+		mode := currentMode()
+		for i, f := range st {
+			frames[i] = f.text(mode)
+		}
+		return frames
+	}
+	return nil
+}
+
+// DeepestStack returns the frames of the deepest stack trace of the chain of
+// err, following the first cause of each level, as StackText returns them:
+// the stack trace of the origin of the error, the most relevant in reports
+// showing a single one. It returns nil if the chain carries no stack trace.
+func DeepestStack(err error) []string {
+	var frames []string
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		switch err.(type) {
+		case *withStack, *RemoteError:
+			if st := StackText(err); st != nil {
+				frames = st
+			}
+		}
+		causes := causes(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return frames
+}
+
+// mode returns the mode in which the frames of w are printed or serialized.
+func (w *withStack) mode() modeSettings {
+	m := currentMode()
+	if w.factory != nil && w.factory.trimPaths {
+		m.trimPaths = true
+	}
+	return m
+}
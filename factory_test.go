@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFactory(t *testing.T) {
+	f := NewFactory(StackDepth(1), TrimPaths())
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{f.New("boom"), "boom"},
+		{f.Errorf("read: %w", io.EOF), "read: EOF"},
+		{f.WithStack(io.EOF), "EOF"},
+		{f.Wrap(io.EOF, "read"), "read: EOF"},
+		{f.Wrapf(io.EOF, "read %d", 1), "read 1: EOF"},
+	}
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: got %q, want %q", i+1, got, tt.want)
+		}
+		want := "\ngithub.com/objenious/errors.TestFactory\n\tgithub.com/objenious/errors/factory_test.go:"
+		if got := fmt.Sprintf("%+v", tt.err); !strings.Contains(got, want) || strings.Count(got, "\n\t") != 1 {
+			t.Errorf("test %d: got %q, want one frame with a trimmed path", i+1, got)
+		}
+		frames := ToMap(tt.err)["stack"].([]string)
+		if len(frames) != 1 || !strings.HasPrefix(frames[0], "github.com/objenious/errors.TestFactory github.com/objenious/errors/factory_test.go:") {
+			t.Errorf("test %d: got ToMap frames %q", i+1, frames)
+		}
+	}
+	if got := f.Wrap(nil, "read"); got != nil {
+		t.Errorf("Wrap(nil): got %#v, want nil", got)
+	}
+	if got, want := fmt.Sprintf("%+v", New("process")), "\n\t/"; !strings.Contains(got, want) {
+		t.Errorf("New: got %q, want the settings of the process", got)
+	}
+
+	redacting := NewFactory(RedactFrames(func(Frame) (Frame, bool) { return 0, false }))
+	if got := fmt.Sprintf("%+v", redacting.New("boom")); got != "boom" {
+		t.Errorf("RedactFrames: got %q, want no frame", got)
+	}
+	if got := StackText(redacting.New("boom")); got != nil {
+		t.Errorf("RedactFrames: got StackText %q, want none", got)
+	}
+	remote := &RemoteError{Message: "boom", Stack: []string{"main.main /src/main.go:12"}}
+	if got := DeepestStack(Wrap(WithField(remote, "id", 1), "call")); !reflect.DeepEqual(got, remote.Stack) {
+		t.Errorf("DeepestStack() = %q, want the stack of the remote error", got)
+	}
+	if got := DeepestStack(io.EOF); got != nil {
+		t.Errorf("DeepestStack(io.EOF) = %q, want nil", got)
+	}
+	if got := StackText(New("boom")); len(got) == 0 || !strings.HasPrefix(got[0], "github.com/objenious/errors.TestFactory ") {
+		t.Errorf("StackText() = %q", got)
+	}
+	var zero Factory
+	if got := GetStackTrace(zero.New("boom")); got == nil || len(*got) < 2 {
+		t.Errorf("zero Factory: got stack %v, want a full stack", got)
+	}
+}
+
+// newHelper creates an error on behalf of its caller.
+func newHelper(f *Factory) error {
+	return f.New("boom")
+}
+
+func TestFactoryCallerSkip(t *testing.T) {
+	err := newHelper(NewFactory(CallerSkip(1)))
+	if got := fmt.Sprintf("%n", err.(*withStack).StackTrace()[0]); got != "TestFactoryCallerSkip" {
+		t.Errorf("first frame = %s, want TestFactoryCallerSkip", got)
+	}
+	err = newHelper(NewFactory())
+	if got := fmt.Sprintf("%n", err.(*withStack).StackTrace()[0]); got != "newHelper" {
+		t.Errorf("first frame = %s, want newHelper", got)
+	}
+}
@@ -0,0 +1,122 @@
+package errors
+
+import "sync/atomic"
+
+// field is the value of the attributes set by WithField.
+type field struct {
+	key   string
+	value interface{}
+}
+
+// WithField annotates err with the field key, set to value, as returned by
+// Field, Fields and AllFieldValues and included by ToMap in a "fields" map.
+// If err is nil, WithField returns nil.
+func WithField(err error, key string, value interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &withAttr{error: err, name: "fields", value: field{key: key, value: value}}
+}
+
+// WithFields annotates err with several fields, like WithField.
+// If err is nil, WithFields returns nil.
+func WithFields(err error, fields map[string]interface{}) error {
+	for key, value := range fields {
+		err = WithField(err, key, value)
+	}
+	return err
+}
+
+// FieldMergePolicy decides the value of a field set several times in the
+// chain of an error.
+type FieldMergePolicy int32
+
+// Field merge policies.
+const (
+	// OuterWins keeps the value set by the outermost error of the chain.
+	OuterWins FieldMergePolicy = iota
+	// InnerWins keeps the value set by the innermost error of the chain.
+	InnerWins
+	// CollectAll keeps all the values, from the outermost to the innermost,
+	// in a []interface{}, even for the fields set once.
+	CollectAll
+)
+
+// fieldMergePolicy is the current FieldMergePolicy.
+var fieldMergePolicy int32
+
+// SetFieldMergePolicy sets the policy deciding the value of fields set
+// several times in the chain of an error, in Field and Fields. ToMap lists
+// the fields with the level they annotate, the policy only applying to the
+// fields set several times on a level: a field set on both sides of a Wrap
+// is in the "fields" of the two levels. The default is OuterWins.
+// AllFieldValues returns all the values, whatever the policy.
+func SetFieldMergePolicy(p FieldMergePolicy) {
+	atomic.StoreInt32(&fieldMergePolicy, int32(p))
+}
+
+// mergeField returns the value of a field set to values, from the outermost
+// to the innermost, according to the merge policy.
+func mergeField(values []interface{}) interface{} {
+	switch FieldMergePolicy(atomic.LoadInt32(&fieldMergePolicy)) {
+	case InnerWins:
+		return values[len(values)-1]
+	case CollectAll:
+		return append([]interface{}(nil), values...)
+	}
+	return values[0]
+}
+
+// fieldValues returns the values of the fields set in the chain of err, from
+// the outermost to the innermost.
+func fieldValues(err error) map[string][]interface{} {
+	var values map[string][]interface{}
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		if w, ok := err.(*withAttr); ok {
+			if f, ok := w.value.(field); ok {
+				if values == nil {
+					values = make(map[string][]interface{})
+				}
+				values[f.key] = append(values[f.key], f.value)
+			}
+		}
+		causes := causes(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return values
+}
+
+// Field returns the value of the field key in the chain of err, according to
+// the merge policy, see SetFieldMergePolicy.
+func Field(err error, key string) (interface{}, bool) {
+	values := AllFieldValues(err, key)
+	if len(values) == 0 {
+		return nil, false
+	}
+	return mergeField(values), true
+}
+
+// Fields returns the fields set in the chain of err, according to the merge
+// policy, see SetFieldMergePolicy. It returns nil if no field is set.
+func Fields(err error) map[string]interface{} {
+	values := fieldValues(err)
+	if values == nil {
+		return nil
+	}
+	fields := make(map[string]interface{}, len(values))
+	for key, v := range values {
+		fields[key] = mergeField(v)
+	}
+	return fields
+}
+
+// AllFieldValues returns every value the field key was set to in the chain
+// of err, from the outermost to the innermost, whatever the merge policy.
+func AllFieldValues(err error, key string) []interface{} {
+	values := fieldValues(err)
+	return values[key]
+}
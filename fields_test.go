@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	defer SetFieldMergePolicy(OuterWins)
+	inner := WithFields(io.EOF, map[string]interface{}{"device": "inner", "port": 8080})
+	err := WithField(Wrap(WithField(inner, "device", "middle"), "read"), "device", "outer")
+
+	if got, want := AllFieldValues(err, "device"), []interface{}{"outer", "middle", "inner"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AllFieldValues: got %v, want %v", got, want)
+	}
+	if got := AllFieldValues(err, "unknown"); got != nil {
+		t.Errorf("AllFieldValues(unknown): got %v, want nil", got)
+	}
+	if got := WithField(nil, "device", "x"); got != nil {
+		t.Errorf("WithField(nil): got %#v, want nil", got)
+	}
+	if got := Fields(io.EOF); got != nil {
+		t.Errorf("Fields(io.EOF): got %v, want nil", got)
+	}
+
+	tests := []struct {
+		policy FieldMergePolicy
+		device interface{}
+		port   interface{} // value of the field set once
+		outer  interface{} // value of the first level in ToMap
+		level  interface{} // value of the second level in ToMap
+	}{
+		{OuterWins, "outer", 8080, "outer", "middle"},
+		{InnerWins, "inner", 8080, "outer", "inner"},
+		{CollectAll, []interface{}{"outer", "middle", "inner"}, []interface{}{8080}, []interface{}{"outer"}, []interface{}{"middle", "inner"}},
+	}
+	for _, tt := range tests {
+		SetFieldMergePolicy(tt.policy)
+		if got, ok := Field(err, "device"); !ok || !reflect.DeepEqual(got, tt.device) {
+			t.Errorf("policy %d: Field: got %v, want %v", tt.policy, got, tt.device)
+		}
+		if got, ok := Field(err, "port"); !ok || !reflect.DeepEqual(got, tt.port) {
+			t.Errorf("policy %d: Field(port): got %v, want %v", tt.policy, got, tt.port)
+		}
+		if got, want := Fields(err), map[string]interface{}{"device": tt.device, "port": tt.port}; !reflect.DeepEqual(got, want) {
+			t.Errorf("policy %d: Fields: got %v, want %v", tt.policy, got, want)
+		}
+		// the policy does not merge the fields of different levels.
+		m := ToMap(err)
+		if got := m["fields"]; !reflect.DeepEqual(got, map[string]interface{}{"device": tt.outer}) {
+			t.Errorf("policy %d: ToMap: got fields %v", tt.policy, got)
+		}
+		cause := m["cause"].(map[string]interface{})
+		if got, want := cause["fields"], map[string]interface{}{"device": tt.level, "port": tt.port}; !reflect.DeepEqual(got, want) {
+			t.Errorf("policy %d: ToMap: got cause fields %v, want %v", tt.policy, got, want)
+		}
+	}
+}
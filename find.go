@@ -0,0 +1,67 @@
+package errors
+
+// FindFirst returns the outermost error of the chain of err, following the
+// first cause of each error, for which pred returns true, or nil:
+//
+//	// the outermost layer setting the field "user"
+//	layer := errors.FindFirst(err, errors.HasField("user"))
+func FindFirst(err error, pred func(error) bool) error {
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		if pred(err) {
+			return err
+		}
+		causes := causes(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return nil
+}
+
+// FindLast returns the innermost error of the chain of err, following the
+// first cause of each error, for which pred returns true, or nil.
+func FindLast(err error, pred func(error) bool) error {
+	var found error
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		if pred(err) {
+			found = err
+		}
+		causes := causes(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return found
+}
+
+// HasKind returns a predicate for FindFirst and FindLast matching the errors
+// annotated with the kind k by WithKind, or decoded with it.
+func HasKind(k Kind) func(error) bool {
+	return func(err error) bool {
+		v, ok := levelAttr(err, "kind")
+		return ok && v == k
+	}
+}
+
+// HasCode returns a predicate for FindFirst and FindLast matching the errors
+// annotated with the code c by WithCode, or decoded with it.
+func HasCode(c Code) func(error) bool {
+	return func(err error) bool {
+		v, ok := levelAttr(err, "code")
+		return ok && v == c
+	}
+}
+
+// HasField returns a predicate for FindFirst and FindLast matching the
+// errors setting the field key with WithField.
+func HasField(key string) func(error) bool {
+	return func(err error) bool {
+		v, ok := levelAttr(err, "fields")
+		f, _ := v.(field)
+		return ok && f.key == key
+	}
+}
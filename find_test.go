@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	inner := WithField(io.EOF, "user", 1)
+	middle := WithKind(WithCode(Wrap(inner, "read"), "DEV-0001"), KindNotFound)
+	outer := WithField(Wrap(middle, "load"), "user", 2)
+	remote := &RemoteError{Message: "call: load: read: EOF", Kind: KindNotFound, Causes: []error{outer}}
+
+	tests := []struct {
+		name        string
+		err         error
+		pred        func(error) bool
+		first, last error
+	}{
+		{"field", outer, HasField("user"), outer, inner},
+		{"kind", remote, HasKind(KindNotFound), remote, middle},
+		{"code", remote, HasCode("DEV-0001"), middle.(*withAttr).error, middle.(*withAttr).error},
+		{"no match", outer, HasField("other"), nil, nil},
+		{"type", outer, func(err error) bool { _, ok := err.(*withStack); return ok }, outer.(*withAttr).error, middle.(*withAttr).error.(*withAttr).error},
+		{"nil", nil, HasField("user"), nil, nil},
+	}
+	for _, tt := range tests {
+		if got := FindFirst(tt.err, tt.pred); got != tt.first {
+			t.Errorf("%s: FindFirst() = %v, want %v", tt.name, got, tt.first)
+		}
+		if got := FindLast(tt.err, tt.pred); got != tt.last {
+			t.Errorf("%s: FindLast() = %v, want %v", tt.name, got, tt.last)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// extendedVerb returns the format printing errors as %+v does on s, with the
+// precision of s if any, so that it applies to the whole chain.
+func extendedVerb(s io.Writer) string {
+	if st, ok := s.(interface{ Precision() (int, bool) }); ok {
+		if p, ok := st.Precision(); ok {
+			return fmt.Sprintf("%%+.%dv", p)
+		}
+	}
+	return "%+v"
+}
+
+// limitFrames returns the first frames of st, as many as the precision of s
+// if any.
+func limitFrames(s fmt.State, st StackTrace) StackTrace {
+	if p, ok := s.Precision(); ok && p < len(st) {
+		return st[:p]
+	}
+	return st
+}
+
+// formatIndented prints err with %+v on s, every line but the first being
+// indented by the width of s, and reports whether s has a width.
+func formatIndented(s fmt.State, err error) bool {
+	width, ok := s.Width()
+	if !ok || width <= 0 || legacyWidth() {
+		return false
+	}
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, extendedVerb(s), err)
+	_, _ = io.WriteString(s, strings.Replace(b.String(), "\n", "\n"+strings.Repeat(" ", width), -1))
+	return true
+}
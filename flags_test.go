@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFormatPrecision(t *testing.T) {
+	err := WithKind(Wrap(Join(New("first"), io.EOF), "read"), KindInternal)
+	frames := func(s string) int {
+		return strings.Count(s, "\n\t")
+	}
+	all := frames(fmt.Sprintf("%+v", err))
+	if all <= 4 {
+		t.Fatalf("%%+v prints %d frames", all)
+	}
+	for _, p := range []int{0, 1, 2} {
+		got := fmt.Sprintf("%+.*v", p, err)
+		// the stack traces of New and Wrap
+		if n := frames(got); n != 2*p {
+			t.Errorf("%%+.%dv prints %d frames, want %d:\n%s", p, n, 2*p, got)
+		}
+		if !strings.HasPrefix(got, "first\n") || !strings.Contains(got, "\nEOF\nread") {
+			t.Errorf("%%+.%dv does not print the messages:\n%s", p, got)
+		}
+	}
+}
+
+func TestFormatWidth(t *testing.T) {
+	for _, err := range []error{
+		WithStack(io.EOF),
+		WithField(New("boom"), "key", "value"),
+		Join(New("first"), New("second")),
+	} {
+		plain := fmt.Sprintf("%+v", err)
+		want := strings.Replace(plain, "\n", "\n  ", -1)
+		if got := fmt.Sprintf("%+2v", err); got != want {
+			t.Errorf("%%+2v:\n got: %q\nwant: %q", got, want)
+		}
+		want = strings.Replace(fmt.Sprintf("%+.1v", err), "\n", "\n   ", -1)
+		if got := fmt.Sprintf("%+3.1v", err); got != want {
+			t.Errorf("%%+3.1v:\n got: %q\nwant: %q", got, want)
+		}
+	}
+	if got := fmt.Sprintf("%+2v", WithStack(io.EOF)); !strings.HasPrefix(got, "EOF\n  github.com/objenious/errors.TestFormatWidth\n  \t") {
+		t.Errorf("%%+2v = %q", got)
+	}
+}
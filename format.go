@@ -0,0 +1,124 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// chainLevel is a level of a chain printed by formatChain: a *withStack,
+// with the wrappers collapsed with it if any, or a *withAttr.
+type chainLevel struct {
+	err error
+	run []*withStack
+}
+
+// formatChain prints err, a *withStack or a *withAttr, with %+v on s, once
+// the settings printing it on its own are ruled out. The levels of the chain
+// that are one of these types are printed in a single walk on s, instead of
+// a call to fmt.Fprintf printing each cause with %+v: what each level prints
+// before its cause is printed going down the chain, up to the first cause of
+// another type, printed with formatCause or formatExtended as its level
+// prints it, then what each level prints after its cause, from the
+// innermost.
+func formatChain(s fmt.State, err error) {
+	var buf [16]chainLevel
+	levels := buf[:0]
+	indent := atomic.LoadInt32(&levelIndent) > 0
+	for depth := 0; err != nil; depth++ {
+		l, cause, indented := beginLevel(s, err, depth == 0)
+		levels = append(levels, l)
+		if cause == nil {
+			break
+		}
+		if indented && indent || depth+1 >= maxDepth || !chained(cause) {
+			if indented {
+				formatCause(s, cause)
+			} else {
+				formatExtended(s, cause)
+			}
+			break
+		}
+		err = cause
+	}
+	for i := len(levels) - 1; i >= 0; i-- {
+		endLevel(s, levels[i])
+	}
+}
+
+// chained reports whether err is a level formatChain prints in its walk.
+func chained(err error) bool {
+	switch err.(type) {
+	case *withStack, *withAttr:
+		return true
+	}
+	return false
+}
+
+// beginLevel prints what err prints before its cause with %+v, and returns
+// the level of err, its cause to print next if any, and whether the cause is
+// indented by the level indent. top reports whether err is the error
+// printed, whose width applies.
+func beginLevel(s fmt.State, err error, top bool) (chainLevel, error, bool) {
+	switch w := err.(type) {
+	case *withStack:
+		if run := w.collapsed(); run != nil {
+			return chainLevel{err: w, run: run}, run[len(run)-1].error, true
+		}
+		if w.message() == "" {
+			if width, ok := s.Width(); top && ok && width > 0 && legacyWidth() {
+				_, _ = io.WriteString(s, "\n")
+			}
+			return chainLevel{err: w}, w.error, w.wrapped
+		}
+		if cause := w.Cause(); cause != nil {
+			return chainLevel{err: w}, cause, true
+		}
+		// root format
+		_, _ = fmt.Fprintf(s, "%+v", w.error)
+		return chainLevel{err: w}, nil, false
+	case *withAttr:
+		if msg, inner := w.Error(), w.error.Error(); len(msg) > len(inner) {
+			_, _ = io.WriteString(s, msg[:len(msg)-len(inner)])
+		}
+		return chainLevel{err: w}, w.error, false
+	}
+	return chainLevel{}, nil, false
+}
+
+// endLevel prints what the error of l prints after its cause with %+v.
+func endLevel(s fmt.State, l chainLevel) {
+	switch w := l.err.(type) {
+	case *withStack:
+		if l.run != nil {
+			inner := l.run[len(l.run)-1]
+			msgs := make([]string, len(l.run))
+			for i, w := range l.run {
+				msgs[i] = w.message()
+			}
+			_, _ = io.WriteString(s, "\n")
+			_, _ = io.WriteString(s, strings.Join(msgs, messageSeparator()))
+			w = inner
+		} else if msg := w.message(); msg != "" {
+			if cause := w.Cause(); cause != nil {
+				if causeWithStack, ok := cause.(*withStack); ok && causeWithStack.message() != msg || cause.Error() != msg {
+					_, _ = io.WriteString(s, "\n")
+					_, _ = io.WriteString(s, msg)
+				}
+			}
+		}
+		m := w.mode()
+		for _, f := range limitFrames(s, w.frames()) {
+			_, _ = io.WriteString(s, "\n")
+			f.formatLong(s, m)
+		}
+	case *withAttr:
+		switch v := w.value.(type) {
+		case payload:
+			_, _ = fmt.Fprintf(s, "\npayload: %s", v)
+		case Exchange:
+			v.format(s)
+		}
+	}
+}
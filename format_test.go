@@ -1,12 +1,13 @@
 package errors
 
 import (
-	goerrors "errors"
 	"fmt"
 	"io"
 	"regexp"
 	"strings"
 	"testing"
+
+	"github.com/objenious/errors/internal/blocks"
 )
 
 func TestFormatNew(t *testing.T) {
@@ -27,7 +28,7 @@ func TestFormatNew(t *testing.T) {
 		"%+v",
 		"error\n" +
 			"github.com/objenious/errors.TestFormatNew\n" +
-			"\t.+/github.com/objenious/errors/format_test.go:26",
+			"\t.+/github.com/objenious/errors/format_test.go:27",
 	}, {
 		New("error"),
 		"%q",
@@ -57,7 +58,7 @@ func TestFormatErrorf(t *testing.T) {
 		"%+v",
 		"error\n" +
 			"github.com/objenious/errors.TestFormatErrorf\n" +
-			"\t.+/github.com/objenious/errors/format_test.go:56",
+			"\t.+/github.com/objenious/errors/format_test.go:57",
 	}}
 
 	for i, tt := range tests {
@@ -83,7 +84,7 @@ func TestFormatWrap(t *testing.T) {
 		"%+v",
 		"error\n" +
 			"github.com/objenious/errors.TestFormatWrap\n" +
-			"\t.+/github.com/objenious/errors/format_test.go:82",
+			"\t.+/github.com/objenious/errors/format_test.go:83",
 	}, {
 		Wrap(io.EOF, "error"),
 		"%s",
@@ -98,14 +99,14 @@ func TestFormatWrap(t *testing.T) {
 		"EOF\n" +
 			"error\n" +
 			"github.com/objenious/errors.TestFormatWrap\n" +
-			"\t.+/github.com/objenious/errors/format_test.go:96",
+			"\t.+/github.com/objenious/errors/format_test.go:97",
 	}, {
 		Wrap(Wrap(io.EOF, "error1"), "error2"),
 		"%+v",
 		"EOF\n" +
 			"error1\n" +
 			"github.com/objenious/errors.TestFormatWrap\n" +
-			"\t.+/github.com/objenious/errors/format_test.go:103\n",
+			"\t.+/github.com/objenious/errors/format_test.go:104\n",
 	}, {
 		Wrap(New("error with space"), "context"),
 		"%q",
@@ -136,7 +137,7 @@ func TestFormatWrapf(t *testing.T) {
 		"EOF\n" +
 			"error2\n" +
 			"github.com/objenious/errors.TestFormatWrapf\n" +
-			"\t.+/github.com/objenious/errors/format_test.go:134",
+			"\t.+/github.com/objenious/errors/format_test.go:135",
 	}, {
 		Wrapf(New("error"), "error%d", 2),
 		"%s",
@@ -150,7 +151,7 @@ func TestFormatWrapf(t *testing.T) {
 		"%+v",
 		"error\n" +
 			"github.com/objenious/errors.TestFormatWrapf\n" +
-			"\t.+/github.com/objenious/errors/format_test.go:149",
+			"\t.+/github.com/objenious/errors/format_test.go:150",
 	}}
 
 	for i, tt := range tests {
@@ -176,7 +177,7 @@ func TestFormatWithStack(t *testing.T) {
 		"%+v",
 		[]string{"EOF",
 			"github.com/objenious/errors.TestFormatWithStack\n" +
-				"\t.+/github.com/objenious/errors/format_test.go:175"},
+				"\t.+/github.com/objenious/errors/format_test.go:176"},
 	}, {
 		WithStack(New("error")),
 		"%s",
@@ -190,36 +191,36 @@ func TestFormatWithStack(t *testing.T) {
 		"%+v",
 		[]string{"error",
 			"github.com/objenious/errors.TestFormatWithStack\n" +
-				"\t.+/github.com/objenious/errors/format_test.go:189",
+				"\t.+/github.com/objenious/errors/format_test.go:190",
 			"github.com/objenious/errors.TestFormatWithStack\n" +
-				"\t.+/github.com/objenious/errors/format_test.go:189"},
+				"\t.+/github.com/objenious/errors/format_test.go:190"},
 	}, {
 		WithStack(WithStack(io.EOF)),
 		"%+v",
 		[]string{"EOF",
 			"github.com/objenious/errors.TestFormatWithStack\n" +
-				"\t.+/github.com/objenious/errors/format_test.go:197",
+				"\t.+/github.com/objenious/errors/format_test.go:198",
 			"github.com/objenious/errors.TestFormatWithStack\n" +
-				"\t.+/github.com/objenious/errors/format_test.go:197"},
+				"\t.+/github.com/objenious/errors/format_test.go:198"},
 	}, {
 		WithStack(WithStack(Wrapf(io.EOF, "message"))),
 		"%+v",
 		[]string{"EOF",
 			"message",
 			"github.com/objenious/errors.TestFormatWithStack\n" +
-				"\t.+/github.com/objenious/errors/format_test.go:205",
+				"\t.+/github.com/objenious/errors/format_test.go:206",
 			"github.com/objenious/errors.TestFormatWithStack\n" +
-				"\t.+/github.com/objenious/errors/format_test.go:205",
+				"\t.+/github.com/objenious/errors/format_test.go:206",
 			"github.com/objenious/errors.TestFormatWithStack\n" +
-				"\t.+/github.com/objenious/errors/format_test.go:205"},
+				"\t.+/github.com/objenious/errors/format_test.go:206"},
 	}, {
 		WithStack(Errorf("error%d", 1)),
 		"%+v",
 		[]string{"error1",
 			"github.com/objenious/errors.TestFormatWithStack\n" +
-				"\t.+/github.com/objenious/errors/format_test.go:216",
+				"\t.+/github.com/objenious/errors/format_test.go:217",
 			"github.com/objenious/errors.TestFormatWithStack\n" +
-				"\t.+/github.com/objenious/errors/format_test.go:216"},
+				"\t.+/github.com/objenious/errors/format_test.go:217"},
 	}}
 
 	for i, tt := range tests {
@@ -241,9 +242,9 @@ func TestFormatWrappedNew(t *testing.T) {
 		"%+v",
 		"error\n" +
 			"github.com/objenious/errors.wrappedNew\n" +
-			"\t.+/github.com/objenious/errors/format_test.go:231\n" +
+			"\t.+/github.com/objenious/errors/format_test.go:232\n" +
 			"github.com/objenious/errors.TestFormatWrappedNew\n" +
-			"\t.+/github.com/objenious/errors/format_test.go:240",
+			"\t.+/github.com/objenious/errors/format_test.go:241",
 	}}
 
 	for i, tt := range tests {
@@ -273,76 +274,54 @@ func testFormatRegexp(t *testing.T, n int, arg interface{}, format, want string)
 	}
 }
 
-var stackLineR = regexp.MustCompile(`\.`)
-
-// parseBlocks parses input into a slice, where:
-//  - incase entry contains a newline, its a stacktrace
-//  - incase entry contains no newline, its a solo line.
+// parseBlocks parses input with blocks.Parse, as cmd/errfmt does, into a slice,
+// where:
+//   - incase entry contains a newline, its a stacktrace
+//   - incase entry contains no newline, its a solo line.
 //
-// Detecting stack boundaries only works incase the WithStack-calls are
-// to be found on the same line, thats why it is optionally here.
+// The stack traces following each other, such as those of
+// WithStack(New("error")), are only told apart if detectStackboundaries is
+// set, by a frame they both have, as the calls on the same line of the tests.
 //
 // Example use:
 //
-// for _, e := range blocks {
-//   if strings.ContainsAny(e, "\n") {
-//     // Match as stack
-//   } else {
-//     // Match as line
-//   }
-// }
-//
+//	for _, e := range blocks {
+//		if strings.ContainsAny(e, "\n") {
+//			// Match as stack
+//		} else {
+//			// Match as line
+//		}
+//	}
 func parseBlocks(input string, detectStackboundaries bool) ([]string, error) {
-	var blocks []string
-
-	stack := ""
-	wasStack := false
-	lines := map[string]bool{} // already found lines
-
-	for _, l := range strings.Split(input, "\n") {
-		isStackLine := stackLineR.MatchString(l)
-
-		switch {
-		case !isStackLine && wasStack:
-			blocks = append(blocks, stack, l)
-			stack = ""
-			lines = map[string]bool{}
-		case isStackLine:
-			if wasStack {
-				// Detecting two stacks after another, possible cause lines match in
-				// our tests due to WithStack(WithStack(io.EOF)) on same line.
-				if detectStackboundaries {
-					if lines[l] {
-						if len(stack) == 0 {
-							return nil, goerrors.New("len of block must not be zero here")
-						}
-
-						blocks = append(blocks, stack)
-						stack = l
-						lines = map[string]bool{l: true}
-						continue
-					}
+	var parsed []string
+	text := func(l string) error {
+		parsed = append(parsed, l)
+		return nil
+	}
+	emit := func(b *blocks.Block) error {
+		for _, e := range b.Entries {
+			if e.Message != "" {
+				parsed = append(parsed, strings.Split(e.Message, "\n")...)
+			}
+			var stack []string
+			frames := map[blocks.Frame]bool{} // frames of the current stack
+			for _, f := range e.Stack {
+				if detectStackboundaries && frames[f] {
+					parsed = append(parsed, strings.Join(stack, "\n"))
+					stack = nil
+					frames = map[blocks.Frame]bool{}
 				}
-
-				stack = stack + "\n" + l
-			} else {
-				stack = l
+				stack = append(stack, f.Func, fmt.Sprintf("\t%s:%d", f.File, f.Line))
+				frames[f] = true
 			}
-			lines[l] = true
-		case !isStackLine && !wasStack:
-			blocks = append(blocks, l)
-		default:
-			return nil, goerrors.New("must not happen")
+			parsed = append(parsed, strings.Join(stack, "\n"))
 		}
-
-		wasStack = isStackLine
+		return nil
 	}
-
-	// Use up stack
-	if stack != "" {
-		blocks = append(blocks, stack)
+	if err := blocks.Parse(strings.NewReader(input), text, emit); err != nil {
+		return nil, err
 	}
-	return blocks, nil
+	return parsed, nil
 }
 
 func testFormatCompleteCompare(t *testing.T, n int, arg interface{}, format string, want []string, detectStackBoundaries bool) {
@@ -387,3 +366,22 @@ func prettyBlocks(blocks []string) string {
 
 	return "   " + strings.Join(out, "\n   ")
 }
+
+func TestFormatLongChain(t *testing.T) {
+	err := New("root")
+	var want []string
+	for i := 1; i <= 40; i++ {
+		msg := fmt.Sprintf("wrap %d", i)
+		err = WithField(Wrap(err, msg), "level", i)
+		want = append(want, msg)
+	}
+	var got []string
+	for _, line := range strings.Split(fmt.Sprintf("%+v", err), "\n") {
+		if strings.HasPrefix(line, "wrap ") {
+			got = append(got, line)
+		}
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got messages %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// FormatCompat selects how errors are printed by %+v.
+type FormatCompat int32
+
+// Format compatibility settings.
+const (
+	// FormatDefault prints errors with the format of this package.
+	FormatDefault FormatCompat = iota
+	// PkgErrors prints errors exactly as github.com/pkg/errors: the
+	// messages of wrappers are printed even when they repeat the message of
+	// their cause, no newline is added for a width, and the causes of
+	// errors that do not implement fmt.Formatter are not expanded.
+	PkgErrors
+	// LegacyWidth prints errors with the format of this package, but for
+	// the width of %+v: as in former versions, any width adds a newline
+	// before the errors created by WithStack, instead of indenting the
+	// output.
+	LegacyWidth
+)
+
+// formatCompat is the current FormatCompat.
+var formatCompat int32
+
+// SetFormatCompat sets how errors are printed by %+v, for log parsers written
+// against the output of another package. The default is FormatDefault.
+func SetFormatCompat(c FormatCompat) {
+	atomic.StoreInt32(&formatCompat, int32(c))
+}
+
+// pkgErrorsFormat reports whether errors are printed as by pkg/errors.
+func pkgErrorsFormat() bool {
+	return FormatCompat(atomic.LoadInt32(&formatCompat)) == PkgErrors
+}
+
+// legacyWidth reports whether widths add a newline before the errors created
+// by WithStack.
+func legacyWidth() bool {
+	return FormatCompat(atomic.LoadInt32(&formatCompat)) == LegacyWidth
+}
+
+// formatPkgErrors prints w with %+v as pkg/errors prints the error it would
+// have created in its place.
+func (w *withStack) formatPkgErrors(s fmt.State) {
+	switch msg := w.message(); {
+	case !w.wrapped:
+		// New and Errorf
+		_, _ = io.WriteString(s, w.error.Error())
+	case msg == "":
+		// WithStack
+		_, _ = fmt.Fprintf(s, "%+v", w.error)
+	default:
+		// Wrap and Wrapf, a message wrapped with a stack trace
+		_, _ = fmt.Fprintf(s, "%+v\n", w.error)
+		_, _ = io.WriteString(s, msg)
+	}
+	m := w.mode()
+	for _, f := range w.frames() {
+		_, _ = io.WriteString(s, "\n")
+		f.formatLong(s, m)
+	}
+}
@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestSetFormatCompat(t *testing.T) {
+	defer SetFormatCompat(FormatDefault)
+	SetFormatCompat(PkgErrors)
+
+	frame := "github.com/objenious/errors.TestSetFormatCompat\n\t.+/errors/formatcompat_test.go:\\d+\n(.+\n)*"
+	tests := []struct {
+		err    error
+		format string
+		want   string
+	}{{
+		New("error"),
+		"%+v",
+		"^error\n" + frame,
+	}, {
+		Wrap(New("error"), "error"),
+		"%+v",
+		"^error\n" + frame + "error\n" + frame,
+	}, {
+		WithStack(io.EOF),
+		"%+1v",
+		"^EOF\n" + frame,
+	}, {
+		Wrap(fmt.Errorf("read: %w", New("error")), "load"),
+		"%+v",
+		"^read: error\nload\n" + frame,
+	}, {
+		WithKind(Errorf("error %d", 1), KindInternal),
+		"%+v",
+		"^error 1\n" + frame,
+	}}
+	for i, tt := range tests {
+		got := fmt.Sprintf(tt.format, tt.err) + "\n"
+		if !regexp.MustCompile(tt.want + "$").MatchString(got) {
+			t.Errorf("test %d: %s:\n got: %s\nwant: %s", i+1, tt.format, got, tt.want)
+		}
+	}
+
+	SetFormatCompat(LegacyWidth)
+	if got := fmt.Sprintf("%+1v", WithStack(io.EOF)); got[0] != '\n' {
+		t.Errorf("LegacyWidth: %%+1v does not start with a newline: %q", got)
+	}
+}
@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestFramePackage(t *testing.T) {
+	f := New("boom").(*withStack).StackTrace()[0]
+	if got, want := f.Package(), "github.com/objenious/errors"; got != want {
+		t.Errorf("Package() = %q, want %q", got, want)
+	}
+	if got := Frame(0).Package(); got != "" {
+		t.Errorf("Frame(0).Package() = %q, want empty", got)
+	}
+
+	tests := []struct {
+		name, want string
+	}{
+		{"main.main", "main"},
+		{"github.com/objenious/errors.TestFramePackage", "github.com/objenious/errors"},
+		{"github.com/objenious/errors.(*withStack).Format", "github.com/objenious/errors"},
+		{"gopkg.in/yaml%2ev3.Marshal", "gopkg.in/yaml.v3"},
+		{"runtime", "runtime"},
+	}
+	for _, tt := range tests {
+		if got := pkgname(tt.name); got != tt.want {
+			t.Errorf("pkgname(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestShortFrame(t *testing.T) {
+	f := New("boom").(*withStack).StackTrace()[0]
+	if got, want := fmt.Sprint(ShortFrame(f)), "TestShortFrame"; got != want {
+		t.Errorf("ShortFrame = %q, want %q", got, want)
+	}
+	b, err := json.Marshal([]ShortFrame{ShortFrame(f)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `["TestShortFrame"]`; got != want {
+		t.Errorf("json.Marshal = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// Freeze returns a materialized copy of err, suitable for storing in caches
+// or broadcasting to subscribers: its message, its representation printed
+// with %+v and the one built by ToMap are rendered once, the stack traces
+// symbolized, when Freeze is called. Formatting the copy, or calling ToMap,
+// only reads these renderings, whatever the settings are later. The copy
+// still unwraps to err, for errors.Is, errors.As and the accessors of this
+// package.
+// If err is nil, Freeze returns nil.
+func Freeze(err error) error {
+	if err == nil {
+		return nil
+	}
+	if f, ok := err.(*frozenError); ok {
+		return f
+	}
+	CheckPolicy(err, "Freeze")
+	return &frozenError{
+		err:     err,
+		msg:     err.Error(),
+		verbose: fmt.Sprintf("%+v", err),
+		m:       toMap(acyclic(err), Frame.text),
+	}
+}
+
+// frozenError is an error rendered by Freeze.
+type frozenError struct {
+	err     error
+	msg     string
+	verbose string
+	m       map[string]interface{}
+}
+
+func (f *frozenError) Error() string { return f.msg }
+
+// Unwrap returns the frozen error
+func (f *frozenError) Unwrap() error { return f.err }
+
+// Format formats the renderings of the frozen error
+func (f *frozenError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, f.verbose)
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, f.msg)
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", f.msg)
+	}
+}
+
+// copyValue returns a deep copy of the value v of a representation built by
+// ToMap.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			m[k] = copyValue(value)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, value := range v {
+			list[i] = copyValue(value)
+		}
+		return list
+	case []string:
+		return append([]string(nil), v...)
+	}
+	return v
+}
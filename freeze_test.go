@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	if got := Freeze(nil); got != nil {
+		t.Errorf("Freeze(nil): got %#v, want nil", got)
+	}
+	err := WithField(Wraplazy(io.EOF, "read %s", "config"), "file", "config.json")
+	want := fmt.Sprintf("%+v", err)
+	wantMap := ToMap(err)
+
+	frozen := Freeze(err)
+	if Freeze(frozen) != frozen {
+		t.Errorf("Freeze(Freeze(err)): got a new copy")
+	}
+	defer SetMode(DefaultMode)
+	SetMode(Production)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := frozen.Error(); got != "read config: EOF" {
+				t.Errorf("Error(): got %q", got)
+			}
+			if got := fmt.Sprintf("%+v", frozen); got != want {
+				t.Errorf("%%+v: got %q, want %q", got, want)
+			}
+			if got := ToMap(frozen); !reflect.DeepEqual(got, wantMap) {
+				t.Errorf("ToMap: got %v, want %v", got, wantMap)
+			}
+		}()
+	}
+	wg.Wait()
+
+	ToMap(frozen)["message"] = "modified"
+	if got := ToMap(frozen)["message"]; got != "read config: EOF" {
+		t.Errorf("ToMap: got message %q after modifying a copy", got)
+	}
+	if !Is(frozen, io.EOF) || Cause(frozen) != io.EOF {
+		t.Errorf("Freeze: the copy does not unwrap to err")
+	}
+	if v, _ := Field(frozen, "file"); v != "config.json" {
+		t.Errorf("Field(): got %v", v)
+	}
+	if got := fmt.Sprintf("%s %q", frozen, frozen); got != `read config: EOF "read config: EOF"` {
+		t.Errorf("%%s %%q: got %s", got)
+	}
+}
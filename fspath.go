@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// pathRedactor holds the function set by SetPathRedactor.
+var pathRedactor atomic.Value
+
+type stringRedactor struct {
+	f func(string) string
+}
+
+// SetPathRedactor sets a function applied to the paths recorded by WrapPath,
+// to hide user names or customer identifiers they contain. It returns the
+// path to record instead. A nil function disables redaction.
+func SetPathRedactor(f func(path string) string) {
+	pathRedactor.Store(stringRedactor{f})
+}
+
+// redactPath returns path redacted by the function set by SetPathRedactor.
+func redactPath(path string) string {
+	if r, _ := pathRedactor.Load().(stringRedactor); r.f != nil {
+		return r.f(path)
+	}
+	return path
+}
+
+// WrapPath annotates err, returned by the file system operation op on path,
+// with the stack trace at the point WrapPath was called, the fields "op" and
+// "path", and the kind inferred from err (see InferKind), such as
+// KindNotFound or KindPermissionDenied:
+//
+//	f, err := os.Open(path)
+//	if err != nil {
+//		return errors.WrapPath(err, "open", path)
+//	}
+//
+// err is normalized as an *os.PathError, or kept as an *os.LinkError, whose
+// paths are redacted by the function set by SetPathRedactor, in its message
+// as in the fields. The op and path of an *os.PathError are used if op or
+// path are empty; the paths of an *os.LinkError are always used, its new
+// path being the field "new_path".
+// If err is nil, WrapPath returns nil.
+func WrapPath(err error, op, path string) error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*os.PathError); ok {
+		if op == "" {
+			op = e.Op
+		}
+		if path == "" {
+			path = e.Path
+		}
+		err = e.Err
+	}
+	fields := make(map[string]interface{}, 3)
+	if e, ok := err.(*os.LinkError); ok {
+		if op == "" {
+			op = e.Op
+		}
+		err = &os.LinkError{Op: op, Old: redactPath(e.Old), New: redactPath(e.New), Err: e.Err}
+		fields["path"], fields["new_path"] = redactPath(e.Old), redactPath(e.New)
+	} else {
+		err = &os.PathError{Op: op, Path: redactPath(path), Err: err}
+		fields["path"] = redactPath(path)
+	}
+	fields["op"] = op
+	err = WithFields(&withStack{
+		error:   err,
+		stack:   callers(),
+		created: timestamp(),
+		wrapped: true,
+	}, fields)
+	if kind := InferKind(err); kind != KindUnknown {
+		err = WithKind(err, kind)
+	}
+	return err
+}
+
+// IsNotExist reports whether err is caused by a file or directory that does
+// not exist, like os.IsNotExist, but through the whole chain of err.
+func IsNotExist(err error) bool {
+	return Is(err, os.ErrNotExist)
+}
+
+// IsPermission reports whether err is caused by a denied permission, like
+// os.IsPermission, but through the whole chain of err.
+func IsPermission(err error) bool {
+	return Is(err, os.ErrPermission)
+}
@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWrapPath(t *testing.T) {
+	if err := WrapPath(nil, "open", "config.json"); err != nil {
+		t.Errorf("WrapPath(nil) = %v, want nil", err)
+	}
+
+	path := filepath.Join(os.TempDir(), "errors-test-missing", "config.json")
+	_, openErr := os.Open(path)
+	err := WrapPath(openErr, "", "")
+	if got, want := err.Error(), openErr.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if KindOf(err) != KindNotFound || !IsNotExist(err) || IsPermission(err) {
+		t.Errorf("WrapPath() = %v, want a not found error", err)
+	}
+	if !IsNotExist(Wrap(err, "load config")) {
+		t.Error("IsNotExist() does not see through Wrap")
+	}
+	if v, _ := Field(err, "op"); v != "open" {
+		t.Errorf("Field(op) = %v, want open", v)
+	}
+	if v, _ := Field(err, "path"); v != path {
+		t.Errorf("Field(path) = %v, want %v", v, path)
+	}
+	var pathErr *os.PathError
+	if !As(err, &pathErr) || GetStackTrace(err) == nil {
+		t.Errorf("WrapPath() = %v, want an *os.PathError with a stack trace", err)
+	}
+
+	defer SetPathRedactor(nil)
+	SetPathRedactor(func(path string) string { return strings.Replace(path, "alice", "REDACTED", -1) })
+	err = WrapPath(os.ErrPermission, "read", "/home/alice/.ssh/id_rsa")
+	if got, want := err.Error(), "read /home/REDACTED/.ssh/id_rsa: permission denied"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if KindOf(err) != KindPermissionDenied || !IsPermission(err) {
+		t.Errorf("WrapPath() = %v, want a permission error", err)
+	}
+	if v, _ := Field(err, "path"); v != "/home/REDACTED/.ssh/id_rsa" {
+		t.Errorf("Field(path) = %v, want the redacted path", v)
+	}
+
+	err = WrapPath(&os.LinkError{Op: "rename", Old: "/home/alice/a", New: "/home/alice/b", Err: io.ErrShortWrite}, "", "")
+	if got, want := err.Error(), "rename /home/REDACTED/a /home/REDACTED/b: short write"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if v, _ := Field(err, "new_path"); v != "/home/REDACTED/b" {
+		t.Errorf("Field(new_path) = %v, want the redacted new path", v)
+	}
+	if KindOf(err) != KindUnknown {
+		t.Errorf("KindOf() = %q, want no kind", KindOf(err))
+	}
+}
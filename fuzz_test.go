@@ -0,0 +1,17 @@
+//go:build go1.18
+// +build go1.18
+
+package errors
+
+import "testing"
+
+func FuzzMixedChains(f *testing.F) {
+	f.Add([]byte{opWrap, opFmtErrorf, opWithStack})
+	f.Add([]byte{opFmtErrorf, opErrorf, opWraplazy, opWrapf})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		if len(ops) > 32 {
+			ops = ops[:32]
+		}
+		checkChain(t, ops)
+	})
+}
@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// Group is the interface of the groups of goroutines run by a LabelGroup,
+// implemented by *errgroup.Group of golang.org/x/sync/errgroup.
+type Group interface {
+	Go(f func() error)
+	Wait() error
+}
+
+// LabelGroup runs the goroutines of a Group under labels, see
+// WithLabelGroup.
+type LabelGroup struct {
+	g Group
+}
+
+// WithLabelGroup returns a LabelGroup running its goroutines in g, for
+// instance an errgroup.Group:
+//
+//	g, ctx := errgroup.WithContext(ctx)
+//	lg := errors.WithLabelGroup(g)
+//	lg.Go("fetch users", func() error { return fetchUsers(ctx) })
+//	lg.Go("fetch orders", func() error { return fetchOrders(ctx) })
+//	err := lg.Wait() // "fetch orders: ..."
+//
+// The errors returned by the goroutines are wrapped with their label, so the
+// first error returned by Wait tells which goroutine failed.
+func WithLabelGroup(g Group) *LabelGroup {
+	return &LabelGroup{g: g}
+}
+
+// Go calls f in a new goroutine of the group. If f returns an error, it is
+// wrapped with the message label and the stack trace at the point Go was
+// called, and has the fields "task", the label, and "goroutine", the ID of
+// the goroutine f ran in.
+func (g *LabelGroup) Go(label string, f func() error) {
+	st := callers()
+	g.g.Go(func() error {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		err = &withStack{
+			error:   err,
+			stack:   st,
+			created: timestamp(),
+			msg:     label,
+			wrapped: true,
+		}
+		return WithFields(err, map[string]interface{}{
+			"task":      label,
+			"goroutine": goroutineID(),
+		})
+	})
+}
+
+// Wait waits for the goroutines of the group, and returns the error of the
+// underlying Group.
+func (g *LabelGroup) Wait() error {
+	return g.g.Wait()
+}
+
+// goroutineID returns the ID of the calling goroutine, 0 if unknown.
+func goroutineID() uint64 {
+	var buf [64]byte
+	// the trace starts with "goroutine ID [status]:"
+	b := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
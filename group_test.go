@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// firstErrorGroup is a Group with the semantics of errgroup.Group.
+type firstErrorGroup struct {
+	wg   sync.WaitGroup
+	once sync.Once
+	err  error
+}
+
+func (g *firstErrorGroup) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := f(); err != nil {
+			g.once.Do(func() { g.err = err })
+		}
+	}()
+}
+
+func (g *firstErrorGroup) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
+
+func TestLabelGroup(t *testing.T) {
+	g := WithLabelGroup(&firstErrorGroup{})
+	g.Go("fetch users", func() error { return nil })
+	g.Go("fetch orders", func() error { return io.EOF })
+	err := g.Wait()
+	if err == nil || err.Error() != "fetch orders: EOF" {
+		t.Fatalf("Wait() = %v, want fetch orders: EOF", err)
+	}
+	if Cause(err) != io.EOF {
+		t.Errorf("Cause(err) = %v, want io.EOF", Cause(err))
+	}
+	if task, _ := Field(err, "task"); task != "fetch orders" {
+		t.Errorf("task = %v, want fetch orders", task)
+	}
+	if id, _ := Field(err, "goroutine"); id == uint64(0) || id == goroutineID() {
+		t.Errorf("goroutine = %v, want the ID of another goroutine", id)
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, "fetch orders\ngithub.com/objenious/errors.TestLabelGroup\n") {
+		t.Errorf("stack trace does not start at the call of Go:\n%s", got)
+	}
+
+	g = WithLabelGroup(&firstErrorGroup{})
+	g.Go("noop", func() error { return nil })
+	if err := g.Wait(); err != nil {
+		t.Errorf("Wait() = %v, want nil", err)
+	}
+}
@@ -0,0 +1,148 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// Header is a message header, as carried by Kafka records or AMQP messages.
+type Header struct {
+	Key   string
+	Value string
+}
+
+// headerPrefix prefixes the keys of the headers built by EncodeHeaders.
+const headerPrefix = "error-"
+
+// headersBudget is the size set by SetHeadersBudget.
+var headersBudget int32 = 4096
+
+// SetHeadersBudget sets the maximum size, in bytes of keys and values, of the
+// headers built by EncodeHeaders. The default is 4096.
+func SetHeadersBudget(n int) {
+	atomic.StoreInt32(&headersBudget, int32(n))
+}
+
+// EncodeHeaders returns headers describing err, for consumers to attach to a
+// failed message before retrying it or sending it to a dead-letter queue:
+//
+//	error-message: query devices: connection refused
+//	error-kind: unavailable
+//	error-field-db: devices
+//	error-stack: main.query /src/main.go:12\nmain.main /src/main.go:5
+//
+// The headers are, in order: the message of err, its kind, code, reference
+// ID and creation time, its fields, and the frames of its deepest stack
+// trace, one per line. They fit in the budget set by SetHeadersBudget: the
+// message is truncated to half of it, then the headers that do not fit are
+// left out, and the stack trace is cut after the frames that fit.
+// If err is nil, EncodeHeaders returns nil.
+func EncodeHeaders(err error) []Header {
+	if err == nil {
+		return nil
+	}
+	budget := int(atomic.LoadInt32(&headersBudget))
+	var headers []Header
+	add := func(key, value string) {
+		key = headerPrefix + key
+		if value == "" || len(key)+len(value) > budget {
+			return
+		}
+		headers = append(headers, Header{Key: key, Value: value})
+		budget -= len(key) + len(value)
+	}
+
+	msg := err.Error()
+	if max := budget/2 - len(headerPrefix+"message"); len(msg) > max {
+		msg = truncateString(msg, max)
+	}
+	add("message", msg)
+	add("kind", string(KindOf(err)))
+	add("code", string(CodeOf(err)))
+	add("ref-id", RefID(err))
+	if created, ok := CreatedAt(err); ok {
+		add("time", created.Format(time.RFC3339Nano))
+	}
+	fields := Fields(err)
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		add("field-"+key, fmt.Sprint(fields[key]))
+	}
+	frames := DeepestStack(err)
+	for n := len(frames); n > 0; n-- {
+		stack := strings.Join(frames[:n], "\n")
+		if len(headerPrefix+"stack")+len(stack) <= budget {
+			add("stack", stack)
+			break
+		}
+	}
+	return headers
+}
+
+// DecodeHeaders returns the error described by the headers built by
+// EncodeHeaders, ignoring the other headers: a *RemoteError with the message,
+// kind, code, reference ID, creation time and stack trace of the original
+// error, annotated with its fields, whose values are strings.
+// If there is no "error-message" header, DecodeHeaders returns nil.
+func DecodeHeaders(headers []Header) error {
+	fields := make(map[string]interface{})
+	values := make(map[string]string, len(headers))
+	for _, h := range headers {
+		if !strings.HasPrefix(h.Key, headerPrefix) {
+			continue
+		}
+		key := strings.TrimPrefix(h.Key, headerPrefix)
+		if strings.HasPrefix(key, "field-") {
+			fields[strings.TrimPrefix(key, "field-")] = h.Value
+		} else {
+			values[key] = h.Value
+		}
+	}
+	msg, ok := values["message"]
+	if !ok {
+		return nil
+	}
+	remote := &RemoteError{
+		Message: msg,
+		Kind:    Kind(values["kind"]),
+		Code:    Code(values["code"]),
+		RefID:   values["ref-id"],
+	}
+	if ts, ok := values["time"]; ok {
+		remote.Time, _ = time.Parse(time.RFC3339Nano, ts)
+	}
+	if stack, ok := values["stack"]; ok {
+		remote.Stack = strings.Split(stack, "\n")
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var err error = remote
+	for _, key := range keys {
+		err = WithField(err, key, fields[key])
+	}
+	return err
+}
+
+// truncateString returns the first max bytes of s, followed by "...", without
+// cutting a rune in half.
+func truncateString(s string, max int) string {
+	max -= len("...")
+	if max <= 0 {
+		return ""
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max] + "..."
+}
@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeHeaders(t *testing.T) {
+	if got := EncodeHeaders(nil); got != nil {
+		t.Errorf("EncodeHeaders(nil) = %v, want nil", got)
+	}
+
+	err := WithKind(WithField(WithField(Wrap(New("connection refused"), "query devices"), "db", "devices"), "attempt", 3), KindUnavailable)
+	headers := EncodeHeaders(err)
+	want := []Header{
+		{"error-message", "query devices: connection refused"},
+		{"error-kind", "unavailable"},
+		{"error-field-attempt", "3"},
+		{"error-field-db", "devices"},
+	}
+	if len(headers) != len(want)+1 || !reflect.DeepEqual(headers[:len(want)], want) {
+		t.Fatalf("EncodeHeaders() = %q, want %q and a stack", headers, want)
+	}
+	if stack := headers[len(want)]; stack.Key != "error-stack" || !strings.Contains(stack.Value, "TestEncodeHeaders") {
+		t.Errorf("EncodeHeaders() stack = %q", stack)
+	}
+
+	decoded := DecodeHeaders(append([]Header{{"content-type", "application/json"}}, headers...))
+	if decoded.Error() != err.Error() {
+		t.Errorf("DecodeHeaders().Error() = %q, want %q", decoded.Error(), err.Error())
+	}
+	if KindOf(decoded) != KindUnavailable {
+		t.Errorf("KindOf(DecodeHeaders()) = %q, want %q", KindOf(decoded), KindUnavailable)
+	}
+	if got := Fields(decoded); !reflect.DeepEqual(got, map[string]interface{}{"attempt": "3", "db": "devices"}) {
+		t.Errorf("Fields(DecodeHeaders()) = %v", got)
+	}
+	var remote *RemoteError
+	if !As(decoded, &remote) || len(remote.Stack) != len(strings.Split(headers[len(want)].Value, "\n")) {
+		t.Errorf("DecodeHeaders() stack = %v", remote)
+	}
+
+	if got := DecodeHeaders([]Header{{"content-type", "application/json"}}); got != nil {
+		t.Errorf("DecodeHeaders() without error headers = %v, want nil", got)
+	}
+}
+
+func TestSetHeadersBudget(t *testing.T) {
+	defer SetHeadersBudget(4096)
+	SetHeadersBudget(64)
+
+	err := WithField(Wrap(New(strings.Repeat("é", 20)), "query devices"), "db", "devices")
+	size := 0
+	for _, h := range EncodeHeaders(err) {
+		size += len(h.Key) + len(h.Value)
+		if h.Key == "error-message" && !strings.HasSuffix(h.Value, "...") {
+			t.Errorf("message not truncated: %q", h.Value)
+		}
+	}
+	if size > 64 {
+		t.Errorf("EncodeHeaders() size = %d, want at most 64", size)
+	}
+}
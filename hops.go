@@ -0,0 +1,80 @@
+package errors
+
+import (
+	goerrors "errors"
+	"sync/atomic"
+	"time"
+)
+
+// Hop is a service an error went through.
+type Hop struct {
+	Service string
+	Time    time.Time
+}
+
+// serviceName holds the name set by SetServiceName.
+var serviceName atomic.Value
+
+// SetServiceName sets the name of the running service. When set, ToMap,
+// ToYAML and ToCBOR append a Hop with this name and the current time to the
+// hops of the error, so that the final consumer of an error propagated
+// across services can see its path.
+func SetServiceName(name string) {
+	serviceName.Store(name)
+}
+
+// Hops returns the services the error went through before being decoded, in
+// order. It returns nil if err was not decoded, or no service name was set
+// where it was serialized.
+func Hops(err error) []Hop {
+	var remote *RemoteError
+	if As(err, &remote) {
+		return remote.Hops
+	}
+	return nil
+}
+
+// addHops adds the hops of err, followed by the current service, to m.
+func addHops(m map[string]interface{}, err error) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	hops := Hops(err)
+	if name, _ := serviceName.Load().(string); name != "" {
+		hops = append(hops[:len(hops):len(hops)], Hop{Service: name, Time: time.Now().UTC()})
+	}
+	if len(hops) > 0 {
+		list := make([]interface{}, len(hops))
+		for i, h := range hops {
+			list[i] = map[string]interface{}{
+				"service": h.Service,
+				"time":    h.Time.Format(time.RFC3339Nano),
+			}
+		}
+		m["hops"] = list
+	}
+	return m
+}
+
+// hopsFromMap decodes the hops added by addHops.
+func hopsFromMap(m map[string]interface{}) ([]Hop, error) {
+	list, _ := m["hops"].([]interface{})
+	var hops []Hop
+	for _, item := range list {
+		hm, _ := item.(map[string]interface{})
+		service, ok := hm["service"].(string)
+		if !ok {
+			return nil, goerrors.New("errors: invalid hop")
+		}
+		h := Hop{Service: service}
+		if ts, ok := hm["time"].(string); ok {
+			t, err := time.Parse(time.RFC3339Nano, ts)
+			if err != nil {
+				return nil, err
+			}
+			h.Time = t
+		}
+		hops = append(hops, h)
+	}
+	return hops, nil
+}
@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestHops(t *testing.T) {
+	defer SetServiceName("")
+
+	if got := Hops(Wrap(io.EOF, "local")); got != nil {
+		t.Errorf("Hops(local error): got %v, want nil", got)
+	}
+
+	start := time.Now()
+	SetServiceName("device-api")
+	received, err := FromCBOR(ToCBOR(Wrap(io.EOF, "read")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetServiceName("gateway")
+	data, err := json.Marshal(ToMap(Wrap(received, "forward")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetServiceName("")
+	final, err := FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hops := Hops(Wrap(final, "display"))
+	if len(hops) != 2 || hops[0].Service != "device-api" || hops[1].Service != "gateway" {
+		t.Fatalf("Hops: got %v, want device-api then gateway", hops)
+	}
+	for _, h := range hops {
+		if h.Time.Before(start.Add(-time.Second)) || h.Time.After(time.Now()) {
+			t.Errorf("hop %s: unexpected time %v", h.Service, h.Time)
+		}
+	}
+	if len(received.Hops) != 1 {
+		t.Errorf("serializing a received error modified its hops: %v", received.Hops)
+	}
+
+	if _, err := FromJSON([]byte(`{"message":"x","hops":[{"time":"now"}]}`)); err == nil {
+		t.Errorf("FromJSON with an invalid hop: expected an error")
+	}
+}
@@ -0,0 +1,64 @@
+package errors
+
+import (
+	goerrors "errors"
+	"net/http"
+	"time"
+)
+
+// BadRequest returns an error with the message msg, also its user message,
+// the kind KindInvalid and the HTTP status 400, for handlers to respond in
+// one line:
+//
+//	return errors.BadRequest("missing device ID")
+//
+// BadRequest also records the stack trace at the point it was called.
+func BadRequest(msg string) error {
+	return httpError(callers(), msg, KindInvalid, http.StatusBadRequest)
+}
+
+// Unauthorized returns an error like BadRequest, with the kind
+// KindUnauthenticated and the HTTP status 401.
+func Unauthorized(msg string) error {
+	return httpError(callers(), msg, KindUnauthenticated, http.StatusUnauthorized)
+}
+
+// Forbidden returns an error like BadRequest, with the kind
+// KindPermissionDenied and the HTTP status 403.
+func Forbidden(msg string) error {
+	return httpError(callers(), msg, KindPermissionDenied, http.StatusForbidden)
+}
+
+// NotFound returns an error like BadRequest, with the kind KindNotFound and
+// the HTTP status 404.
+func NotFound(msg string) error {
+	return httpError(callers(), msg, KindNotFound, http.StatusNotFound)
+}
+
+// Conflict returns an error like BadRequest, with the kind KindConflict and
+// the HTTP status 409.
+func Conflict(msg string) error {
+	return httpError(callers(), msg, KindConflict, http.StatusConflict)
+}
+
+// TooManyRequests returns an error like BadRequest, with the kind
+// KindRateLimited and the HTTP status 429, retryable after retryAfter if it
+// is positive, as reported by RetryAfter.
+func TooManyRequests(msg string, retryAfter time.Duration) error {
+	err := httpError(callers(), msg, KindRateLimited, http.StatusTooManyRequests)
+	if retryAfter > 0 {
+		err = WithRetryAfter(err, retryAfter)
+	}
+	return err
+}
+
+// httpError returns an error with the stack st, the message and user message
+// msg, the kind k and the HTTP status.
+func httpError(st *stack, msg string, k Kind, status int) error {
+	err := WithUserMessage(&withStack{
+		error:   goerrors.New(msg),
+		stack:   st,
+		created: timestamp(),
+	}, msg)
+	return WithHTTPStatus(WithKind(err, k), status)
+}
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHTTPErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		kind   Kind
+		status int
+	}{
+		{BadRequest("invalid device"), KindInvalid, http.StatusBadRequest},
+		{Unauthorized("invalid device"), KindUnauthenticated, http.StatusUnauthorized},
+		{Forbidden("invalid device"), KindPermissionDenied, http.StatusForbidden},
+		{NotFound("invalid device"), KindNotFound, http.StatusNotFound},
+		{Conflict("invalid device"), KindConflict, http.StatusConflict},
+		{TooManyRequests("invalid device", 0), KindRateLimited, http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != "invalid device" {
+			t.Errorf("Error() = %q", got)
+		}
+		if got := UserMessage(tt.err); got != "invalid device" {
+			t.Errorf("UserMessage(%v) = %q", tt.err, got)
+		}
+		if got := KindOf(tt.err); got != tt.kind {
+			t.Errorf("KindOf(%v) = %q, want %q", tt.err, got, tt.kind)
+		}
+		if got, _ := HTTPStatus(tt.err); got != tt.status {
+			t.Errorf("HTTPStatus(%v) = %d, want %d", tt.err, got, tt.status)
+		}
+		if frames := GetStackTrace(tt.err).StackTrace(); len(frames) == 0 || fmt.Sprintf("%s", frames[0]) != "httperrors_test.go" {
+			t.Errorf("stack of %v starts at %v", tt.err, frames)
+		}
+		if _, ok := RetryAfter(tt.err); ok {
+			t.Errorf("RetryAfter(%v) is set", tt.err)
+		}
+	}
+	err := TooManyRequests("slow down", time.Minute)
+	if d, ok := RetryAfter(err); !ok || d != time.Minute || !IsRetryable(err) {
+		t.Errorf("RetryAfter(%v) = %v, %v", err, d, ok)
+	}
+}
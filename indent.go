@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// levelIndent is the indentation set by SetLevelIndent.
+var levelIndent int32
+
+// SetLevelIndent sets the number of spaces by which %+v indents each level of
+// the chain of an error relative to the error wrapping it, so that the output
+// shows which frames belong to which wrapper:
+//
+//	    open config.json: no such file or directory
+//	  read config
+//	  main.readConfig
+//	  	/src/main.go:12
+//	load
+//	main.main
+//		/src/main.go:20
+//
+// The default, 0, prints all the levels without indentation.
+func SetLevelIndent(n int) {
+	atomic.StoreInt32(&levelIndent, int32(n))
+}
+
+// formatCause prints the cause of a level of a chain with %+v on s, indented
+// by the level indent.
+func formatCause(s io.Writer, cause error) {
+	n := int(atomic.LoadInt32(&levelIndent))
+	if n <= 0 {
+		formatExtended(s, cause)
+		return
+	}
+	var b strings.Builder
+	formatExtended(precisionWriter{Writer: &b, from: s}, cause)
+	pad := strings.Repeat(" ", n)
+	_, _ = io.WriteString(s, pad+strings.Replace(b.String(), "\n", "\n"+pad, -1))
+}
+
+// precisionWriter is a Writer with the precision of the fmt.State from, if
+// any, for extendedVerb.
+type precisionWriter struct {
+	io.Writer
+	from io.Writer
+}
+
+// Precision returns the precision of the fmt.State the writer is from.
+func (w precisionWriter) Precision() (int, bool) {
+	if p, ok := w.from.(interface{ Precision() (int, bool) }); ok {
+		return p.Precision()
+	}
+	return 0, false
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSetLevelIndent(t *testing.T) {
+	defer SetLevelIndent(0)
+	SetLevelIndent(2)
+
+	err := Wrap(Wrap(New("boom"), "read"), "load")
+	got := fmt.Sprintf("%+.1v", err)
+	want := "^    boom\n" +
+		"    github.com/objenious/errors.TestSetLevelIndent\n" +
+		"    \t.+/indent_test.go:\\d+\n" +
+		"  read\n" +
+		"  github.com/objenious/errors.TestSetLevelIndent\n" +
+		"  \t.+/indent_test.go:\\d+\n" +
+		"load\n" +
+		"github.com/objenious/errors.TestSetLevelIndent\n" +
+		"\t.+/indent_test.go:\\d+$"
+	if !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("%%+.1v:\n got: %s\nwant: %s", got, want)
+	}
+
+	got = fmt.Sprintf("%+.0v", Wrap(Join(New("first"), WithStack(io.EOF)), "batch"))
+	if want := "    first\n      EOF\nbatch"; got != want {
+		t.Errorf("%%+.0v:\n got: %q\nwant: %q", got, want)
+	}
+
+	SetLevelIndent(0)
+	if got := fmt.Sprintf("%+v", err); strings.Contains(got, "\n ") {
+		t.Errorf("levels are indented by default:\n%s", got)
+	}
+}
@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+// kindMatcher infers the kind of errors matching target, or the kind
+// returned by f.
+type kindMatcher struct {
+	target error
+	kind   Kind
+	f      func(error) (Kind, bool)
+}
+
+var (
+	kindsMu sync.RWMutex
+	// registeredKinds are the matchers of RegisterKind and RegisterKindFunc.
+	registeredKinds []kindMatcher
+	// wellKnownKinds are the matchers of the errors of the standard library.
+	wellKnownKinds = []kindMatcher{
+		{target: os.ErrNotExist, kind: KindNotFound},
+		{target: os.ErrExist, kind: KindAlreadyExists},
+		{target: os.ErrPermission, kind: KindPermissionDenied},
+		{target: context.DeadlineExceeded, kind: KindTimeout},
+		{target: context.Canceled, kind: KindCanceled},
+		{f: func(err error) (Kind, bool) {
+			if IsConnRefused(err) || IsConnReset(err) {
+				return KindUnavailable, true
+			}
+			return KindUnknown, false
+		}},
+		{f: func(err error) (Kind, bool) {
+			var t interface{ Timeout() bool }
+			if As(err, &t) && t.Timeout() {
+				return KindTimeout, true
+			}
+			return KindUnknown, false
+		}},
+	}
+)
+
+// RegisterKind teaches InferKind that errors matching target, according to
+// errors.Is, are of kind k. Registered errors take precedence over the well
+// known errors, in the order they were registered.
+func RegisterKind(target error, k Kind) {
+	kindsMu.Lock()
+	defer kindsMu.Unlock()
+	registeredKinds = append(registeredKinds, kindMatcher{target: target, kind: k})
+}
+
+// RegisterKindFunc is like RegisterKind, for errors recognized by f: f
+// returns the kind of the errors it recognizes, and false for the others.
+func RegisterKindFunc(f func(err error) (Kind, bool)) {
+	kindsMu.Lock()
+	defer kindsMu.Unlock()
+	registeredKinds = append(registeredKinds, kindMatcher{f: f})
+}
+
+// InferKind returns the kind of err: the kind it is annotated with if any,
+// see KindOf, or else the kind of the errors its chain matches, among the
+// errors registered with RegisterKind and RegisterKindFunc, then the well
+// known errors of the standard library:
+//
+//   - os.ErrNotExist is of kind KindNotFound, as is sql.ErrNoRows once
+//     package errsql is imported,
+//   - os.ErrExist is of kind KindAlreadyExists,
+//   - os.ErrPermission is of kind KindPermissionDenied,
+//   - context.DeadlineExceeded and timeouts, errors with a Timeout method
+//     returning true such as net.Error, are of kind KindTimeout,
+//   - context.Canceled is of kind KindCanceled,
+//   - refused and reset connections, see IsConnRefused and IsConnReset, are
+//     of kind KindUnavailable.
+//
+// It returns KindUnknown if err is not recognized.
+func InferKind(err error) Kind {
+	if err == nil {
+		return KindUnknown
+	}
+	if k := KindOf(err); k != KindUnknown {
+		return k
+	}
+	kindsMu.RLock()
+	registered := registeredKinds
+	kindsMu.RUnlock()
+	for _, matchers := range [][]kindMatcher{registered, wellKnownKinds} {
+		for _, m := range matchers {
+			if m.f != nil {
+				if k, ok := m.f(err); ok {
+					return k
+				}
+			} else if Is(err, m.target) {
+				return m.kind
+			}
+		}
+	}
+	return KindUnknown
+}
@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestInferKind(t *testing.T) {
+	defer func(registered []kindMatcher) { registeredKinds = registered }(registeredKinds)
+	errQuota := Sentinel("quota exceeded")
+	RegisterKind(errQuota, KindRateLimited)
+	RegisterKind(io.EOF, KindInvalid)
+	RegisterKindFunc(func(err error) (Kind, bool) {
+		if err.Error() == "busy" {
+			return KindUnavailable, true
+		}
+		return KindUnknown, false
+	})
+
+	_, openErr := os.Open("/does/not/exist")
+	tests := []struct {
+		err  error
+		want Kind
+	}{
+		{nil, KindUnknown},
+		{io.ErrUnexpectedEOF, KindUnknown},
+		{openErr, KindNotFound},
+		{Wrap(os.ErrNotExist, "get device"), KindNotFound},
+		{Wrap(os.ErrExist, "create"), KindAlreadyExists},
+		{os.ErrPermission, KindPermissionDenied},
+		{Wrap(context.DeadlineExceeded, "call"), KindTimeout},
+		{context.Canceled, KindCanceled},
+		{&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, KindUnavailable},
+		{&net.DNSError{Err: "timeout", IsTimeout: true}, KindTimeout},
+		{WithKind(os.ErrNotExist, KindInternal), KindInternal},
+		{Wrap(errQuota, "upload"), KindRateLimited},
+		{io.EOF, KindInvalid},
+		{Sentinel("busy"), KindUnavailable},
+	}
+	for i, tt := range tests {
+		if got := InferKind(tt.err); got != tt.want {
+			t.Errorf("test %d: InferKind(%v): got %q, want %q", i+1, tt.err, got, tt.want)
+		}
+	}
+}
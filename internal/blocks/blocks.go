@@ -0,0 +1,129 @@
+// Package blocks parses the errors formatted with %+v by
+// github.com/objenious/errors out of log streams, for cmd/errfmt and the
+// tests of the formatting.
+package blocks
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MaxGap is the number of lines of messages between two stack traces of a
+// block, after which the block ends.
+const MaxGap = 2
+
+// frameFileR matches the file line of a frame printed with %+v.
+var frameFileR = regexp.MustCompile(`^\t(.+):(\d+)$`)
+
+// Frame is a frame of a stack trace.
+type Frame struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// Entry is a message of a block, and the stack traces following it.
+type Entry struct {
+	Message string  `json:"message,omitempty"`
+	Stack   []Frame `json:"stack"`
+}
+
+// Block is an error formatted with %+v.
+type Block struct {
+	Entries []Entry `json:"errors"`
+}
+
+// parser splits lines into blocks and the other lines. It keeps the lines
+// that may still be part of a block until they are known not to be.
+type parser struct {
+	block   *Block   // block being parsed, or nil
+	pending []string // lines not emitted yet
+	text    func(line string) error
+	emit    func(b *Block) error
+}
+
+// Parse reads r, calling text with the lines outside of blocks and emit with
+// the blocks, in order. A block is recognised by its stack traces, each frame
+// being a function name followed by a line with a tab and the file and line.
+// Up to MaxGap lines of messages between stack traces belong to the block,
+// and the block begins at the line preceding its first stack trace.
+func Parse(r io.Reader, text func(line string) error, emit func(b *Block) error) error {
+	p := &parser{text: text, emit: emit}
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		if err := p.line(s.Text()); err != nil {
+			return err
+		}
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	return p.flush(0)
+}
+
+// line parses the line l.
+func (p *parser) line(l string) error {
+	m := frameFileR.FindStringSubmatch(l)
+	if m == nil || len(p.pending) == 0 {
+		p.pending = append(p.pending, l)
+		if p.block == nil {
+			return p.flushText(2)
+		}
+		if len(p.pending) > MaxGap+1 {
+			return p.flush(2)
+		}
+		return nil
+	}
+	n, _ := strconv.Atoi(m[2])
+	f := Frame{Func: p.pending[len(p.pending)-1], File: m[1], Line: n}
+	messages := p.pending[:len(p.pending)-1]
+	p.pending = nil
+	switch {
+	case p.block == nil:
+		// The block begins at the line preceding its first stack trace.
+		start := len(messages) - 1
+		if start < 0 {
+			start = 0
+		}
+		for _, t := range messages[:start] {
+			if err := p.text(t); err != nil {
+				return err
+			}
+		}
+		p.block = &Block{Entries: []Entry{{Message: strings.Join(messages[start:], "\n")}}}
+	case len(messages) > 0:
+		p.block.Entries = append(p.block.Entries, Entry{Message: strings.Join(messages, "\n")})
+	}
+	e := &p.block.Entries[len(p.block.Entries)-1]
+	e.Stack = append(e.Stack, f)
+	return nil
+}
+
+// flush emits the current block, if any, and the pending lines but the last
+// keep ones.
+func (p *parser) flush(keep int) error {
+	if p.block != nil {
+		b := p.block
+		p.block = nil
+		if err := p.emit(b); err != nil {
+			return err
+		}
+	}
+	return p.flushText(keep)
+}
+
+// flushText emits the pending lines but the last keep ones as text.
+func (p *parser) flushText(keep int) error {
+	for len(p.pending) > keep {
+		l := p.pending[0]
+		p.pending = p.pending[1:]
+		if err := p.text(l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
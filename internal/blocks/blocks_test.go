@@ -0,0 +1,101 @@
+package blocks
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/objenious/errors"
+)
+
+const logs = `starting
+2024/01/01 request failed: EOF
+read
+main.read
+	/src/main.go:11
+main.main
+	/src/main.go:20
+load
+main.main
+	/src/main.go:21
+done
+more
+2024/01/01 other: boom
+main.main
+	/src/main.go:30
+`
+
+func parseAll(t *testing.T, s string) ([]string, []*Block) {
+	var lines []string
+	var blocks []*Block
+	err := Parse(strings.NewReader(s), func(l string) error {
+		lines = append(lines, l)
+		return nil
+	}, func(b *Block) error {
+		blocks = append(blocks, b)
+		lines = append(lines, fmt.Sprintf("<block %d>", len(blocks)))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lines, blocks
+}
+
+func TestParse(t *testing.T) {
+	lines, blocks := parseAll(t, logs)
+	wantLines := []string{"starting", "2024/01/01 request failed: EOF", "<block 1>", "done", "more", "<block 2>"}
+	if !reflect.DeepEqual(lines, wantLines) {
+		t.Errorf("lines = %q, want %q", lines, wantLines)
+	}
+	want := []*Block{
+		{Entries: []Entry{
+			{Message: "read", Stack: []Frame{{"main.read", "/src/main.go", 11}, {"main.main", "/src/main.go", 20}}},
+			{Message: "load", Stack: []Frame{{"main.main", "/src/main.go", 21}}},
+		}},
+		{Entries: []Entry{
+			{Message: "2024/01/01 other: boom", Stack: []Frame{{"main.main", "/src/main.go", 30}}},
+		}},
+	}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Errorf("blocks = %+v, want %+v", blocks, want)
+	}
+}
+
+func TestParseGap(t *testing.T) {
+	s := "boom\nmain.main\n\t/src/main.go:1\n" + strings.Repeat("text\n", MaxGap+2) + "f\n\t/src/f.go:2\n"
+	lines, blocks := parseAll(t, s)
+	if len(blocks) != 2 || len(blocks[0].Entries) != 1 {
+		t.Fatalf("blocks = %+v", blocks)
+	}
+	if len(lines) != MaxGap+3 || blocks[1].Entries[0].Message != "text" {
+		t.Errorf("lines = %q, blocks[1] = %+v", lines, blocks[1])
+	}
+}
+
+func TestParseError(t *testing.T) {
+	err := errors.Wrap(errors.New("boom"), "load")
+	_, blocks := parseAll(t, fmt.Sprintf("%+v\n", err))
+	if len(blocks) != 1 || len(blocks[0].Entries) != 2 {
+		t.Fatalf("blocks = %+v", blocks)
+	}
+	for i, msg := range []string{"boom", "load"} {
+		e := blocks[0].Entries[i]
+		if e.Message != msg || !strings.HasSuffix(e.Stack[0].Func, "TestParseError") || e.Stack[0].Line == 0 {
+			t.Errorf("entry %d = %+v", i, e)
+		}
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, io.ErrUnexpectedEOF }
+
+func TestParseReadError(t *testing.T) {
+	err := Parse(failingReader{}, func(string) error { return nil }, func(*Block) error { return nil })
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("Parse() = %v", err)
+	}
+}
@@ -0,0 +1,317 @@
+package errors
+
+import (
+	goerrors "errors"
+	"reflect"
+	"sync"
+)
+
+// asKey is the key of the results of As in the cache of an error.
+type asKey struct {
+	t reflect.Type
+}
+
+// asResult is a result of As.
+type asResult struct {
+	ok    bool
+	value reflect.Value
+}
+
+// maxMatches is the number of targets whose results of Is and As are cached
+// per error, so that targets created dynamically don't grow the cache
+// without bound: the results for the other targets are not cached.
+const maxMatches = 8
+
+// matchCache caches the results of Is and As for an error.
+type matchCache struct {
+	mu      sync.RWMutex
+	keys    []interface{}
+	results []interface{}
+}
+
+// matchCacheMu serializes the allocations of the caches of errors.
+var matchCacheMu sync.Mutex
+
+// cached returns the result cached for key by w, if any.
+func (w *withStack) cached(key interface{}) (interface{}, bool) {
+	c, _ := w.matches.Load().(*matchCache)
+	if c == nil {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for i, k := range c.keys {
+		if k == key {
+			return c.results[i], true
+		}
+	}
+	return nil, false
+}
+
+// cache caches the result for key by w, unless it already caches maxMatches
+// results.
+func (w *withStack) cache(key, result interface{}) {
+	c, _ := w.matches.Load().(*matchCache)
+	if c == nil {
+		matchCacheMu.Lock()
+		if c, _ = w.matches.Load().(*matchCache); c == nil {
+			c = &matchCache{}
+			w.matches.Store(c)
+		}
+		matchCacheMu.Unlock()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.keys) < maxMatches {
+		c.keys = append(c.keys, key)
+		c.results = append(c.results, result)
+	}
+}
+
+// Is is like errors.Is of the standard library, for services checking the
+// same deep chains repeatedly, in retry loops for instance: the result for
+// an error created by this package is cached, so that the chain is only
+// walked once per target. Errors of the chain must therefore match the same
+// targets over time, as is the case of most errors: the results are not
+// cached for the chains walked through a Partial or an ErrorMap, which may
+// get more errors after they are wrapped. In chains with a cycle,
+// the errors that are not found in the first 4096 errors walked are not
+// found, the chain matching ErrCycle instead, see CheckCycle.
+//
+// A Kind or a Code only matches the kind or code of err, as returned by
+// KindOf and CodeOf, if it has one: the kinds and codes overridden by outer
+// annotations do not match, unlike with errors.Is of the standard library.
+func Is(err, target error) bool {
+	switch t := target.(type) {
+	case Kind:
+		if k := KindOf(err); k != KindUnknown {
+			return k == t
+		}
+	case Code:
+		if c := CodeOf(err); c != "" {
+			return c == t
+		}
+	}
+	w, ok := err.(*withStack)
+	if !ok || target == nil || !reflect.TypeOf(target).Comparable() {
+		is, _ := isChain(err, target)
+		return is
+	}
+	if is, ok := w.cached(target); ok {
+		return is.(bool)
+	}
+	is, cacheable := isChain(err, target)
+	if cacheable {
+		w.cache(target, is)
+	}
+	return is
+}
+
+// As is like errors.As of the standard library, and caches its results like
+// Is: the chain of an error created by this package is only walked once per
+// type of target.
+func As(err error, target interface{}) bool {
+	w, ok := err.(*withStack)
+	if !ok || target == nil {
+		ok, _ = asChain(err, target)
+		return ok
+	}
+	val := reflect.ValueOf(target)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		// let the standard library panic.
+		return goerrors.As(err, target)
+	}
+	key := asKey{val.Type().Elem()}
+	if r, ok := w.cached(key); ok {
+		res := r.(asResult)
+		if res.ok {
+			val.Elem().Set(res.value)
+		}
+		return res.ok
+	}
+	ok, cacheable := asChain(err, target)
+	if !cacheable {
+		return ok
+	}
+	res := asResult{ok: ok}
+	if ok {
+		res.value = reflect.New(key.t).Elem()
+		res.value.Set(val.Elem())
+	}
+	w.cache(key, res)
+	return ok
+}
+
+// causesMatcher is implemented by the errors whose Is and As methods only
+// match their causes, for the versions of Go whose errors.Is and errors.As
+// don't unwrap several errors. isChain and asChain skip these methods, the
+// causes being walked anyway.
+type causesMatcher interface {
+	matchesCauses()
+}
+
+// mutableError is implemented by the errors whose causes may change after
+// they are wrapped, such as Partial and ErrorMap: the results of Is and As
+// for the chains walked through them are not cached.
+type mutableError interface {
+	mutableCauses()
+}
+
+// chainWalk is the state of the walks of matchIs and matchAs.
+type chainWalk struct {
+	n       int  // number of errors left to walk, negative once exhausted
+	mutable bool // whether a mutableError was walked
+}
+
+// next counts err as walked, and reports whether the walk goes on.
+func (c *chainWalk) next(err error) bool {
+	if c.n--; c.n < 0 {
+		return false
+	}
+	if _, ok := err.(mutableError); ok {
+		c.mutable = true
+	}
+	return true
+}
+
+// unwrapNext returns the cause of err walked next by errors.Is and
+// errors.As, or the causes of err if it wraps several errors.
+func unwrapNext(err error) (error, []error) {
+	switch x := err.(type) {
+	case *withStack:
+		if multi, ok := x.multi(); ok {
+			return multi, nil
+		}
+		return x.Unwrap(), nil
+	case interface{ Unwrap() error }:
+		return x.Unwrap(), nil
+	case interface{ Unwrap() []error }:
+		return nil, x.Unwrap()
+	}
+	return nil, nil
+}
+
+// isChain is errors.Is, walking at most maxDepth errors of the chain of err:
+// if it has more, the chain is checked for a cycle, see CheckCycle. It also
+// reports whether the result can be cached, no mutableError being walked.
+func isChain(err, target error) (bool, bool) {
+	if err == nil || target == nil {
+		return err == target, true
+	}
+	c := chainWalk{n: maxDepth}
+	if matchIs(err, target, reflect.TypeOf(target).Comparable(), &c) {
+		return true, !c.mutable
+	}
+	if c.n >= 0 {
+		return false, !c.mutable
+	}
+	return goerrors.Is(acyclic(err), target), !c.mutable
+}
+
+// matchIs reports whether an error of the chain of err matches target, as
+// errors.Is does, counting the errors walked by c, until it stops the walk.
+func matchIs(err, target error, comparable bool, c *chainWalk) bool {
+	for err != nil {
+		if !c.next(err) {
+			return false
+		}
+		if comparable && err == target {
+			return true
+		}
+		if _, ok := err.(causesMatcher); !ok {
+			if x, ok := err.(interface{ Is(error) bool }); ok && x.Is(target) {
+				return true
+			}
+		}
+		next, errs := unwrapNext(err)
+		for _, err := range errs {
+			if matchIs(err, target, comparable, c) {
+				return true
+			}
+		}
+		err = next
+	}
+	return false
+}
+
+// asChain is errors.As, walking at most maxDepth errors of the chain of err
+// like isChain, and also reports whether the result can be cached.
+func asChain(err error, target interface{}) (bool, bool) {
+	if err == nil {
+		return false, true
+	}
+	val := reflect.ValueOf(target)
+	if target == nil || val.Kind() != reflect.Ptr || val.IsNil() {
+		// let the standard library panic.
+		return goerrors.As(err, target), false
+	}
+	typ := val.Type().Elem()
+	if typ.Kind() != reflect.Interface && !typ.Implements(errorType) {
+		// let the standard library panic.
+		return goerrors.As(err, target), false
+	}
+	c := chainWalk{n: maxDepth}
+	if matchAs(err, target, val, typ, &c) {
+		return true, !c.mutable
+	}
+	if c.n >= 0 {
+		return false, !c.mutable
+	}
+	return goerrors.As(acyclic(err), target), !c.mutable
+}
+
+// errorType is the type of the error interface.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// matchAs sets target to the first error of the chain of err that matches
+// it, as errors.As does, counting the errors walked by c, until it stops the
+// walk. val and typ are the value of target and the type it points to.
+func matchAs(err error, target interface{}, val reflect.Value, typ reflect.Type, c *chainWalk) bool {
+	for err != nil {
+		if !c.next(err) {
+			return false
+		}
+		if reflect.TypeOf(err).AssignableTo(typ) {
+			val.Elem().Set(reflect.ValueOf(err))
+			return true
+		}
+		if _, ok := err.(causesMatcher); !ok {
+			if x, ok := err.(interface{ As(interface{}) bool }); ok && x.As(target) {
+				return true
+			}
+		}
+		next, errs := unwrapNext(err)
+		for _, err := range errs {
+			if matchAs(err, target, val, typ, c) {
+				return true
+			}
+		}
+		err = next
+	}
+	return false
+}
+
+// Ignore returns nil if err matches any of targets, according to Is, and err
+// otherwise, for the errors that are expected at a call site:
+//
+//	err := db.QueryRow(query, id).Scan(&name)
+//	return errors.Ignore(err, sql.ErrNoRows)
+func Ignore(err error, targets ...error) error {
+	for _, target := range targets {
+		if Is(err, target) {
+			return nil
+		}
+	}
+	return err
+}
+
+// Only returns err if it matches any of targets, according to Is, and nil
+// otherwise.
+func Only(err error, targets ...error) error {
+	for _, target := range targets {
+		if Is(err, target) {
+			return err
+		}
+	}
+	return nil
+}
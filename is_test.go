@@ -0,0 +1,80 @@
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestIsAs(t *testing.T) {
+	pathErr := &os.PathError{Op: "open", Path: "config.json", Err: os.ErrNotExist}
+	err := Wrap(deepChain(10), "outer")
+	chain := Wrap(Wrap(pathErr, "read"), "load")
+	for i := 0; i < 2; i++ {
+		if Is(err, io.EOF) || !Is(chain, os.ErrNotExist) || Is(nil, io.EOF) || !Is(io.EOF, io.EOF) {
+			t.Errorf("pass %d: Is: got unexpected results", i+1)
+		}
+		var target *os.PathError
+		if !As(chain, &target) || target != pathErr {
+			t.Errorf("pass %d: As(*os.PathError): got %v", i+1, target)
+		}
+		var timeout interface{ Timeout() bool }
+		if !As(chain, &timeout) || timeout != pathErr {
+			t.Errorf("pass %d: As(interface): got %v", i+1, timeout)
+		}
+		var countErr *countingError
+		if As(err, &countErr) || countErr != nil {
+			t.Errorf("pass %d: As(*countingError): got %v", i+1, countErr)
+		}
+	}
+	// uncomparable targets are left to the standard library.
+	if Is(chain, uncomparable{}) != goerrors.Is(chain, uncomparable{}) {
+		t.Errorf("Is(uncomparable): got a different result than errors.Is")
+	}
+}
+
+func TestIsCacheBound(t *testing.T) {
+	err := Wrap(io.EOF, "read")
+	for i := 0; i < 2*maxMatches; i++ {
+		if Is(err, StringError(fmt.Sprint(i))) {
+			t.Errorf("Is(%d): got true", i)
+		}
+	}
+	if c := err.(*withStack).matches.Load().(*matchCache); len(c.keys) != maxMatches {
+		t.Errorf("got %d cached results, want %d", len(c.keys), maxMatches)
+	}
+	if !Is(err, io.EOF) || !Is(err, io.EOF) {
+		t.Errorf("Is(io.EOF): got false once the cache is full")
+	}
+}
+
+type uncomparable []string
+
+func (uncomparable) Error() string { return "uncomparable" }
+
+func TestIgnoreOnly(t *testing.T) {
+	err := Wrap(io.EOF, "read")
+	notFound := WithKind(err, KindNotFound)
+	tests := []struct {
+		err          error
+		targets      []error
+		ignore, only error
+	}{
+		{nil, []error{io.EOF}, nil, nil},
+		{err, nil, err, nil},
+		{err, []error{io.EOF}, nil, err},
+		{err, []error{os.ErrNotExist, io.EOF}, nil, err},
+		{err, []error{os.ErrNotExist}, err, nil},
+		{notFound, []error{KindNotFound}, nil, notFound},
+	}
+	for _, tt := range tests {
+		if got := Ignore(tt.err, tt.targets...); got != tt.ignore {
+			t.Errorf("Ignore(%v, %v) = %v, want %v", tt.err, tt.targets, got, tt.ignore)
+		}
+		if got := Only(tt.err, tt.targets...); got != tt.only {
+			t.Errorf("Only(%v, %v) = %v, want %v", tt.err, tt.targets, got, tt.only)
+		}
+	}
+}
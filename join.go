@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Join returns an error wrapping errs, like errors.Join of Go 1.20, whatever
+// the version of Go. Nil errors are discarded, and Join returns nil if errs
+// contains no non-nil error. Its message is made of the messages of errs,
+// separated by newlines; with %+v, each error is printed with its stack
+// trace.
+func Join(errs ...error) error {
+	n := 0
+	for _, err := range errs {
+		if err != nil {
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	e := &joinError{errs: make([]error, 0, n)}
+	for _, err := range errs {
+		if err != nil {
+			e.errs = append(e.errs, err)
+		}
+	}
+	return e
+}
+
+type joinError struct {
+	errs []error
+}
+
+func (e *joinError) Error() string {
+	msgs := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the joined errors
+func (e *joinError) Unwrap() []error {
+	return e.errs
+}
+
+// Format formats the joined errors, with their stack traces for %+v
+func (e *joinError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatIndented(s, e) {
+				return
+			}
+			for i, err := range e.errs {
+				if i > 0 {
+					_, _ = io.WriteString(s, "\n")
+				}
+				formatCause(s, err)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	}
+}
@@ -0,0 +1,120 @@
+//go:build go1.20
+// +build go1.20
+
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestJoinedChains(t *testing.T) {
+	root := &chainRoot{}
+	joined := goerrors.Join(WithStack(io.EOF), fmt.Errorf("decode: %w", Wrap(root, "field")))
+	for _, err := range []error{
+		joined,
+		Wrap(joined, "batch"),
+		WithStack(joined),
+		fmt.Errorf("run: %w", Wrap(joined, "batch")),
+		fmt.Errorf("run: %w, %w", Wrap(io.EOF, "read"), root),
+	} {
+		if !goerrors.Is(err, io.EOF) {
+			t.Errorf("%q: errors.Is(err, io.EOF) = false", err)
+		}
+		var target *chainRoot
+		if !goerrors.As(err, &target) || target != root {
+			t.Errorf("%q: errors.As(err, &target) = false", err)
+		}
+		if got := fmt.Sprintf("%+v", err); !strings.Contains(got, "root") || !strings.Contains(got, "EOF") {
+			t.Errorf("%q: %%+v lost messages: %q", err, got)
+		}
+	}
+}
+
+func TestCauseJoined(t *testing.T) {
+	root := &chainRoot{}
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{goerrors.Join(root, io.EOF), root},
+		{goerrors.Join(nil, Wrap(root, "first"), io.EOF), root},
+		{Wrap(goerrors.Join(WithStack(root), io.EOF), "wrapped"), root},
+		{fmt.Errorf("%w: %w", io.EOF, root), io.EOF},
+		{Errorf("%w: %w", root, io.EOF), root},
+	}
+	for i, tt := range tests {
+		if got := Cause(tt.err); got != tt.want {
+			t.Errorf("test %d: Cause(%q): got %#v, want %#v", i+1, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestErrorfMultipleWraps(t *testing.T) {
+	root := &chainRoot{}
+	err := Errorf("%w: %w", WithStack(io.EOF), root)
+	if !goerrors.Is(err, io.EOF) || !goerrors.Is(err, root) {
+		t.Errorf("Errorf(%%w: %%w): errors.Is don't find the wrapped errors")
+	}
+	var target *chainRoot
+	if !goerrors.As(err, &target) || target != root {
+		t.Errorf("Errorf(%%w: %%w): errors.As(err, &target) = false")
+	}
+	if goerrors.Is(err, goerrors.New("EOF")) {
+		t.Errorf("Errorf(%%w: %%w): errors.Is matches an unrelated error")
+	}
+}
+
+func TestGetStackTraceJoined(t *testing.T) {
+	err := Wrap(goerrors.Join(io.EOF, New("first"), New("second")), "wrapped")
+	testFormatRegexp(t, 0, GetStackTrace(err).StackTrace()[0], "%+v",
+		"github.com/objenious/errors.TestGetStackTraceJoined\n"+
+			"\t.+/github.com/objenious/errors/join_test.go:72")
+}
+
+func TestFormatJoined(t *testing.T) {
+	tests := []struct {
+		error
+		format string
+		want   []string
+	}{{
+		Wrap(goerrors.Join(New("first"), io.EOF, New("second")), "batch"),
+		"%+v",
+		[]string{"first",
+			"github.com/objenious/errors.TestFormatJoined\n" +
+				"\t.+/github.com/objenious/errors/join_test.go:84",
+			"EOF",
+			"second",
+			"github.com/objenious/errors.TestFormatJoined\n" +
+				"\t.+/github.com/objenious/errors/join_test.go:84",
+			"batch",
+			"github.com/objenious/errors.TestFormatJoined\n" +
+				"\t.+/github.com/objenious/errors/join_test.go:84"},
+	}, {
+		WithStack(fmt.Errorf("decode %w, %w", Wrap(io.EOF, "header"), io.ErrUnexpectedEOF)),
+		"%+v",
+		[]string{"EOF",
+			"header",
+			"github.com/objenious/errors.TestFormatJoined\n" +
+				"\t.+/github.com/objenious/errors/join_test.go:97",
+			"unexpected EOF",
+			"decode header: EOF, unexpected EOF",
+			"github.com/objenious/errors.TestFormatJoined\n" +
+				"\t.+/github.com/objenious/errors/join_test.go:97"},
+	}, {
+		Wrap(goerrors.Join(io.EOF, io.ErrUnexpectedEOF), "batch"),
+		"%+v",
+		[]string{"EOF",
+			"unexpected EOF",
+			"batch",
+			"github.com/objenious/errors.TestFormatJoined\n" +
+				"\t.+/github.com/objenious/errors/join_test.go:108"},
+	}}
+
+	for i, tt := range tests {
+		testFormatCompleteCompare(t, i, tt.error, tt.format, tt.want, true)
+	}
+}
@@ -0,0 +1,160 @@
+package errors
+
+import "sort"
+
+// jsonSchemaDialect is the JSON Schema dialect of JSONSchema.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns the JSON Schema (draft 2020-12) of the representation of
+// errors encoded by ToJSON, to publish along with APIs returning it, once
+// marshalled to JSON. The schema documents the kinds of this package and the
+// codes registered with RegisterCode; other keys, such as fields, are
+// allowed. See OpenAPISchemas for OpenAPI documents.
+func JSONSchema() map[string]interface{} {
+	root := errorSchema("#/$defs/cause")
+	root["$schema"] = jsonSchemaDialect
+	root["title"] = "Error"
+	addTopLevel(root)
+	root["$defs"] = map[string]interface{}{"cause": errorSchema("#/$defs/cause")}
+	return root
+}
+
+// OpenAPISchemas returns the schemas of the representation of errors encoded
+// by ToJSON, like JSONSchema, for the components.schemas object of OpenAPI
+// 3.1 documents: "Error" is the schema of the representation, and
+// "ErrorCause" the schema of its causes and warnings.
+func OpenAPISchemas() map[string]interface{} {
+	const ref = "#/components/schemas/ErrorCause"
+	top := errorSchema(ref)
+	top["title"] = "Error"
+	addTopLevel(top)
+	return map[string]interface{}{
+		"Error":      top,
+		"ErrorCause": errorSchema(ref),
+	}
+}
+
+// errorSchema returns the schema of a level of the representation built by
+// ToMap, its causes and warnings referring to the schema ref.
+func errorSchema(ref string) map[string]interface{} {
+	str := func(description string) map[string]interface{} {
+		return map[string]interface{}{"type": "string", "description": description}
+	}
+	causes := map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": ref}}
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"message"},
+		"properties": map[string]interface{}{
+			"message": str("The message of the error, including the messages of its causes."),
+			"stack": map[string]interface{}{
+				"type":        "array",
+				"description": "The frames of the stack trace recorded by the error, most recent call first.",
+				"items":       map[string]interface{}{"type": "string"},
+			},
+			"time": map[string]interface{}{
+				"type":        "string",
+				"format":      "date-time",
+				"description": "The time the error was created.",
+			},
+			"kind":         kindSchema(),
+			"code":         codeSchema(),
+			"ref_id":       str("The reference ID of the error, for support."),
+			"fingerprint":  str("The fingerprint of the error, identifying its occurrences."),
+			"user_message": str("The message to show to end users."),
+			"http_status":  map[string]interface{}{"type": "integer", "description": "The HTTP status to respond with."},
+			"retryable":    map[string]interface{}{"type": "boolean", "description": "Whether the operation that failed can be retried."},
+			"retry_after":  str("The delay after which the operation can be retried, such as \"1.5s\"."),
+			"rate_limit": map[string]interface{}{
+				"type":        "object",
+				"description": "The rate limit of the API when the error occurred.",
+				"properties": map[string]interface{}{
+					"limit":     map[string]interface{}{"type": "integer"},
+					"remaining": map[string]interface{}{"type": "integer"},
+					"reset":     map[string]interface{}{"type": "string", "format": "date-time"},
+				},
+			},
+			"fields": map[string]interface{}{
+				"type":        "object",
+				"description": "The fields the error is annotated with.",
+			},
+			"truncated": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Whether the representation was cut down to the maximum size of errors, or the stack trace, maps or causes of this level cut.",
+			},
+			"cause":    map[string]interface{}{"$ref": ref},
+			"causes":   causes,
+			"warnings": causes,
+		},
+	}
+}
+
+// kindSchema returns the schema of the kinds of errors, documenting the
+// kinds of this package without rejecting the others.
+func kindSchema() map[string]interface{} {
+	kinds := make([]string, 0, len(kindStatuses))
+	for k := range kindStatuses {
+		kinds = append(kinds, string(k))
+	}
+	sort.Strings(kinds)
+	anyOf := make([]interface{}, 0, len(kinds)+1)
+	for _, k := range kinds {
+		anyOf = append(anyOf, map[string]interface{}{"const": k})
+	}
+	return map[string]interface{}{
+		"type":        "string",
+		"description": "The kind of the error.",
+		"anyOf":       append(anyOf, map[string]interface{}{"description": "A kind defined by the application."}),
+	}
+}
+
+// codeSchema returns the schema of the codes of errors, documenting the
+// registered codes without rejecting the others.
+func codeSchema() map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":        "string",
+		"description": "The code identifying the error condition.",
+	}
+	registered := RegisteredCodes()
+	if len(registered) == 0 {
+		return schema
+	}
+	codes := make([]string, 0, len(registered))
+	for c := range registered {
+		codes = append(codes, string(c))
+	}
+	sort.Strings(codes)
+	anyOf := make([]interface{}, 0, len(codes)+1)
+	for _, c := range codes {
+		anyOf = append(anyOf, map[string]interface{}{"const": c, "description": registered[Code(c)]})
+	}
+	schema["anyOf"] = append(anyOf, map[string]interface{}{"description": "An unregistered code."})
+	return schema
+}
+
+// addTopLevel adds the keys of the outermost level of the representation
+// encoded by ToJSON to schema.
+func addTopLevel(schema map[string]interface{}) {
+	properties := schema["properties"].(map[string]interface{})
+	properties["schema"] = map[string]interface{}{
+		"type":        "integer",
+		"description": "The version of the representation.",
+		"const":       SchemaVersion,
+	}
+	properties["summary"] = map[string]interface{}{
+		"type":        "string",
+		"description": "The error on a single line, for indexing.",
+		"maxLength":   MaxSummaryLength,
+	}
+	properties["hops"] = map[string]interface{}{
+		"type":        "array",
+		"description": "The services the error went through.",
+		"items": map[string]interface{}{
+			"type":     "object",
+			"required": []interface{}{"service", "time"},
+			"properties": map[string]interface{}{
+				"service": map[string]interface{}{"type": "string"},
+				"time":    map[string]interface{}{"type": "string", "format": "date-time"},
+			},
+		},
+	}
+}
@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	defer func(codes map[Code]string) { registeredCodes = codes }(registeredCodes)
+	registeredCodes = map[Code]string{}
+	RegisterCode("DEV-0042", "The device battery is low.")
+
+	schema := JSONSchema()
+	if _, err := json.Marshal(schema); err != nil {
+		t.Fatal(err)
+	}
+	code := schema["properties"].(map[string]interface{})["code"].(map[string]interface{})
+	want := []interface{}{
+		map[string]interface{}{"const": "DEV-0042", "description": "The device battery is low."},
+		map[string]interface{}{"description": "An unregistered code."},
+	}
+	if !reflect.DeepEqual(code["anyOf"], want) {
+		t.Errorf("code schema = %v, want anyOf %v", code, want)
+	}
+
+	kind := schema["properties"].(map[string]interface{})["kind"].(map[string]interface{})
+	kinds := kind["anyOf"].([]interface{})
+	if _, ok := kind["enum"]; ok || len(kinds) != len(kindStatuses)+1 || kinds[len(kinds)-1].(map[string]interface{})["const"] != nil {
+		t.Errorf("kind schema = %v, want the known kinds and any other", kind)
+	}
+
+	// every key of the representation of a detailed error is documented.
+	err := WithRetryAfter(WithUserMessage(WithRefID(WithHTTPStatus(WithCode(WithKind(
+		Wrap(Join(io.EOF, New("boom")), "sync"), KindUnavailable), "DEV-0042"), 503), "ref"), "try again"), 1)
+	data, _ := ToJSON(WithField(err, "device", "42"))
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	checkSchemaKeys(t, m, schema["properties"].(map[string]interface{}))
+
+	schemas := OpenAPISchemas()
+	if _, ok := schemas["Error"].(map[string]interface{})["properties"].(map[string]interface{})["schema"]; !ok {
+		t.Error("OpenAPISchemas() Error has no schema property")
+	}
+	cause := schemas["ErrorCause"].(map[string]interface{})["properties"].(map[string]interface{})["cause"]
+	if !reflect.DeepEqual(cause, map[string]interface{}{"$ref": "#/components/schemas/ErrorCause"}) {
+		t.Errorf("OpenAPISchemas() cause = %v", cause)
+	}
+}
+
+func checkSchemaKeys(t *testing.T, m, properties map[string]interface{}) {
+	t.Helper()
+	for key, v := range m {
+		if _, ok := properties[key]; !ok {
+			t.Errorf("key %q is not documented", key)
+		}
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if key == "cause" {
+				checkSchemaKeys(t, v, properties)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if cause, ok := item.(map[string]interface{}); ok && key != "hops" {
+					checkSchemaKeys(t, cause, properties)
+				}
+			}
+		}
+	}
+}
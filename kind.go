@@ -0,0 +1,182 @@
+package errors
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// Kind classifies errors by the nature of the failure, independently of
+// their messages, so that callers can handle them without matching sentinel
+// errors of every package. A Kind is also an error, that Is matches with the
+// errors of that kind, see KindOf:
+//
+//	if errors.Is(err, errors.KindNotFound) {
+//		w.WriteHeader(http.StatusNotFound)
+//	}
+type Kind string
+
+// Kinds of errors. The zero Kind is KindUnknown.
+const (
+	KindUnknown          Kind = ""
+	KindInvalid          Kind = "invalid"
+	KindNotFound         Kind = "not_found"
+	KindAlreadyExists    Kind = "already_exists"
+	KindConflict         Kind = "conflict"
+	KindUnauthenticated  Kind = "unauthenticated"
+	KindPermissionDenied Kind = "permission_denied"
+	KindRateLimited      Kind = "rate_limited"
+	KindCanceled         Kind = "canceled"
+	KindTimeout          Kind = "timeout"
+	KindUnavailable      Kind = "unavailable"
+	KindInternal         Kind = "internal"
+)
+
+// String returns the name of k
+func (k Kind) String() string { return string(k) }
+
+// Error returns the name of k
+func (k Kind) Error() string { return string(k) }
+
+// kindStatuses maps kinds to HTTP statuses.
+var kindStatuses = map[Kind]int{
+	KindInvalid:          http.StatusBadRequest,
+	KindNotFound:         http.StatusNotFound,
+	KindAlreadyExists:    http.StatusConflict,
+	KindConflict:         http.StatusConflict,
+	KindUnauthenticated:  http.StatusUnauthorized,
+	KindPermissionDenied: http.StatusForbidden,
+	KindRateLimited:      http.StatusTooManyRequests,
+	KindCanceled:         499, // Client Closed Request
+	KindTimeout:          http.StatusGatewayTimeout,
+	KindUnavailable:      http.StatusServiceUnavailable,
+	KindInternal:         http.StatusInternalServerError,
+}
+
+// grpcStatuses maps gRPC codes, by value, to HTTP statuses.
+var grpcStatuses = [...]int{
+	1:  499, // Canceled
+	2:  http.StatusInternalServerError,
+	3:  http.StatusBadRequest,
+	4:  http.StatusGatewayTimeout,
+	5:  http.StatusNotFound,
+	6:  http.StatusConflict,
+	7:  http.StatusForbidden,
+	8:  http.StatusTooManyRequests,
+	9:  http.StatusBadRequest,
+	10: http.StatusConflict,
+	11: http.StatusBadRequest,
+	12: http.StatusNotImplemented,
+	13: http.StatusInternalServerError,
+	14: http.StatusServiceUnavailable,
+	15: http.StatusInternalServerError,
+	16: http.StatusUnauthorized,
+}
+
+// WithKind annotates err with the kind k, as returned by KindOf.
+// If err is nil, WithKind returns nil.
+func WithKind(err error, k Kind) error {
+	if err == nil {
+		return nil
+	}
+	return &withAttr{error: err, name: "kind", value: k}
+}
+
+// KindOf returns the kind of the outermost error of the chain of err
+// annotated with WithKind or decoded with a kind, or KindUnknown. It is the
+// only kind of the chain Is matches: an outer WithKind overrides the kinds
+// set deeper, which errors.Is of the standard library still matches.
+func KindOf(err error) Kind {
+	k, _ := lookupAttr(err, "kind")
+	kind, _ := k.(Kind)
+	return kind
+}
+
+// WithHTTPStatus annotates err with the HTTP status a server should respond
+// with, as returned by HTTPStatus.
+// If err is nil, WithHTTPStatus returns nil.
+func WithHTTPStatus(err error, status int) error {
+	if err == nil {
+		return nil
+	}
+	return &withAttr{error: err, name: "http_status", value: status}
+}
+
+// HTTPStatus returns the HTTP status corresponding to err. It is given by
+// the outermost error of the chain of err that has either a status set by
+// WithHTTPStatus, a kind set by WithKind, or a gRPC status (an error with a
+// GRPCStatus method, as in google.golang.org/grpc/status).
+func HTTPStatus(err error) (int, bool) {
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		if w, ok := err.(*withAttr); ok {
+			switch w.name {
+			case "http_status":
+				return w.value.(int), true
+			case "kind":
+				if status, ok := kindStatuses[w.value.(Kind)]; ok {
+					return status, true
+				}
+			}
+		}
+		if r, ok := err.(*RemoteError); ok {
+			if status, ok := kindStatuses[r.Kind]; ok {
+				return status, true
+			}
+		}
+		if code, ok := grpcCode(err); ok {
+			if code < uint64(len(grpcStatuses)) && grpcStatuses[code] != 0 {
+				return grpcStatuses[code], true
+			}
+		}
+		causes := causes(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return 0, false
+}
+
+// grpcCode returns the code of the gRPC status of err, without depending on
+// the gRPC packages: err must have a GRPCStatus method returning a value with
+// a Code method, that returns an unsigned integer.
+func grpcCode(err error) (uint64, bool) {
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return 0, false
+	}
+	m := v.MethodByName("GRPCStatus")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return 0, false
+	}
+	st := m.Call(nil)[0]
+	if st.Kind() == reflect.Ptr && st.IsNil() {
+		return 0, false
+	}
+	code := st.MethodByName("Code")
+	if !code.IsValid() || code.Type().NumIn() != 0 || code.Type().NumOut() != 1 {
+		return 0, false
+	}
+	switch c := code.Call(nil)[0]; c.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return c.Uint(), true
+	}
+	return 0, false
+}
+
+// IsClientError reports whether err is caused by the client of the
+// operation that failed, such as invalid arguments or missing permissions,
+// according to HTTPStatus: circuit breakers and SLO counters should not
+// count it against the service.
+func IsClientError(err error) bool {
+	status, ok := HTTPStatus(err)
+	return ok && status >= 400 && status < 500
+}
+
+// IsServerError reports whether err is caused by a failure of the service
+// itself, according to HTTPStatus: circuit breakers and SLO counters should
+// count it against the service.
+func IsServerError(err error) bool {
+	status, ok := HTTPStatus(err)
+	return ok && status >= 500 && status < 600
+}
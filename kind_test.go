@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+type grpcStatus struct{ code uint32 }
+
+func (s *grpcStatus) Code() uint32 { return s.code }
+
+type grpcError struct{ code uint32 }
+
+func (e grpcError) Error() string           { return "rpc error" }
+func (e grpcError) GRPCStatus() *grpcStatus { return &grpcStatus{e.code} }
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Kind
+	}{
+		{nil, KindUnknown},
+		{io.EOF, KindUnknown},
+		{WithKind(io.EOF, KindNotFound), KindNotFound},
+		{Wrap(WithKind(io.EOF, KindNotFound), "read"), KindNotFound},
+		{WithKind(WithKind(io.EOF, KindNotFound), KindInternal), KindInternal},
+	}
+	for i, tt := range tests {
+		if got := KindOf(tt.err); got != tt.want {
+			t.Errorf("test %d: KindOf(%v): got %q, want %q", i+1, tt.err, got, tt.want)
+		}
+	}
+	if got := WithKind(nil, KindNotFound); got != nil {
+		t.Errorf("WithKind(nil): got %#v, want nil", got)
+	}
+	if got := ToMap(WithKind(io.EOF, KindNotFound))["kind"]; got != "not_found" {
+		t.Errorf("ToMap: got kind %#v, want \"not_found\"", got)
+	}
+}
+
+func TestClientServerError(t *testing.T) {
+	tests := []struct {
+		err            error
+		status         int
+		client, server bool
+	}{
+		{nil, 0, false, false},
+		{io.EOF, 0, false, false},
+		{WithKind(io.EOF, KindUnknown), 0, false, false},
+		{WithKind(io.EOF, KindNotFound), http.StatusNotFound, true, false},
+		{Wrap(WithKind(io.EOF, KindTimeout), "call"), http.StatusGatewayTimeout, false, true},
+		{WithHTTPStatus(io.EOF, http.StatusTeapot), http.StatusTeapot, true, false},
+		{WithHTTPStatus(WithKind(io.EOF, KindInvalid), http.StatusBadGateway), http.StatusBadGateway, false, true},
+		{WithKind(WithHTTPStatus(io.EOF, http.StatusBadGateway), KindInvalid), http.StatusBadRequest, true, false},
+		{Wrap(grpcError{5}, "get"), http.StatusNotFound, true, false},
+		{grpcError{14}, http.StatusServiceUnavailable, false, true},
+		{grpcError{0}, 0, false, false},
+		{grpcError{42}, 0, false, false},
+	}
+	for i, tt := range tests {
+		status, _ := HTTPStatus(tt.err)
+		if status != tt.status {
+			t.Errorf("test %d: HTTPStatus(%v): got %d, want %d", i+1, tt.err, status, tt.status)
+		}
+		if got := IsClientError(tt.err); got != tt.client {
+			t.Errorf("test %d: IsClientError(%v): got %t, want %t", i+1, tt.err, got, tt.client)
+		}
+		if got := IsServerError(tt.err); got != tt.server {
+			t.Errorf("test %d: IsServerError(%v): got %t, want %t", i+1, tt.err, got, tt.server)
+		}
+	}
+}
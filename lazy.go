@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Errorlazy is like Errorf, but defers formatting the message until the
+// error is formatted or its Error method is called. It is intended for hot
+// paths where most errors are discarded after an Is check.
+// Errorlazy also records the stack trace at the point it was called.
+//
+// The arguments are retained until the message is rendered, so they must not
+// be modified afterwards. The %w verb is not supported, use Errorf instead.
+func Errorlazy(format string, args ...interface{}) error {
+	return &withStack{
+		error:   &lazyError{msg: lazyMessage{format: format, args: args}},
+		stack:   callers(),
+		created: timestamp(),
+	}
+}
+
+// Wraplazy is like Wrapf, but defers formatting the message until the
+// error is formatted or its Error method is called.
+// If err is nil, Wraplazy returns nil.
+//
+// The arguments are retained until the message is rendered, so they must not
+// be modified afterwards.
+func Wraplazy(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &withStack{
+		error:   err,
+		stack:   callers(),
+		created: timestamp(),
+		lazy:    &lazyMessage{format: format, args: args},
+		wrapped: true,
+	}
+}
+
+// lazyMessage is a message formatted on first use.
+type lazyMessage struct {
+	format string
+	args   []interface{}
+
+	once sync.Once
+	msg  string
+}
+
+func (m *lazyMessage) String() string {
+	m.once.Do(func() {
+		m.msg = fmt.Sprintf(m.format, m.args...)
+		m.args = nil
+	})
+	return m.msg
+}
+
+// lazyError is the root error created by Errorlazy.
+type lazyError struct {
+	msg lazyMessage
+}
+
+func (e *lazyError) Error() string {
+	return e.msg.String()
+}
@@ -0,0 +1,91 @@
+package errors
+
+import (
+	goerrors "errors"
+	"io"
+	"testing"
+)
+
+type countingStringer struct{ calls int }
+
+func (c *countingStringer) String() string {
+	c.calls++
+	return "arg"
+}
+
+func TestErrorlazy(t *testing.T) {
+	arg := &countingStringer{}
+	err := Errorlazy("lazy %s %d", arg, 1)
+	if arg.calls != 0 {
+		t.Fatalf("Errorlazy formatted its message eagerly")
+	}
+	for i := 0; i < 2; i++ {
+		if got, want := err.Error(), "lazy arg 1"; got != want {
+			t.Errorf("Errorlazy.Error(): got %q, want %q", got, want)
+		}
+	}
+	if arg.calls != 1 {
+		t.Errorf("message formatted %d times, want 1", arg.calls)
+	}
+}
+
+func TestWraplazy(t *testing.T) {
+	if got := Wraplazy(nil, "no error"); got != nil {
+		t.Errorf("Wraplazy(nil, \"no error\"): got %#v, expected nil", got)
+	}
+
+	arg := &countingStringer{}
+	err := Wraplazy(io.EOF, "read %s", arg)
+	if !goerrors.Is(err, io.EOF) {
+		t.Errorf("Wraplazy: errors.Is(err, io.EOF) = false")
+	}
+	if Cause(err) != io.EOF {
+		t.Errorf("Wraplazy: Cause(err) = %v, want io.EOF", Cause(err))
+	}
+	if arg.calls != 0 {
+		t.Fatalf("Wraplazy formatted its message before being printed")
+	}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err, "read arg: EOF"},
+		{Wraplazy(Wrapf(io.EOF, "read error with %d format specifier", 1), "client error"), "client error: read error with 1 format specifier: EOF"},
+		{Wrap(Wraplazy(io.EOF, "read %s", "error"), "client error"), "client error: read error: EOF"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Wraplazy: got: %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestFormatWraplazy(t *testing.T) {
+	tests := []struct {
+		error
+		format string
+		want   string
+	}{{
+		Wraplazy(io.EOF, "error%d", 2),
+		"%s",
+		"error2: EOF",
+	}, {
+		Wraplazy(io.EOF, "error%d", 2),
+		"%+v",
+		"EOF\n" +
+			"error2\n" +
+			"github.com/objenious/errors.TestFormatWraplazy\n" +
+			"\t.+/github.com/objenious/errors/lazy_test.go:74",
+	}, {
+		Errorlazy("error%d", 1),
+		"%+v",
+		"error1\n" +
+			"github.com/objenious/errors.TestFormatWraplazy\n" +
+			"\t.+/github.com/objenious/errors/lazy_test.go:81",
+	}}
+
+	for i, tt := range tests {
+		testFormatRegexp(t, i, tt.error, tt.format, tt.want)
+	}
+}
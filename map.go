@@ -0,0 +1,130 @@
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+// ToMap returns a representation of the chain of err suitable for logging
+// adapters, JSON encoding or templates:
+//
+//	{
+//		"message": "read config: open config.json: no such file or directory",
+//		"stack":   ["main.readConfig /src/main.go:12", ...],
+//		"cause":   {"message": "open config.json: no such file or directory"},
+//	}
+//
+// "stack" is only present for levels carrying a stack trace, "time" for
+// levels created while timestamps were recorded (see SetTimestamps), and errors
+// wrapping several errors have a "causes" list instead of a "cause". Errors
+// returned by ErrorsAndWarnings.Err have a "warnings" list, and errors
+// annotated by WithField a "fields" map. The outermost level has a "hops"
+// list when the error went through services, see SetServiceName.
+// If err is nil, ToMap returns nil.
+func ToMap(err error) map[string]interface{} {
+	return addHops(toMap(acyclic(err), Frame.text), err)
+}
+
+// toMap builds the representation of ToMap, formatting frames with
+// frameText in the mode of their error.
+func toMap(err error, frameText func(Frame, modeSettings) string) map[string]interface{} {
+	if err == nil {
+		return nil
+	}
+	if f, ok := err.(*frozenError); ok {
+		return copyValue(f.m).(map[string]interface{})
+	}
+	if _, ok := err.(*withAttr); ok {
+		// attributes are listed with the error they annotate: the outermost
+		// value of an attribute wins, while fields follow their merge policy.
+		var attrs []*withAttr
+		for w, ok := err.(*withAttr); ok; w, ok = err.(*withAttr) {
+			attrs = append(attrs, w)
+			err = w.error
+		}
+		m := toMap(err, frameText)
+		var fields map[string][]interface{}
+		for _, w := range attrs {
+			if f, ok := w.value.(field); ok {
+				if fields == nil {
+					fields = make(map[string][]interface{})
+				}
+				fields[f.key] = append(fields[f.key], attrText(f.value))
+			} else if _, ok := w.value.(value); ok {
+				continue
+			} else if _, ok := m[w.name]; !ok {
+				m[w.name] = attrText(w.value)
+			}
+		}
+		if fields != nil {
+			merged := make(map[string]interface{}, len(fields))
+			for key, values := range fields {
+				merged[key] = mergeField(values)
+			}
+			m["fields"] = merged
+		}
+		return m
+	}
+	m := map[string]interface{}{
+		"message": err.Error(),
+	}
+	if w, ok := err.(*withStack); ok {
+		if frames := w.stackText(frameText); frames != nil {
+			m["stack"] = frames
+		}
+		if !w.created.IsZero() {
+			m["time"] = w.created.Format(time.RFC3339Nano)
+		}
+	} else if r, ok := err.(*RemoteError); ok {
+		if len(r.Stack) > 0 {
+			m["stack"] = r.Stack
+		}
+		if !r.Time.IsZero() {
+			m["time"] = r.Time.Format(time.RFC3339Nano)
+		}
+		if r.Kind != KindUnknown {
+			m["kind"] = string(r.Kind)
+		}
+		if r.Code != "" {
+			m["code"] = string(r.Code)
+		}
+		if r.RefID != "" {
+			m["ref_id"] = r.RefID
+		}
+		if r.Fingerprint != "" {
+			m["fingerprint"] = r.Fingerprint
+		}
+	}
+	switch causes := causes(err); len(causes) {
+	case 0:
+	case 1:
+		m["cause"] = toMap(causes[0], frameText)
+	default:
+		list := make([]interface{}, len(causes))
+		for i, cause := range causes {
+			list[i] = toMap(cause, frameText)
+		}
+		m["causes"] = list
+	}
+	if w, ok := err.(*withWarnings); ok && len(w.warnings) > 0 {
+		list := make([]interface{}, len(w.warnings))
+		for i, warning := range w.warnings {
+			list[i] = toMap(warning, frameText)
+		}
+		m["warnings"] = list
+	}
+	return m
+}
+
+// attrText returns the representation of an attribute value in ToMap.
+func attrText(v interface{}) interface{} {
+	switch v := v.(type) {
+	case fmt.Stringer:
+		return v.String()
+	case Exchange:
+		return v.mapValue()
+	case RateLimitInfo:
+		return v.mapValue()
+	}
+	return v
+}
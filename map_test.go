@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestToMap(t *testing.T) {
+	if got := ToMap(nil); got != nil {
+		t.Errorf("ToMap(nil): got %v, want nil", got)
+	}
+	if got, want := ToMap(io.EOF), map[string]interface{}{"message": "EOF"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ToMap(io.EOF): got %v, want %v", got, want)
+	}
+
+	m := ToMap(Wrap(fmt.Errorf("decode: %w", New("bad payload")), "uplink"))
+	if got, want := m["message"], "uplink: decode: bad payload"; got != want {
+		t.Errorf("message: got %q, want %q", got, want)
+	}
+	frameR := regexp.MustCompile(`^github.com/objenious/errors\.TestToMap .+/github.com/objenious/errors/map_test.go:20$`)
+	if st, _ := m["stack"].([]string); len(st) == 0 || !frameR.MatchString(st[0]) {
+		t.Errorf("stack: got %q, want a frame matching %s", m["stack"], frameR)
+	}
+	cause, _ := m["cause"].(map[string]interface{})
+	if _, ok := cause["stack"]; ok || cause["message"] != "decode: bad payload" {
+		t.Errorf("cause: got %v, want the fmt.Errorf error without stack", cause)
+	}
+	root, _ := cause["cause"].(map[string]interface{})
+	if st, _ := root["stack"].([]string); root["message"] != "bad payload" || len(st) == 0 || root["cause"] != nil {
+		t.Errorf("root cause: got %v, want the New error with its stack", root)
+	}
+
+	if _, err := json.Marshal(m); err != nil {
+		t.Errorf("json.Marshal(ToMap(err)): %v", err)
+	}
+}
+
+func TestToMapMultiple(t *testing.T) {
+	m := ToMap(Errorf("%w, %w", io.EOF, io.ErrUnexpectedEOF))
+	want := []interface{}{
+		map[string]interface{}{"message": "EOF"},
+		map[string]interface{}{"message": "unexpected EOF"},
+	}
+	if got := m["causes"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("causes: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ToMarkdown returns a report of err in Markdown, to be pasted in an issue
+// or a ticket: the message of err as a title, the chain of its messages, a
+// table of its fields and attributes, and the deepest stack trace of the
+// chain in a code block. If err is nil, ToMarkdown returns an empty string.
+func ToMarkdown(err error) string {
+	if err == nil {
+		return ""
+	}
+	m := ToMap(err)
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s\n\n", markdownText(err.Error()))
+	b.WriteString("#### Chain\n\n")
+	markdownChain(&b, m, 0)
+	if attrs := markdownAttrs(m); len(attrs) > 0 {
+		b.WriteString("\n#### Fields\n\n| Key | Value |\n| --- | --- |\n")
+		keys := make([]string, 0, len(attrs))
+		for key := range attrs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&b, "| %s | %s |\n", markdownText(key), markdownText(fmt.Sprint(attrs[key])))
+		}
+	}
+	if stack := DeepestStack(err); len(stack) > 0 {
+		b.WriteString("\n#### Stack trace\n\n```\n")
+		for _, f := range stack {
+			b.WriteString(f)
+			b.WriteString("\n")
+		}
+		b.WriteString("```\n")
+	}
+	if warnings, ok := m["warnings"].([]interface{}); ok {
+		b.WriteString("\n#### Warnings\n\n")
+		for _, w := range warnings {
+			fmt.Fprintf(&b, "- %s\n", markdownText(w.(map[string]interface{})["message"].(string)))
+		}
+	}
+	return b.String()
+}
+
+// markdownChain writes the messages of the chain of the representation m of
+// an error as a list, indenting the causes of errors wrapping several errors.
+func markdownChain(b *strings.Builder, m map[string]interface{}, indent int) {
+	for m != nil {
+		fmt.Fprintf(b, "%s- %s\n", strings.Repeat("  ", indent), markdownText(m["message"].(string)))
+		if causes, ok := m["causes"].([]interface{}); ok {
+			for _, cause := range causes {
+				markdownChain(b, cause.(map[string]interface{}), indent+1)
+			}
+		}
+		m, _ = m["cause"].(map[string]interface{})
+	}
+}
+
+// markdownAttrs returns the fields and attributes of the chain of the
+// representation m of an error, the outermost value of each winning.
+func markdownAttrs(m map[string]interface{}) map[string]interface{} {
+	attrs := make(map[string]interface{})
+	for ; m != nil; m, _ = m["cause"].(map[string]interface{}) {
+		for key, value := range m {
+			switch key {
+			case "message", "stack", "time", "cause", "causes", "warnings", "hops":
+				continue
+			case "fields":
+				for key, value := range value.(map[string]interface{}) {
+					if _, ok := attrs[key]; !ok {
+						attrs[key] = value
+					}
+				}
+				continue
+			}
+			if _, ok := attrs[key]; !ok {
+				attrs[key] = value
+			}
+		}
+	}
+	return attrs
+}
+
+// markdownEscaper escapes the characters with a meaning in Markdown.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`<`, `\<`, `>`, `\>`, `|`, `\|`, `#`, `\#`,
+)
+
+// markdownText returns s as Markdown text on a single line, its lines being
+// separated by semicolons.
+func markdownText(s string) string {
+	return markdownEscaper.Replace(strings.Replace(s, "\n", "; ", -1))
+}
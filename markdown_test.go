@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestToMarkdown(t *testing.T) {
+	if got := ToMarkdown(nil); got != "" {
+		t.Errorf("ToMarkdown(nil) = %q, want empty", got)
+	}
+
+	err := WithField(Wrap(WithKind(New("no *such* file"), KindNotFound), "read config"), "path", "a|b")
+	got := ToMarkdown(err)
+	want := "### read config: no \\*such\\* file\n" +
+		"\n" +
+		"#### Chain\n" +
+		"\n" +
+		"- read config: no \\*such\\* file\n" +
+		"- no \\*such\\* file\n" +
+		"\n" +
+		"#### Fields\n" +
+		"\n" +
+		"| Key | Value |\n" +
+		"| --- | --- |\n" +
+		"| kind | not\\_found |\n" +
+		"| path | a\\|b |\n" +
+		"\n" +
+		"#### Stack trace\n" +
+		"\n" +
+		"```\n" +
+		"github.com/objenious/errors.TestToMarkdown "
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("ToMarkdown:\n got: %s\nwant: %s...", got, want)
+	}
+	if !strings.HasSuffix(got, "\n```\n") {
+		t.Errorf("ToMarkdown does not end with the stack trace:\n%s", got)
+	}
+}
+
+func TestToMarkdownJoin(t *testing.T) {
+	got := ToMarkdown(Wrap(Join(io.EOF, io.ErrUnexpectedEOF), "read"))
+	want := "### read: EOF; unexpected EOF\n" +
+		"\n" +
+		"#### Chain\n" +
+		"\n" +
+		"- read: EOF; unexpected EOF\n" +
+		"- EOF; unexpected EOF\n" +
+		"  - EOF\n" +
+		"  - unexpected EOF\n" +
+		"\n" +
+		"#### Stack trace\n"
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("ToMarkdown:\n got: %s\nwant: %s...", got, want)
+	}
+}
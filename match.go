@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MatchMessage reports whether a message of the chain of err, as returned by
+// Messages but following every cause, matches pattern, for the code that must
+// branch on the texts of the errors of other packages:
+//
+//	if errors.MatchMessage(err, "*connection reset by peer") {
+//		// retry
+//	}
+//
+// A pattern enclosed in slashes, such as "/^pq: .*deadlock/", is a regular
+// expression matching any part of a message. Other patterns are globs,
+// matching whole messages, where '*' matches any sequence of characters, '?'
+// any character, and '\' escapes the next character. An invalid regular
+// expression matches nothing.
+func MatchMessage(err error, pattern string) bool {
+	re, e := compileMessagePattern(pattern)
+	if e != nil {
+		return false
+	}
+	return matchMessage(acyclic(err), re)
+}
+
+// matchMessage reports whether a message of the tree of err matches re.
+func matchMessage(err error, re *regexp.Regexp) bool {
+	if err == nil {
+		return false
+	}
+	causes := causes(err)
+	if msg := levelMessage(err, causes); msg != "" && re.MatchString(msg) {
+		return true
+	}
+	for _, c := range causes {
+		if matchMessage(c, re) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileMessagePattern returns the regular expression of a pattern of
+// MatchMessage.
+func compileMessagePattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) >= 2 && pattern[0] == '/' && pattern[len(pattern)-1] == '/' {
+		return regexp.Compile(pattern[1 : len(pattern)-1])
+	}
+	var b strings.Builder
+	b.WriteString("^(?s:")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '*':
+			b.WriteString(".*")
+		case r == '?':
+			b.WriteString(".")
+		case r == '\\':
+			escaped = true
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	if escaped {
+		b.WriteString(regexp.QuoteMeta("\\"))
+	}
+	b.WriteString(")$")
+	return regexp.Compile(b.String())
+}
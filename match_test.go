@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestMatchMessage(t *testing.T) {
+	err := Wrap(fmt.Errorf("read tcp 10.0.0.1:5432: %w", io.ErrUnexpectedEOF), "query users")
+	joined := Join(New("pq: deadlock detected"), WithField(New("a*b?"), "id", 1))
+	tests := []struct {
+		err     error
+		pattern string
+		want    bool
+	}{
+		{nil, "*", false},
+		{err, "query users", true},
+		{err, "query*", true},
+		{err, "read tcp *", true},
+		{err, "unexpected EOF", true},
+		{err, "unexpected ???", true},
+		{err, "query users: read*", false},
+		{err, "read", false},
+		{err, "/tcp [0-9.]+:5432/", true},
+		{err, "/^tcp/", false},
+		{err, "/[/", false},
+		{joined, "/^pq: .* deadlock/", false},
+		{joined, "/^pq: deadlock/", true},
+		{joined, `a\*b\?`, true},
+		{joined, `a\*b\?*`, true},
+		{joined, `a\*c`, false},
+		{joined, `*\`, false},
+		{New("échec de connexion"), "échec*", true},
+		{New("échec de connexion"), "?chec*", true},
+		{New("délai dépassé"), `d\élai*`, true},
+	}
+	for _, tt := range tests {
+		if got := MatchMessage(tt.err, tt.pattern); got != tt.want {
+			t.Errorf("MatchMessage(%v, %q) = %v, want %v", tt.err, tt.pattern, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package errors
+
+import "strings"
+
+// Messages returns the messages added by the levels of the chain of err,
+// from the outermost to the innermost, following the first cause of each
+// level, for breadcrumb-style displays:
+//
+//	Messages(Wrap(fmt.Errorf("read config: %w", os.ErrNotExist), "load"))
+//	// ["load", "read config", "file does not exist"]
+//
+// The message of each level is stripped of the message of its cause, so that
+// messages composed with fmt.Errorf are not repeated. Levels that add no
+// message, such as those of WithStack or WithField, are skipped.
+// If err is nil, Messages returns nil.
+func Messages(err error) []string {
+	var msgs []string
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		causes := causes(err)
+		if msg := levelMessage(err, causes); msg != "" {
+			msgs = append(msgs, msg)
+		}
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return msgs
+}
+
+// levelMessage returns the message added by err to its causes.
+func levelMessage(err error, causes []error) string {
+	switch e := err.(type) {
+	case *withAttr:
+		return ""
+	case *withStack:
+		if e.wrapped {
+			return e.message()
+		}
+		return ownMessage(e.Error(), causes)
+	}
+	return ownMessage(err.Error(), causes)
+}
+
+// ownMessage returns the message msg of an error stripped of the messages of
+// its causes.
+func ownMessage(msg string, causes []error) string {
+	switch len(causes) {
+	case 0:
+		return msg
+	case 1:
+		cause := causes[0].Error()
+		if strings.HasSuffix(msg, cause) {
+			return strings.TrimRight(strings.TrimSuffix(msg, cause), ": ")
+		}
+	default:
+		msgs := make([]string, len(causes))
+		for i, c := range causes {
+			msgs[i] = c.Error()
+		}
+		if msg == strings.Join(msgs, "\n") {
+			return ""
+		}
+	}
+	return msg
+}
@@ -0,0 +1,31 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestMessages(t *testing.T) {
+	tests := []struct {
+		err  error
+		want []string
+	}{
+		{nil, nil},
+		{io.EOF, []string{"EOF"}},
+		{New("boom"), []string{"boom"}},
+		{Wrap(fmt.Errorf("read config: %w", os.ErrNotExist), "load"), []string{"load", "read config", "file does not exist"}},
+		{Errorf("read config: %w", WithStack(io.EOF)), []string{"read config", "EOF"}},
+		{WithField(WithStack(Wrapf(io.EOF, "read %d", 1)), "key", 1), []string{"read 1", "EOF"}},
+		{fmt.Errorf("read failed (%w)", io.EOF), []string{"read failed (EOF)", "EOF"}},
+		{Wrap(Join(io.EOF, io.ErrShortWrite), "batch"), []string{"batch", "EOF"}},
+		{&RemoteError{Message: "call: boom", Causes: []error{&RemoteError{Message: "boom"}}}, []string{"call", "boom"}},
+	}
+	for _, tt := range tests {
+		if got := Messages(tt.err); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Messages(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package errors
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Mode is a preset of the settings printing and serializing stack traces.
+type Mode int
+
+// Modes of SetMode.
+const (
+	// DefaultMode prints and serializes stack traces as they are.
+	DefaultMode Mode = iota
+	// Development prints full stack traces with their function names in
+	// color, and each frame followed by its line of source code when the
+	// source is available.
+	Development
+	// Production trims the paths of source files to the path of their
+	// package, redacts the frames of the packages outside the main module
+	// (the standard library and the dependencies, the runtime included)
+	// when the main module is known, and keeps at most 10 frames per stack
+	// trace, also in ToMap and its encodings.
+	Production
+)
+
+// maxProductionFrames is the number of frames kept in Production mode.
+const maxProductionFrames = 10
+
+// ANSI escape sequences of the function names in Development mode.
+const (
+	colorFunc  = "\x1b[36m"
+	colorReset = "\x1b[0m"
+)
+
+type modeSettings struct {
+	color       bool
+	sources     bool
+	trimPaths   bool
+	dropRuntime bool
+	mainOnly    bool
+	maxFrames   int
+}
+
+// modeConfig holds the modeSettings set by SetMode.
+var modeConfig atomic.Value
+
+// SetMode sets the preset for printing and serializing stack traces, so that
+// programs get sensible defaults without setting every option: Development
+// for readable traces on a terminal, Production for compact traces that do
+// not reveal the paths of the build. It is DefaultMode by default. The frame
+// redactor set by SetFrameRedactor applies in every mode.
+func SetMode(m Mode) {
+	var settings modeSettings
+	switch m {
+	case Development:
+		settings = modeSettings{color: true, sources: true}
+	case Production:
+		settings = modeSettings{trimPaths: true, dropRuntime: true, mainOnly: mainModule != "", maxFrames: maxProductionFrames}
+	}
+	modeConfig.Store(settings)
+}
+
+// mainModule is the path of the main module of the program, if known.
+var mainModule = func() string {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		return bi.Main.Path
+	}
+	return ""
+}()
+
+// inMainModule reports whether the function of f is in a package of the main
+// module, or in package main.
+func inMainModule(f Frame) bool {
+	pkg := f.Package()
+	return pkg == "main" || pkg == mainModule || strings.HasPrefix(pkg, mainModule+"/")
+}
+
+func currentMode() modeSettings {
+	m, _ := modeConfig.Load().(modeSettings)
+	return m
+}
+
+// displayFile returns the path of the source file of f to print in mode m:
+// <package path>/<file name> when paths are trimmed.
+func (f Frame) displayFile(m modeSettings) string {
+	file := f.file()
+	if !m.trimPaths || file == "unknown" {
+		return file
+	}
+	return pkgname(f.name()) + "/" + path.Base(file)
+}
+
+// sources caches the lines of the source files printed in Development mode.
+var sources sync.Map
+
+// sourceLine returns the line number line of file, without indentation.
+func sourceLine(file string, line int) (string, bool) {
+	lines, ok := sources.Load(file)
+	if !ok {
+		lines, _ = sources.LoadOrStore(file, readLines(file))
+	}
+	l := lines.([]string)
+	if line < 1 || line > len(l) {
+		return "", false
+	}
+	return strings.TrimSpace(l[line-1]), true
+}
+
+// readLines returns the lines of file, or nil if it can't be read.
+func readLines(file string) []string {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func deepError(n int) error {
+	if n == 0 {
+		return New("deep")
+	}
+	return deepError(n - 1)
+}
+
+func TestSetMode(t *testing.T) {
+	defer SetMode(DefaultMode)
+	err := New("boom")
+
+	SetMode(Development)
+	got := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(got, "boom\n"+colorFunc+"github.com/objenious/errors.TestSetMode"+colorReset+"\n\t") {
+		t.Errorf("Development: got %q, want a function name in color", got)
+	}
+	if !strings.Contains(got, "\n\t\terr := New(\"boom\")\n") {
+		t.Errorf("Development: got %q, want the source line", got)
+	}
+
+	SetMode(Production)
+	got = fmt.Sprintf("%+v", deepError(20))
+	if !strings.HasPrefix(got, "deep\ngithub.com/objenious/errors.deepError\n\tgithub.com/objenious/errors/mode_test.go:") {
+		t.Errorf("Production: got %q, want trimmed paths", got)
+	}
+	if strings.Contains(got, "runtime.") || strings.Count(got, "\n\t") != maxProductionFrames {
+		t.Errorf("Production: got %q, want %d frames without runtime", got, maxProductionFrames)
+	}
+	if got := StackText(err); len(got) != 1 || !strings.HasPrefix(got[0], "github.com/objenious/errors.TestSetMode ") {
+		t.Errorf("Production: got frames %q, want the frames of the main module", got)
+	}
+	data, _ := json.Marshal(ToMap(err))
+	if !strings.Contains(string(data), `"github.com/objenious/errors.TestSetMode github.com/objenious/errors/mode_test.go:`) || strings.Contains(string(data), "runtime.") {
+		t.Errorf("Production: got ToMap %s, want trimmed paths", data)
+	}
+
+	SetMode(DefaultMode)
+	if got := fmt.Sprintf("%+v", err); strings.Contains(got, colorFunc) || !strings.Contains(got, "runtime.goexit") {
+		t.Errorf("DefaultMode: got %q", got)
+	}
+}
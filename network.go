@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	goerrors "errors"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// IsDNSError reports whether err is caused by a failure to resolve a host
+// name, a *net.DNSError.
+func IsDNSError(err error) bool {
+	var dnsErr *net.DNSError
+	return As(err, &dnsErr)
+}
+
+// IsTLSError reports whether err is caused by a failure of a TLS handshake or
+// of the verification of a certificate.
+func IsTLSError(err error) bool {
+	var (
+		header    tls.RecordHeaderError
+		authority x509.UnknownAuthorityError
+		invalid   x509.CertificateInvalidError
+		hostname  x509.HostnameError
+	)
+	if As(err, &header) || As(err, &authority) ||
+		As(err, &invalid) || As(err, &hostname) {
+		return true
+	}
+	// the other errors of crypto/tls are only recognized by their message.
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		if strings.HasPrefix(err.Error(), "tls: ") {
+			return true
+		}
+		err = goerrors.Unwrap(err)
+	}
+	return false
+}
+
+// IsConnRefused reports whether err is caused by a connection refused by the
+// remote host, whatever the operating system.
+func IsConnRefused(err error) bool {
+	return isErrno(err, connRefusedErrnos)
+}
+
+// IsConnReset reports whether err is caused by a connection reset by the
+// remote host, whatever the operating system.
+func IsConnReset(err error) bool {
+	return isErrno(err, connResetErrnos)
+}
+
+// isErrno reports whether err matches any of errnos.
+func isErrno(err error, errnos []syscall.Errno) bool {
+	for _, errno := range errnos {
+		if Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
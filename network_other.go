@@ -0,0 +1,11 @@
+//go:build !windows
+// +build !windows
+
+package errors
+
+import "syscall"
+
+var (
+	connRefusedErrnos = []syscall.Errno{syscall.ECONNREFUSED}
+	connResetErrnos   = []syscall.Errno{syscall.ECONNRESET}
+)
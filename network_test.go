@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestNetworkClassifiers(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	_ = lis.Close()
+	_, refused := net.Dial("tcp", addr)
+
+	dnsErr := &net.DNSError{Err: "no such host", Name: "devices.invalid", IsNotFound: true}
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	tlsErr := fmt.Errorf("get devices: %w", x509.UnknownAuthorityError{})
+	alert := &net.OpError{Op: "remote error", Err: New("tls: bad certificate")}
+
+	tests := []struct {
+		name                        string
+		err                         error
+		dns, tls, refused, resetErr bool
+	}{
+		{"refused", Wrap(refused, "dial"), false, false, true, false},
+		{"reset", Wrap(reset, "read"), false, false, false, true},
+		{"dns", Wrap(dnsErr, "dial"), true, false, false, false},
+		{"tls", Wrap(tlsErr, "call"), false, true, false, false},
+		{"tls alert", alert, false, true, false, false},
+		{"other", Wrap(io.EOF, "read"), false, false, false, false},
+		{"nil", nil, false, false, false, false},
+	}
+	for _, tt := range tests {
+		if got := IsDNSError(tt.err); got != tt.dns {
+			t.Errorf("%s: IsDNSError() = %t, want %t", tt.name, got, tt.dns)
+		}
+		if got := IsTLSError(tt.err); got != tt.tls {
+			t.Errorf("%s: IsTLSError() = %t, want %t", tt.name, got, tt.tls)
+		}
+		if got := IsConnRefused(tt.err); got != tt.refused {
+			t.Errorf("%s: IsConnRefused() = %t, want %t", tt.name, got, tt.refused)
+		}
+		if got := IsConnReset(tt.err); got != tt.resetErr {
+			t.Errorf("%s: IsConnReset() = %t, want %t", tt.name, got, tt.resetErr)
+		}
+	}
+	if got := InferKind(reset); got != KindUnavailable {
+		t.Errorf("InferKind(reset) = %q, want %q", got, KindUnavailable)
+	}
+}
@@ -0,0 +1,14 @@
+package errors
+
+import "syscall"
+
+// The Windows Sockets error codes, that syscall does not define.
+const (
+	wsaeconnreset   syscall.Errno = 10054
+	wsaeconnrefused syscall.Errno = 10061
+)
+
+var (
+	connRefusedErrnos = []syscall.Errno{syscall.ECONNREFUSED, wsaeconnrefused}
+	connResetErrnos   = []syscall.Errno{syscall.ECONNRESET, wsaeconnreset}
+)
@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// IsNil reports whether err is nil, or a typed nil: an error interface
+// holding a nil pointer, map, slice, function or channel, which compares
+// unequal to nil. Typed nils are usually returned by functions whose result
+// is a variable of a concrete error type, as in
+//
+//	func check() error {
+//		var err *MyError
+//		return err // err != nil
+//	}
+func IsNil(err error) bool {
+	if err == nil {
+		return true
+	}
+	v := reflect.ValueOf(err)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// typedNilDetection holds the hook set by SetTypedNilDetection.
+var typedNilDetection atomic.Value
+
+type typedNilHook struct {
+	f func(err error, site Frame)
+}
+
+// SetTypedNilDetection enables the detection of typed nils (see IsNil)
+// wrapped by Wrap, Wrapf or WithStack, or the functions like them such as the
+// methods of Factory, which return an error wrapping them
+// instead of nil: hook is called with the new error and the call site. The
+// error message of typed nils often panics, hooks should not format the
+// error. A nil hook disables the detection, as by default.
+func SetTypedNilDetection(hook func(err error, site Frame)) {
+	typedNilDetection.Store(typedNilHook{hook})
+}
+
+// checkNil calls the hook set by SetTypedNilDetection, and reports a
+// TypedNilCause misuse, if w wraps a typed nil.
+func checkNil(w *withStack) {
+	h, _ := typedNilDetection.Load().(typedNilHook)
+	if h.f == nil && !diagnosing() || !IsNil(w.error) {
+		return
+	}
+	site := wrapSite(w)
+	if h.f != nil {
+		h.f(w, site)
+	}
+	diagnose(Misuse{Kind: TypedNilCause, Err: w, Site: site})
+}
@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+type typedNilError struct{}
+
+func (e *typedNilError) Error() string { return "nil error" }
+
+func typedNil() error {
+	var err *typedNilError
+	return err
+}
+
+func TestIsNil(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, true},
+		{typedNil(), true},
+		{io.EOF, false},
+		{&typedNilError{}, false},
+		{StringError("value"), false},
+	}
+	for _, tt := range tests {
+		if got := IsNil(tt.err); got != tt.want {
+			t.Errorf("IsNil(%#v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestSetTypedNilDetection(t *testing.T) {
+	defer SetTypedNilDetection(nil)
+	var sites []string
+	SetTypedNilDetection(func(err error, site Frame) {
+		if _, ok := err.(*withStack); !ok {
+			t.Errorf("hook called with %T", err)
+		}
+		sites = append(sites, fmt.Sprintf("%n", site))
+	})
+	_ = Wrap(typedNil(), "wrap")
+	_ = WithStack(typedNil())
+	_ = Wrap(io.EOF, "wrap")
+	if len(sites) != 2 || sites[0] != "TestSetTypedNilDetection" {
+		t.Errorf("hook: got sites %q, want TestSetTypedNilDetection twice", sites)
+	}
+
+	SetTypedNilDetection(nil)
+	_ = Wrapf(typedNil(), "wrap")
+	if len(sites) != 2 {
+		t.Errorf("disabled: hook called")
+	}
+}
@@ -0,0 +1,56 @@
+package errors
+
+import "sync"
+
+var (
+	convertersMu sync.RWMutex
+	// converters are the functions registered with RegisterConverter.
+	converters []func(error) (error, bool)
+)
+
+// RegisterConverter registers a function converting the errors it recognizes
+// to errors of this package, for Normalize: f returns the converted error, or
+// false for the errors it does not recognize. For instance, to convert the
+// errors of a database driver:
+//
+//	errors.RegisterConverter(func(err error) (error, bool) {
+//		var pqErr *pq.Error
+//		if !errors.As(err, &pqErr) || pqErr.Code != "23505" {
+//			return nil, false
+//		}
+//		return errors.WithKind(errors.WithCode(err, "DB-DUP"), errors.KindAlreadyExists), true
+//	})
+//
+// Converters are tried in the order they were registered, those returning a
+// nil error being ignored.
+func RegisterConverter(f func(err error) (error, bool)) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	converters = append(converters, f)
+}
+
+// Normalize converts err at the boundary with infrastructure code, such as a
+// database or a client library: it returns the error of the first converter
+// recognizing err, see RegisterConverter. If the resulting error has no kind,
+// it is annotated with the kind InferKind infers, if any.
+// If err is nil, Normalize returns nil.
+func Normalize(err error) error {
+	if err == nil {
+		return nil
+	}
+	convertersMu.RLock()
+	registered := converters
+	convertersMu.RUnlock()
+	for _, f := range registered {
+		if converted, ok := f(err); ok && converted != nil {
+			err = converted
+			break
+		}
+	}
+	if KindOf(err) == KindUnknown {
+		if k := InferKind(err); k != KindUnknown {
+			err = WithKind(err, k)
+		}
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+)
+
+// driverError is an error of an infrastructure library.
+type driverError struct{ code string }
+
+func (e *driverError) Error() string { return "driver: " + e.code }
+
+func TestNormalize(t *testing.T) {
+	defer func(registered []func(error) (error, bool)) { converters = registered }(converters)
+	RegisterConverter(func(err error) (error, bool) {
+		var d *driverError
+		if !As(err, &d) || d.code != "dup" {
+			return nil, false
+		}
+		return WithKind(WithCode(err, "DB-DUP"), KindAlreadyExists), true
+	})
+	RegisterConverter(func(err error) (error, bool) {
+		if err != io.ErrUnexpectedEOF {
+			return nil, false
+		}
+		return WithCode(err, "TRUNCATED"), true
+	})
+	RegisterConverter(func(err error) (error, bool) {
+		return nil, true
+	})
+
+	if err := Normalize(nil); err != nil {
+		t.Errorf("Normalize(nil) = %v, want nil", err)
+	}
+	tests := []struct {
+		err  error
+		kind Kind
+		code Code
+	}{
+		{Wrap(&driverError{"dup"}, "insert"), KindAlreadyExists, "DB-DUP"},
+		{&driverError{"other"}, KindUnknown, ""},
+		{io.ErrUnexpectedEOF, KindUnknown, "TRUNCATED"},
+		{Wrap(context.DeadlineExceeded, "query"), KindTimeout, ""},
+		{WithKind(os.ErrNotExist, KindInvalid), KindInvalid, ""},
+		{os.ErrNotExist, KindNotFound, ""},
+	}
+	for _, tt := range tests {
+		got := Normalize(tt.err)
+		if KindOf(got) != tt.kind || CodeOf(got) != tt.code {
+			t.Errorf("Normalize(%v): kind %q, code %q, want %q, %q", tt.err, KindOf(got), CodeOf(got), tt.kind, tt.code)
+		}
+		if got.Error() != tt.err.Error() || !Is(got, Cause(tt.err)) {
+			t.Errorf("Normalize(%v) = %v, does not wrap the error", tt.err, got)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	goerrors "errors"
+	"fmt"
+	"strings"
+)
+
+// FromPanic returns an error for the value v recovered from a panic, of kind
+// KindInternal and with the stack trace of the panic, or nil if v is nil:
+//
+//	defer func() {
+//		if err := errors.FromPanic(recover()); err != nil {
+//			log.Printf("%+v", err)
+//		}
+//	}()
+//
+// Its message is "panic: " followed by the message of v if it is an error,
+// or else v printed with %v. v is returned by PanicValue. If v is an error
+// already carrying a panic, as rethrown by Repanic, it is returned as-is.
+func FromPanic(v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	err, ok := v.(error)
+	if ok {
+		if _, ok := PanicValue(err); ok {
+			return err
+		}
+	} else {
+		err = goerrors.New(fmt.Sprint(v))
+	}
+	return WithKind(WithValue(&withStack{
+		error:   err,
+		stack:   callers(),
+		created: timestamp(),
+		msg:     "panic",
+		wrapped: true,
+	}, panicValueKey{}, v), KindInternal)
+}
+
+// panicValueKey is the key of the values recovered by FromPanic.
+type panicValueKey struct{}
+
+// PanicValue returns the value recovered from a panic that err was created
+// from by FromPanic.
+func PanicValue(err error) (interface{}, bool) {
+	return ValueFrom(err, panicValueKey{})
+}
+
+// Repanic panics with err, unless it is nil, for the middlewares that must
+// rethrow panics after reporting them:
+//
+//	defer func() {
+//		if err := errors.FromPanic(recover()); err != nil {
+//			report(err)
+//			errors.Repanic(err)
+//		}
+//	}()
+//
+// The errors created by FromPanic keep the stack trace of the original panic
+// and its value, as returned by PanicValue, when they are recovered again by
+// FromPanic.
+func Repanic(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Fingerprint returns an identifier of the origin of err, suitable to group
+// the occurrences of an error in reports and alerts: it is the same for
+// errors created at the same place through the same calls, whatever their
+// messages, and the same once the error is decoded by another service. It
+// is computed from the functions of the deepest stack trace of err, local or
+// remote. For errors without stack trace, the type and message of the cause
+// are used instead, unless the chain has a RemoteError decoded with the
+// fingerprint of the original error.
+// If err is nil, Fingerprint returns an empty string.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	if fp, ok := lookupAttr(err, "fingerprint"); ok && len(originFuncs(err)) == 0 {
+		return fp.(string)
+	}
+	h := sha256.New()
+	if funcs := originFuncs(err); len(funcs) > 0 {
+		for _, fn := range funcs {
+			fmt.Fprintln(h, fn)
+		}
+	} else {
+		cause := Cause(err)
+		fmt.Fprintf(h, "%T\n%s\n", cause, cause.Error())
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// originFuncs returns the functions of the frames of the deepest stack
+// trace of err, local or remote.
+func originFuncs(err error) []string {
+	var funcs []string
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		switch e := err.(type) {
+		case *withStack:
+			st := e.StackTrace()
+			funcs = make([]string, len(st))
+			for i, f := range st {
+				funcs[i] = f.name()
+			}
+		case *RemoteError:
+			if len(e.Stack) > 0 {
+				funcs = make([]string, len(e.Stack))
+				for i, f := range e.Stack {
+					if j := strings.IndexByte(f, ' '); j >= 0 {
+						f = f[:j]
+					}
+					funcs[i] = f
+				}
+			}
+		}
+		causes := causes(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return funcs
+}
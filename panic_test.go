@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func recovered(f func()) (err error) {
+	defer func() {
+		err = FromPanic(recover())
+	}()
+	f()
+	return nil
+}
+
+func TestFromPanic(t *testing.T) {
+	if err := recovered(func() {}); err != nil {
+		t.Errorf("FromPanic(nil): got %v, want nil", err)
+	}
+	tests := []struct {
+		v    interface{}
+		want string
+	}{
+		{"boom", "panic: boom"},
+		{42, "panic: 42"},
+		{io.EOF, "panic: EOF"},
+	}
+	for _, tt := range tests {
+		err := recovered(func() { panic(tt.v) })
+		if got := err.Error(); got != tt.want {
+			t.Errorf("FromPanic(%v): got %q, want %q", tt.v, got, tt.want)
+		}
+		if got := KindOf(err); got != KindInternal {
+			t.Errorf("FromPanic(%v): got kind %q, want %q", tt.v, got, KindInternal)
+		}
+		if got := fmt.Sprintf("%+v", err); !strings.Contains(got, "errors.TestFromPanic.func") {
+			t.Errorf("FromPanic(%v): got %q, want the stack trace of the panic", tt.v, got)
+		}
+	}
+	if err := recovered(func() { panic(io.EOF) }); Cause(err) != io.EOF {
+		t.Errorf("Cause(FromPanic(io.EOF)): got %v, want io.EOF", Cause(err))
+	}
+}
+
+func TestPanicValueRepanic(t *testing.T) {
+	type payload struct{ id int }
+	err := recovered(func() { panic(payload{42}) })
+	if v, ok := PanicValue(err); !ok || v != (payload{42}) {
+		t.Errorf("PanicValue(): got %v, %t, want the recovered value", v, ok)
+	}
+	if _, ok := PanicValue(New("boom")); ok {
+		t.Errorf("PanicValue(New()): got a value")
+	}
+
+	again := recovered(func() { Repanic(err) })
+	if again != err {
+		t.Errorf("FromPanic(Repanic(err)): got %v, want err", again)
+	}
+	if v, _ := PanicValue(again); v != (payload{42}) {
+		t.Errorf("PanicValue() after Repanic: got %v, want the original value", v)
+	}
+	if err := recovered(func() { Repanic(nil) }); err != nil {
+		t.Errorf("Repanic(nil): got %v, want no panic", err)
+	}
+}
+
+func fingerprinted(id int) error {
+	return Wrapf(New(fmt.Sprintf("device %d not found", id)), "get device %d", id)
+}
+
+func TestFingerprint(t *testing.T) {
+	if got := Fingerprint(nil); got != "" {
+		t.Errorf("Fingerprint(nil): got %q, want \"\"", got)
+	}
+	first := Fingerprint(fingerprinted(1))
+	if !regexp.MustCompile(`^[0-9a-f]{16}$`).MatchString(first) {
+		t.Errorf("Fingerprint: got %q, want 16 hexadecimal digits", first)
+	}
+	if got := Fingerprint(fingerprinted(2)); got != first {
+		t.Errorf("Fingerprint: got %q and %q for the same origin", first, got)
+	}
+	if got := Fingerprint(New("device 1 not found")); got == first {
+		t.Errorf("Fingerprint: got %q for different origins", got)
+	}
+	if Fingerprint(io.EOF) == Fingerprint(io.ErrUnexpectedEOF) {
+		t.Errorf("Fingerprint: got the same fingerprint for different errors without stack")
+	}
+	remote, _ := FromMap(ToMap(fingerprinted(1)))
+	if got := Fingerprint(Wrap(remote, "call")); got != first {
+		t.Errorf("Fingerprint(remote): got %q, want %q", got, first)
+	}
+	if a, b := Fingerprint(remote), Fingerprint(Wrap(remote, "call")); a != b {
+		t.Errorf("Fingerprint(remote): got %q and %q", a, b)
+	}
+}
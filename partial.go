@@ -0,0 +1,159 @@
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxPartialMessages is the number of errors of items included in the
+// message of a Partial.
+const maxPartialMessages = 3
+
+// Partial collects the errors of the items of a batch operation that may
+// partially succeed, keyed by the index or the ID of the items:
+//
+//	p := errors.NewPartial(len(devices))
+//	for _, d := range devices {
+//		p.Fail(d.EUI, provision(d))
+//	}
+//	return p.Err() // "1 of 3 succeeded: 70B3D5: timeout; 70B3D6: not found"
+//
+// A Partial is also the error returned by Err, that errors.Is and errors.As
+// match with the errors of its items, and that As can retrieve.
+type Partial struct {
+	total    int
+	failures []Failure
+}
+
+// Failure is the error of an item of a Partial.
+type Failure struct {
+	Key interface{}
+	Err error
+}
+
+// NewPartial returns a Partial for a batch of total items.
+func NewPartial(total int) *Partial {
+	return &Partial{total: total}
+}
+
+// Fail records the error err of the item key. Nil errors are ignored.
+func (p *Partial) Fail(key interface{}, err error) {
+	if err != nil {
+		p.failures = append(p.failures, Failure{Key: key, Err: err})
+	}
+}
+
+// Failures returns the errors of the items, in the order they were
+// recorded.
+func (p *Partial) Failures() []Failure {
+	return p.failures
+}
+
+// Total returns the number of items of the batch.
+func (p *Partial) Total() int {
+	return p.total
+}
+
+// Succeeded returns the number of items that did not fail.
+func (p *Partial) Succeeded() int {
+	if n := p.total - len(p.failures); n > 0 {
+		return n
+	}
+	return 0
+}
+
+// Err returns nil if no item failed. Otherwise, it returns an error wrapping
+// a copy of p, with the stack trace at the point Err was called.
+func (p *Partial) Err() error {
+	if len(p.failures) == 0 {
+		return nil
+	}
+	return &withStack{
+		error:   &Partial{total: p.total, failures: append([]Failure(nil), p.failures...)},
+		stack:   callers(),
+		created: timestamp(),
+	}
+}
+
+// Error returns the number of items that succeeded, followed by the errors
+// of the first three items that failed.
+func (p *Partial) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d succeeded", p.Succeeded(), p.total)
+	for i, f := range p.failures {
+		if i == maxPartialMessages {
+			fmt.Fprintf(&b, "; and %d more", len(p.failures)-i)
+			break
+		}
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		fmt.Fprintf(&b, "%v: %s", f.Key, f.Err)
+	}
+	return b.String()
+}
+
+// Unwrap returns the errors of the items
+func (p *Partial) Unwrap() []error {
+	errs := make([]error, len(p.failures))
+	for i, f := range p.failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// matchesCauses marks the Is and As methods as only matching the causes.
+func (p *Partial) matchesCauses() {}
+
+// mutableCauses marks p as getting more errors after it is wrapped.
+func (p *Partial) mutableCauses() {}
+
+// Is reports whether the error of an item matches target, whatever the
+// version of Go.
+func (p *Partial) Is(target error) bool {
+	for _, f := range p.failures {
+		if goerrors.Is(f.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of an item that matches target, whatever the
+// version of Go.
+func (p *Partial) As(target interface{}) bool {
+	for _, f := range p.failures {
+		if goerrors.As(f.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Format formats the number of items that succeeded, then the errors of all
+// the items with their keys and stack traces for %+v
+func (p *Partial) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatIndented(s, p) {
+				return
+			}
+			_, _ = fmt.Fprintf(s, "%d of %d succeeded", p.Succeeded(), p.total)
+			for _, f := range p.failures {
+				_, _ = fmt.Fprintf(s, "\n%v: ", f.Key)
+				formatCause(s, f.Err)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, p.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", p.Error())
+	}
+}
@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"testing"
+)
+
+func TestPartial(t *testing.T) {
+	p := NewPartial(5)
+	if p.Err() != nil {
+		t.Errorf("Err() of an empty Partial != nil")
+	}
+	p.Fail(0, nil)
+	p.Fail("dev-1", io.EOF)
+	p.Fail(3, &os.PathError{Op: "open", Path: "a", Err: os.ErrNotExist})
+	err := p.Err()
+	p.Fail(4, io.ErrClosedPipe)
+	if got, want := err.Error(), "3 of 5 succeeded: dev-1: EOF; 3: open a: file does not exist"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !Is(err, io.EOF) || !Is(err, os.ErrNotExist) || Is(err, io.ErrClosedPipe) {
+		t.Errorf("Is(%v) does not match the errors of the items", err)
+	}
+	var pathErr *os.PathError
+	if !As(err, &pathErr) || pathErr.Path != "a" {
+		t.Errorf("As(%v, *os.PathError) = %v", err, pathErr)
+	}
+	var partial *Partial
+	if !As(err, &partial) || partial.Total() != 5 || partial.Succeeded() != 3 || len(partial.Failures()) != 2 {
+		t.Errorf("As(%v, *Partial) = %+v", err, partial)
+	}
+	if got := p.Succeeded(); got != 2 {
+		t.Errorf("Succeeded() = %d, want 2", got)
+	}
+}
+
+func TestPartialMessage(t *testing.T) {
+	p := NewPartial(2)
+	for i := 0; i < 5; i++ {
+		p.Fail(i, fmt.Errorf("error %d", i))
+	}
+	if got, want := p.Error(), "0 of 2 succeeded: 0: error 0; 1: error 1; 2: error 2; and 2 more"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestPartialFormat(t *testing.T) {
+	p := NewPartial(3)
+	p.Fail("dev-1", New("timeout"))
+	p.Fail("dev-2", io.EOF)
+	got := fmt.Sprintf("%+v", p)
+	want := "^1 of 3 succeeded\n" +
+		"dev-1: timeout\n" +
+		"github.com/objenious/errors.TestPartialFormat\n" +
+		"\t.+/github.com/objenious/errors/partial_test.go:52\n" +
+		"(?s:.*)\n" +
+		"dev-2: EOF$"
+	if !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("%%+v:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestPartialIsCache(t *testing.T) {
+	p := NewPartial(2)
+	err := Wrap(p, "provision")
+	if Is(err, io.EOF) {
+		t.Fatalf("Is matches an empty Partial")
+	}
+	p.Fail("70B3D5", io.EOF)
+	if !Is(err, io.EOF) {
+		t.Errorf("Is returns the result cached before the error was added")
+	}
+	// the results for the other chains are still cached.
+	wrapped := Wrap(io.EOF, "read")
+	if !Is(wrapped, io.EOF) || wrapped.(*withStack).matches.Load() == nil {
+		t.Errorf("Is does not cache the result for %v", wrapped)
+	}
+}
@@ -0,0 +1,48 @@
+package errors
+
+import "encoding/hex"
+
+// payload is the value of the attributes set by WithPayload.
+type payload struct {
+	data []byte
+	size int
+}
+
+// String returns p in hexadecimal, followed by "..." if it was truncated.
+func (p payload) String() string {
+	s := hex.EncodeToString(p.data)
+	if len(p.data) < p.size {
+		s += "..."
+	}
+	return s
+}
+
+// WithPayload annotates err with a copy of the first maxLen bytes of data,
+// typically the binary payload a decoder failed to parse, as returned by
+// Payload. The payload is printed in hexadecimal by %+v and included by
+// ToMap as "payload", followed by "..." if it was truncated. If maxLen is 0
+// or less, all of data is kept.
+// If err is nil, WithPayload returns nil.
+func WithPayload(err error, data []byte, maxLen int) error {
+	if err == nil {
+		return nil
+	}
+	n := len(data)
+	if maxLen > 0 && n > maxLen {
+		n = maxLen
+	}
+	p := payload{data: make([]byte, n), size: len(data)}
+	copy(p.data, data)
+	return &withAttr{error: err, name: "payload", value: p}
+}
+
+// Payload returns the payload err was annotated with by WithPayload, and
+// whether it was truncated.
+func Payload(err error) (data []byte, truncated bool, ok bool) {
+	v, ok := lookupAttr(err, "payload")
+	if !ok {
+		return nil, false, false
+	}
+	p := v.(payload)
+	return p.data, len(p.data) < p.size, true
+}
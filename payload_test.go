@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWithPayload(t *testing.T) {
+	if err := WithPayload(nil, []byte{1}, 0); err != nil {
+		t.Errorf("WithPayload(nil) = %v, want nil", err)
+	}
+	if _, _, ok := Payload(io.EOF); ok {
+		t.Errorf("Payload(io.EOF) = true, want false")
+	}
+
+	data := []byte{0xca, 0xfe, 0xba, 0xbe}
+	tests := []struct {
+		maxLen    int
+		want      []byte
+		truncated bool
+		text      string
+	}{
+		{0, data, false, "cafebabe"},
+		{4, data, false, "cafebabe"},
+		{2, data[:2], true, "cafe..."},
+	}
+	for _, tt := range tests {
+		err := Wrap(WithPayload(io.ErrUnexpectedEOF, data, tt.maxLen), "decode uplink")
+		got, truncated, ok := Payload(err)
+		if !ok || !bytes.Equal(got, tt.want) || truncated != tt.truncated {
+			t.Errorf("maxLen %d: Payload() = %x, %t, %t", tt.maxLen, got, truncated, ok)
+		}
+		if m := ToMap(err)["cause"].(map[string]interface{}); m["payload"] != tt.text {
+			t.Errorf("maxLen %d: ToMap() payload = %v, want %s", tt.maxLen, m["payload"], tt.text)
+		}
+		if s := fmt.Sprintf("%+v", err); !strings.Contains(s, "unexpected EOF\npayload: "+tt.text+"\n") {
+			t.Errorf("maxLen %d: %%+v does not contain the payload:\n%s", tt.maxLen, s)
+		}
+		if s := err.Error(); s != "decode uplink: unexpected EOF" {
+			t.Errorf("maxLen %d: Error() = %q", tt.maxLen, s)
+		}
+	}
+
+	err := WithPayload(io.EOF, data, 0)
+	data[0] = 0
+	if got, _, _ := Payload(err); got[0] != 0xca {
+		t.Errorf("WithPayload does not copy the payload")
+	}
+}
@@ -0,0 +1,35 @@
+package errors
+
+import "sync/atomic"
+
+// policyHook holds the function set by SetPolicyHook.
+var policyHook atomic.Value
+
+type policyViolationHook struct {
+	f func(err error, boundary string)
+}
+
+// SetPolicyHook enables the runtime check of the annotation of errors, to
+// find in staging the error paths that are not annotated: hook is called
+// with the errors crossing a boundary that carry neither a stack trace,
+// local or remote, nor a code (see WithCode), and the name of the boundary.
+// The boundaries are Freeze, ToJSON and the ones of the packages calling
+// CheckPolicy, such as errgrpc.ToStatus. A nil hook disables the check, as
+// by default.
+func SetPolicyHook(hook func(err error, boundary string)) {
+	policyHook.Store(policyViolationHook{hook})
+}
+
+// CheckPolicy calls the hook set by SetPolicyHook with err if it carries
+// neither a stack trace nor a code, for the packages defining boundaries
+// of their own. boundary is the name of the boundary, such as
+// "errgrpc.ToStatus".
+func CheckPolicy(err error, boundary string) {
+	h, _ := policyHook.Load().(policyViolationHook)
+	if h.f == nil || err == nil {
+		return
+	}
+	if !hasStack(acyclic(err)) && CodeOf(err) == "" {
+		h.f(err, boundary)
+	}
+}
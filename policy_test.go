@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestSetPolicyHook(t *testing.T) {
+	var violations []string
+	SetPolicyHook(func(err error, boundary string) {
+		violations = append(violations, boundary+": "+err.Error())
+	})
+	defer SetPolicyHook(nil)
+
+	Freeze(Wrap(io.EOF, "read"))
+	_, _ = ToJSON(WithCode(io.EOF, "DEV-0001"))
+	_, _ = ToJSON(&RemoteError{Message: "boom", Stack: []string{"main.main main.go:1"}})
+	Freeze(WithField(io.EOF, "key", "value"))
+	_, _ = ToJSON(io.ErrUnexpectedEOF)
+	CheckPolicy(io.ErrShortWrite, "custom")
+	CheckPolicy(nil, "custom")
+
+	want := []string{"Freeze: EOF", "ToJSON: unexpected EOF", "custom: short write"}
+	if !reflect.DeepEqual(violations, want) {
+		t.Errorf("violations = %q, want %q", violations, want)
+	}
+
+	SetPolicyHook(nil)
+	violations = nil
+	Freeze(io.EOF)
+	if violations != nil {
+		t.Errorf("violations = %q with the check disabled", violations)
+	}
+}
@@ -0,0 +1,149 @@
+package errors
+
+import goerrors "errors"
+
+// Truncate returns err with its chain cut after its maxDepth outermost
+// levels, not counting the annotations of WithKind, WithField and the like,
+// to keep the logs of deeply wrapped errors readable. The last level kept
+// becomes the end of the chain: it keeps its message, which includes the
+// messages of the levels cut, and gets the deepest stack trace of the chain.
+// The errors cut are no longer matched by errors.Is and errors.As, and
+// levels below errors not created by this package are never cut.
+// If maxDepth is less than 1, or the chain of err is not deeper, Truncate
+// returns err.
+func Truncate(err error, maxDepth int) error {
+	if maxDepth < 1 {
+		return err
+	}
+	return truncate(err, maxDepth)
+}
+
+func truncate(err error, depth int) error {
+	switch w := err.(type) {
+	case nil:
+		return nil
+	case *withAttr:
+		if cause := truncate(w.error, depth); cause != w.error {
+			return &withAttr{error: cause, name: w.name, value: w.value}
+		}
+		return w
+	case *withStack:
+		if len(causes(w)) == 0 {
+			return w
+		}
+		if depth > 1 {
+			if !w.wrapped {
+				return w
+			}
+			if cause := truncate(w.error, depth-1); cause != w.error {
+				return w.rewrap(cause)
+			}
+			return w
+		}
+		return leaf(w)
+	}
+	return err
+}
+
+// leaf returns an error with the message, the deepest stack trace and the
+// creation time of err, but without its causes.
+func leaf(err error) error {
+	st := GetStackTrace(err)
+	if st == nil {
+		return goerrors.New(err.Error())
+	}
+	created, _ := CreatedAt(err)
+	return &withStack{
+		error:   goerrors.New(err.Error()),
+		stack:   st,
+		created: created,
+	}
+}
+
+// Simplify returns err without the redundant levels of its chain, as added
+// by defensive wrapping:
+//
+//   - consecutive wrappers with the same message, as in "read: read: EOF",
+//     are collapsed into the innermost one,
+//   - wrappers adding a stack trace but no message, such as WithStack, are
+//     removed when their cause already has a stack trace,
+//   - wrappers repeating the message of their cause, as in "EOF: EOF", only
+//     keep their stack trace, if their cause has none.
+//
+// The innermost stack traces are preserved. Only the levels created by this
+// package are simplified.
+func Simplify(err error) error {
+	switch w := err.(type) {
+	case *withAttr:
+		if cause := Simplify(w.error); cause != w.error {
+			return &withAttr{error: cause, name: w.name, value: w.value}
+		}
+		return w
+	case *withStack:
+		if !w.wrapped {
+			return w
+		}
+		cause := Simplify(w.error)
+		msg := w.message()
+		if inner, ok := cause.(*withStack); ok && inner.wrapped && msg != "" && inner.message() == msg {
+			return inner
+		}
+		if msg == "" || msg == cause.Error() {
+			if GetStackTrace(cause) != nil {
+				return cause
+			}
+			if msg != "" {
+				return &withStack{error: cause, stack: w.stack, created: w.created, wrapped: true, factory: w.factory}
+			}
+		}
+		if cause != w.error {
+			return w.rewrap(cause)
+		}
+		return w
+	}
+	return err
+}
+
+// rewrap returns a copy of w wrapping cause.
+func (w *withStack) rewrap(cause error) *withStack {
+	return &withStack{
+		error:   cause,
+		stack:   w.stack,
+		msg:     w.msg,
+		lazy:    w.lazy,
+		wrapped: true,
+		created: w.created,
+		factory: w.factory,
+	}
+}
+
+// Rewrap returns a copy of err with the innermost cause of its chain replaced
+// by newCause, to sanitize the errors of third-party packages before returning
+// them across module boundaries:
+//
+//	err = errors.Rewrap(err, errors.New("storage unavailable"))
+//
+// The levels created by Wrap, WithStack and the annotations of this package,
+// such as WithField or WithKind, are rebuilt over newCause with their
+// messages, fields and stack traces. The first other error of the chain is
+// the one replaced, with its own causes; this includes the errors created by
+// New, Errorf and Join.
+// If err or newCause is nil, Rewrap returns err.
+func Rewrap(err, newCause error) error {
+	if err == nil || newCause == nil {
+		return err
+	}
+	return rewrapCause(err, newCause)
+}
+
+func rewrapCause(err, newCause error) error {
+	switch w := err.(type) {
+	case *withAttr:
+		return &withAttr{error: rewrapCause(w.error, newCause), name: w.name, value: w.value}
+	case *withStack:
+		if w.wrapped {
+			return w.rewrap(rewrapCause(w.error, newCause))
+		}
+	}
+	return newCause
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func chainDepth(err error) int {
+	n := 0
+	for ; err != nil; n++ {
+		c := causes(err)
+		if len(c) == 0 {
+			return n + 1
+		}
+		err = c[0]
+	}
+	return n
+}
+
+func TestTruncate(t *testing.T) {
+	root := New("root")
+	err := WithKind(Wrap(Wrap(Wrap(root, "a"), "b"), "c"), KindInternal)
+	if got := Truncate(err, 0); got != err {
+		t.Errorf("Truncate(0): got %v, want err", got)
+	}
+	if got := Truncate(err, 10); got != err {
+		t.Errorf("Truncate(10): got %v, want err", got)
+	}
+	if got := Truncate(nil, 1); got != nil {
+		t.Errorf("Truncate(nil): got %v, want nil", got)
+	}
+	got := Truncate(err, 2)
+	if got.Error() != err.Error() {
+		t.Errorf("Truncate(2): got %q, want %q", got, err)
+	}
+	if d := chainDepth(got); d != 3 { // kind, c, b
+		t.Errorf("Truncate(2): got depth %d, want 3", d)
+	}
+	if KindOf(got) != KindInternal || GetStackTrace(got) != GetStackTrace(root) {
+		t.Errorf("Truncate(2): lost the kind or the deepest stack trace")
+	}
+	if Is(got, root) {
+		t.Errorf("Truncate(2): the errors cut are still matched")
+	}
+
+	external := fmt.Errorf("external: %w", Wrap(root, "a"))
+	if got := Truncate(Wrap(external, "b"), 2); got.(*withStack).error != external {
+		t.Errorf("Truncate: cut below an external error")
+	}
+	if got := Truncate(Wrap(io.EOF, "read"), 1); got.Error() != "read: EOF" || chainDepth(got) != 1 {
+		t.Errorf("Truncate(1): got %q of depth %d", got, chainDepth(got))
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	root := New("root")
+	tests := []struct {
+		err   error
+		want  string
+		depth int
+	}{
+		{nil, "", 0},
+		{io.EOF, "EOF", 1},
+		{root, "root", 1},
+		{Wrap(Wrap(Wrap(root, "x"), "x"), "x"), "x: root", 2},
+		{WithStack(WithStack(Wrap(root, "x"))), "x: root", 2},
+		{WithStack(io.EOF), "EOF", 2},
+		{Wrap(io.EOF, "EOF"), "EOF", 2},
+		{Wrap(Wrap(root, "root"), "read"), "read: root", 2},
+		{WithKind(Wrap(Wrap(root, "x"), "x"), KindInternal), "x: root", 3},
+		{Wrap(fmt.Errorf("y: %w", Wrap(root, "x")), "x"), "x: y: x: root", 4},
+	}
+	for i, tt := range tests {
+		got := Simplify(tt.err)
+		if tt.err == nil {
+			if got != nil {
+				t.Errorf("test %d: got %v, want nil", i+1, got)
+			}
+			continue
+		}
+		if got.Error() != tt.want || chainDepth(got) != tt.depth {
+			t.Errorf("test %d: got %q of depth %d, want %q of depth %d", i+1, got, chainDepth(got), tt.want, tt.depth)
+		}
+	}
+	err := Simplify(WithStack(Wrap(Wrap(root, "x"), "x")))
+	if GetStackTrace(err) != GetStackTrace(root) || !Is(err, root) {
+		t.Errorf("Simplify: lost the innermost stack trace or the root")
+	}
+	if got := fmt.Sprintf("%+v", Simplify(Wrap(io.EOF, "EOF"))); !strings.HasPrefix(got, "EOF\ngithub.com/objenious/errors.TestSimplify\n") {
+		t.Errorf("Simplify: got %q, want the stack trace of the wrapper", got)
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	sanitized := New("storage unavailable")
+	original := fmt.Errorf("dial tcp 10.0.0.1:5432: %w", io.EOF)
+	wrapped := Wrap(WithField(WithStack(original), "table", "users"), "load users")
+	err := WithKind(wrapped, KindUnavailable)
+
+	got := Rewrap(err, sanitized)
+	if want := "load users: storage unavailable"; got.Error() != want {
+		t.Errorf("Rewrap().Error() = %q, want %q", got.Error(), want)
+	}
+	if Is(got, io.EOF) {
+		t.Error("Rewrap() still matches the replaced cause")
+	}
+	if !Is(got, sanitized) {
+		t.Error("Rewrap() does not match the new cause")
+	}
+	if KindOf(got) != KindUnavailable {
+		t.Errorf("KindOf(Rewrap()) = %v, want %v", KindOf(got), KindUnavailable)
+	}
+	if v, _ := Field(got, "table"); v != "users" {
+		t.Errorf("Field(Rewrap(), table) = %v, want users", v)
+	}
+	if got.(*withAttr).error.(*withStack).stack != wrapped.(*withStack).stack {
+		t.Error("Rewrap() did not keep the stack trace of Wrap")
+	}
+	if err.Error() != "load users: dial tcp 10.0.0.1:5432: EOF" {
+		t.Errorf("Rewrap() modified err: %q", err.Error())
+	}
+
+	if got := Rewrap(nil, sanitized); got != nil {
+		t.Errorf("Rewrap(nil) = %v, want nil", got)
+	}
+	if got := Rewrap(err, nil); got != err {
+		t.Errorf("Rewrap(err, nil) = %v, want err", got)
+	}
+	if got := Rewrap(io.EOF, sanitized); got != sanitized {
+		t.Errorf("Rewrap(io.EOF) = %v, want the new cause", got)
+	}
+}
@@ -0,0 +1,106 @@
+package errors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitInfo describes the rate limit of an API when an error occurred.
+type RateLimitInfo struct {
+	Limit     int       // number of requests allowed in the window
+	Remaining int       // number of requests remaining in the window
+	Reset     time.Time // time the window is reset, or zero
+}
+
+// WithRateLimit annotates err with the rate limit of the API that failed or
+// rejected a request, as returned by RateLimit, so that schedulers can wait
+// until reset before sending more requests, and servers can send the
+// RateLimit headers of the limit. It is included by ToMap as "rate_limit".
+// If err is nil, WithRateLimit returns nil.
+func WithRateLimit(err error, limit, remaining int, reset time.Time) error {
+	if err == nil {
+		return nil
+	}
+	return &withAttr{error: err, name: "rate_limit", value: RateLimitInfo{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     reset,
+	}}
+}
+
+// RateLimit returns the rate limit set by WithRateLimit on the outermost
+// error of the chain of err, if any.
+func RateLimit(err error) (RateLimitInfo, bool) {
+	v, ok := lookupAttr(err, "rate_limit")
+	l, _ := v.(RateLimitInfo)
+	return l, ok
+}
+
+// SetHeaders sets the RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers of l in h, the reset being given in seconds from
+// now, rounded up. RateLimit-Reset is not set if l has no reset time.
+func (l RateLimitInfo) SetHeaders(h http.Header) {
+	h.Set("RateLimit-Limit", strconv.Itoa(l.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(l.Remaining))
+	if !l.Reset.IsZero() {
+		seconds := int64(0)
+		if d := time.Until(l.Reset); d > 0 {
+			seconds = int64((d + time.Second - 1) / time.Second)
+		}
+		h.Set("RateLimit-Reset", strconv.FormatInt(seconds, 10))
+	}
+}
+
+// mapValue returns the representation of l in ToMap.
+func (l RateLimitInfo) mapValue() map[string]interface{} {
+	m := map[string]interface{}{
+		"limit":     l.Limit,
+		"remaining": l.Remaining,
+	}
+	if !l.Reset.IsZero() {
+		m["reset"] = l.Reset.UTC().Format(time.RFC3339)
+	}
+	return m
+}
+
+// unixResetThreshold is the value of reset headers above which they are
+// Unix times rather than delays in seconds.
+const unixResetThreshold = 1e9
+
+// parseRateLimit returns the rate limit given by the RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers of h, or by their
+// X-RateLimit- counterparts. Reset values above 10^9 are taken as Unix
+// times, the others as delays in seconds from now.
+func parseRateLimit(h http.Header) (RateLimitInfo, bool) {
+	for _, prefix := range []string{"RateLimit-", "X-RateLimit-"} {
+		limit, err := strconv.Atoi(firstValue(h.Get(prefix + "Limit")))
+		if err != nil {
+			continue
+		}
+		remaining, err := strconv.Atoi(firstValue(h.Get(prefix + "Remaining")))
+		if err != nil {
+			continue
+		}
+		l := RateLimitInfo{Limit: limit, Remaining: remaining}
+		if reset, err := strconv.ParseInt(firstValue(h.Get(prefix+"Reset")), 10, 64); err == nil && reset >= 0 {
+			if reset > unixResetThreshold {
+				l.Reset = time.Unix(reset, 0)
+			} else {
+				l.Reset = time.Now().Add(time.Duration(reset) * time.Second)
+			}
+		}
+		return l, true
+	}
+	return RateLimitInfo{}, false
+}
+
+// firstValue returns the first element of a header value that may list
+// several, such as "100, 100;w=60", without its parameters.
+func firstValue(v string) string {
+	if i := strings.IndexAny(v, ",;"); i >= 0 {
+		v = v[:i]
+	}
+	return strings.TrimSpace(v)
+}
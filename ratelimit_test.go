@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	if WithRateLimit(nil, 100, 0, time.Time{}) != nil {
+		t.Errorf("WithRateLimit(nil) != nil")
+	}
+	if _, ok := RateLimit(New("boom")); ok {
+		t.Errorf("RateLimit() of an error without rate limit is set")
+	}
+	reset := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	err := Wrap(WithRateLimit(io.EOF, 100, 0, reset), "read")
+	want := RateLimitInfo{Limit: 100, Remaining: 0, Reset: reset}
+	if got, ok := RateLimit(err); !ok || got != want {
+		t.Errorf("RateLimit() = %+v, %v, want %+v", got, ok, want)
+	}
+	m := ToMap(err)["cause"].(map[string]interface{})
+	if got := m["rate_limit"].(map[string]interface{}); got["limit"] != 100 || got["remaining"] != 0 || got["reset"] != "2024-01-01T12:00:00Z" {
+		t.Errorf("ToMap() rate_limit = %v", got)
+	}
+}
+
+func TestRateLimitInfoSetHeaders(t *testing.T) {
+	h := http.Header{}
+	RateLimitInfo{Limit: 100, Remaining: 3, Reset: time.Now().Add(1500 * time.Millisecond)}.SetHeaders(h)
+	if h.Get("RateLimit-Limit") != "100" || h.Get("RateLimit-Remaining") != "3" || h.Get("RateLimit-Reset") != "2" {
+		t.Errorf("SetHeaders() = %v", h)
+	}
+	h = http.Header{}
+	RateLimitInfo{Limit: 100, Reset: time.Now().Add(-time.Second)}.SetHeaders(h)
+	if h.Get("RateLimit-Reset") != "0" {
+		t.Errorf("SetHeaders() = %v", h)
+	}
+	h = http.Header{}
+	RateLimitInfo{Limit: 100}.SetHeaders(h)
+	if _, ok := h["Ratelimit-Reset"]; ok {
+		t.Errorf("SetHeaders() = %v", h)
+	}
+}
+
+func TestFromResponseRateLimit(t *testing.T) {
+	tests := []struct {
+		header http.Header
+		want   RateLimitInfo
+		reset  time.Duration
+		ok     bool
+	}{
+		{http.Header{}, RateLimitInfo{}, 0, false},
+		{http.Header{"Ratelimit-Limit": {"100, 100;w=60"}, "Ratelimit-Remaining": {"0"}, "Ratelimit-Reset": {"30"}}, RateLimitInfo{Limit: 100}, 30 * time.Second, true},
+		{http.Header{"X-Ratelimit-Limit": {"5000"}, "X-Ratelimit-Remaining": {"12"}, "X-Ratelimit-Reset": {"1704110400"}}, RateLimitInfo{Limit: 5000, Remaining: 12, Reset: time.Unix(1704110400, 0)}, 0, true},
+		{http.Header{"X-Ratelimit-Limit": {"5000"}, "X-Ratelimit-Remaining": {"12"}}, RateLimitInfo{Limit: 5000, Remaining: 12}, 0, true},
+		{http.Header{"X-Ratelimit-Limit": {"many"}, "X-Ratelimit-Remaining": {"12"}}, RateLimitInfo{}, 0, false},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: tt.header, Body: ioutil.NopCloser(strings.NewReader(""))}
+		got, ok := RateLimit(FromResponse(resp))
+		if ok != tt.ok || got.Limit != tt.want.Limit || got.Remaining != tt.want.Remaining {
+			t.Errorf("RateLimit(%v) = %+v, %v, want %+v", tt.header, got, ok, tt.want)
+			continue
+		}
+		if tt.reset != 0 {
+			if d := time.Until(got.Reset); d <= tt.reset-time.Minute || d > tt.reset {
+				t.Errorf("RateLimit(%v) resets in %v, want %v", tt.header, d, tt.reset)
+			}
+		} else if !got.Reset.Equal(tt.want.Reset) {
+			t.Errorf("RateLimit(%v) resets at %v, want %v", tt.header, got.Reset, tt.want.Reset)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecordedError is an error kept by a Recorder.
+type RecordedError struct {
+	// Time is when the error was recorded.
+	Time time.Time
+	// Fingerprint is the fingerprint of the error, see Fingerprint.
+	Fingerprint string
+	// Err is the error.
+	Err error
+}
+
+// Recorder keeps the last errors it records in memory, to inspect them in
+// a debug endpoint: a Recorder is an http.Handler rendering them, and an
+// expvar.Var.
+//
+//	recorder := errors.NewRecorder(100)
+//	http.Handle("/debug/errors", recorder)
+type Recorder struct {
+	mu      sync.Mutex
+	entries []RecordedError
+	next    int
+	full    bool
+}
+
+// NewRecorder returns a Recorder keeping the last n errors. n must be
+// positive.
+func NewRecorder(n int) *Recorder {
+	if n <= 0 {
+		panic("errors: NewRecorder with a non-positive size")
+	}
+	return &Recorder{entries: make([]RecordedError, n)}
+}
+
+// Record records err, replacing the oldest error if the Recorder is full.
+// Nil errors are ignored.
+func (r *Recorder) Record(err error) {
+	if err == nil {
+		return
+	}
+	entry := RecordedError{Time: time.Now(), Fingerprint: Fingerprint(err), Err: err}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = entry
+	r.next++
+	if r.next == len(r.entries) {
+		r.next, r.full = 0, true
+	}
+}
+
+// Errors returns the errors recorded, the most recent first.
+func (r *Recorder) Errors() []RecordedError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := r.next
+	if r.full {
+		n = len(r.entries)
+	}
+	errs := make([]RecordedError, n)
+	for i := range errs {
+		errs[i] = r.entries[(r.next-1-i+len(r.entries))%len(r.entries)]
+	}
+	return errs
+}
+
+// recordedJSON is the JSON representation of a RecordedError.
+type recordedJSON struct {
+	Time        time.Time              `json:"time"`
+	Fingerprint string                 `json:"fingerprint"`
+	Error       map[string]interface{} `json:"error"`
+}
+
+func (r *Recorder) toJSON() []recordedJSON {
+	errs := r.Errors()
+	list := make([]recordedJSON, len(errs))
+	for i, e := range errs {
+		list[i] = recordedJSON{Time: e.Time, Fingerprint: e.Fingerprint, Error: ToMap(e.Err)}
+	}
+	return list
+}
+
+// String returns the JSON representation of the errors recorded, the most
+// recent first, so that a Recorder can be published with expvar.
+func (r *Recorder) String() string {
+	data, err := json.Marshal(r.toJSON())
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
+
+// ServeHTTP renders the errors recorded, the most recent first, in JSON if
+// the request has a "format=json" query or accepts application/json, and as
+// text printed with %+v otherwise.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Query().Get("format") == "json" || strings.Contains(req.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(r.toJSON())
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, e := range r.Errors() {
+		fmt.Fprintf(w, "%s %s\n%+v\n\n", e.Time.Format(time.RFC3339Nano), e.Fingerprint, e.Err)
+	}
+}
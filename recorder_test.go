@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"encoding/json"
+	"expvar"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var _ expvar.Var = (*Recorder)(nil)
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder(2)
+	if errs := r.Errors(); len(errs) != 0 {
+		t.Errorf("Errors(): got %v, want none", errs)
+	}
+	r.Record(nil)
+	r.Record(io.EOF)
+	if errs := r.Errors(); len(errs) != 1 || errs[0].Err != io.EOF || errs[0].Fingerprint != Fingerprint(io.EOF) || errs[0].Time.IsZero() {
+		t.Errorf("Errors(): got %v, want [EOF]", errs)
+	}
+	second, third := New("second"), New("third")
+	r.Record(second)
+	r.Record(third)
+	errs := r.Errors()
+	if len(errs) != 2 || errs[0].Err != third || errs[1].Err != second {
+		t.Errorf("Errors(): got %v, want [third second]", errs)
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/errors", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, " "+Fingerprint(third)+"\nthird\ngithub.com/objenious/errors.TestRecorder\n") || strings.Index(body, "third") > strings.Index(body, "second") {
+		t.Errorf("ServeHTTP text: got %q", body)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/errors?format=json", nil))
+	var list []struct {
+		Fingerprint string
+		Error       map[string]interface{}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("ServeHTTP json: %v in %s", err, rec.Body)
+	}
+	if len(list) != 2 || list[0].Fingerprint != Fingerprint(third) || list[0].Error["message"] != "third" {
+		t.Errorf("ServeHTTP json: got %s", rec.Body)
+	}
+	if got := r.String(); !strings.HasPrefix(got, `[{"time":`) {
+		t.Errorf("String(): got %q", got)
+	}
+}
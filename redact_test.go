@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSetFrameRedactor(t *testing.T) {
+	SetFrameRedactor(func(f Frame) (Frame, bool) {
+		switch funcname(f.name()) {
+		case "TestSetFrameRedactor":
+			return initpc, true
+		case "tRunner":
+			return f, true
+		}
+		return f, false
+	})
+	defer SetFrameRedactor(nil)
+
+	err := New("redacted")
+	testFormatRegexp(t, 0, err, "%+v", "redacted\n"+
+		"github.com/objenious/errors.init\n"+
+		"\t.+/github.com/objenious/errors/stack_test.go:9\n"+
+		"testing.tRunner\n"+
+		"\t.+/testing/testing.go:\\d+$")
+	if got := strings.Count(fmt.Sprintf("%+v", err), "\n"); got != 4 {
+		t.Errorf("%%+v printed %d lines, want 5", got+1)
+	}
+	st := GetStackTrace(err).StackTrace()
+	testFormatRegexp(t, 1, st, "%v", `^\[stack_test.go:9 testing.go:\d+\]$`)
+	testFormatRegexp(t, 2, st, "%+v", "\n"+
+		"github.com/objenious/errors.init\n"+
+		"\t.+/github.com/objenious/errors/stack_test.go:9\n"+
+		"testing.tRunner\n")
+
+	SetFrameRedactor(nil)
+	if got := strings.Count(fmt.Sprintf("%+v", err), "\n"); got <= 4 {
+		t.Errorf("%%+v printed %d lines after disabling redaction, want more than 5", got+1)
+	}
+}
@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"net/http"
+)
+
+// WithRefID annotates err with the reference ID id, a short identifier that
+// support can ask customers for, to find the error in the logs. It is
+// returned by RefID, included by UserMessage and by ToMap as "ref_id". If id
+// is empty, WithRefID generates one, unless err already has a reference ID,
+// in which case err is returned unchanged.
+// If err is nil, WithRefID returns nil.
+func WithRefID(err error, id string) error {
+	if err == nil {
+		return nil
+	}
+	if id == "" {
+		if RefID(err) != "" {
+			return err
+		}
+		id = newRefID()
+	}
+	return &withAttr{error: err, name: "ref_id", value: id}
+}
+
+// RefID returns the reference ID err was annotated with by WithRefID, or an
+// empty string.
+func RefID(err error) string {
+	id, _ := lookupAttr(err, "ref_id")
+	s, _ := id.(string)
+	return s
+}
+
+// newRefID returns a random reference ID of 8 characters.
+func newRefID() string {
+	var b [5]byte
+	_, _ = rand.Read(b[:])
+	return base32.StdEncoding.EncodeToString(b[:])
+}
+
+// WithUserMessage annotates err with a message that can be shown to users,
+// as returned by UserMessage, unlike the message of err that may reveal
+// internal details.
+// If err is nil, WithUserMessage returns nil.
+func WithUserMessage(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return &withAttr{error: err, name: "user_message", value: message}
+}
+
+// UserMessage returns a message describing err that can be shown to users:
+// the message set by WithUserMessage, or else the text of the HTTP status of
+// err (see HTTPStatus), defaulting to "Internal Server Error". It is
+// followed by the reference ID of err, if any, and prefixed with its code if
+// SetCodePrefix is enabled:
+//
+//	Device not found (reference: MFRGGZDF)
+//
+// If err is nil, UserMessage returns an empty string.
+func UserMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	msg, _ := lookupAttr(err, "user_message")
+	s, _ := msg.(string)
+	if s == "" {
+		status, ok := HTTPStatus(err)
+		if s = http.StatusText(status); !ok || s == "" {
+			s = http.StatusText(http.StatusInternalServerError)
+		}
+	}
+	if id := RefID(err); id != "" {
+		s += " (reference: " + id + ")"
+	}
+	return withCodePrefix(CodeOf(err), s)
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestWithRefID(t *testing.T) {
+	if got := WithRefID(nil, ""); got != nil {
+		t.Errorf("WithRefID(nil): got %#v, want nil", got)
+	}
+	if got := RefID(io.EOF); got != "" {
+		t.Errorf("RefID(io.EOF): got %q, want \"\"", got)
+	}
+	err := WithRefID(New("boom"), "")
+	id := RefID(err)
+	if !regexp.MustCompile(`^[A-Z2-7]{8}$`).MatchString(id) {
+		t.Errorf("RefID(): got %q, want 8 base32 characters", id)
+	}
+	if got := WithRefID(Wrap(err, "call"), ""); RefID(got) != id {
+		t.Errorf("WithRefID(): got %q, want the existing %q", RefID(got), id)
+	}
+	if got := RefID(WithRefID(err, "CUSTOM")); got != "CUSTOM" {
+		t.Errorf("WithRefID(CUSTOM): got %q", got)
+	}
+	data, _ := json.Marshal(ToMap(err))
+	if remote, _ := FromJSON(data); remote == nil || RefID(Wrap(remote, "call")) != id {
+		t.Errorf("ToMap: got %s, want ref_id %q", data, id)
+	}
+}
+
+func TestUserMessage(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{New("connection string: password=secret"), "Internal Server Error"},
+		{WithKind(New("no row"), KindNotFound), "Not Found"},
+		{Wrap(WithUserMessage(WithKind(New("no row"), KindNotFound), "Device not found"), "get"), "Device not found"},
+		{WithRefID(WithUserMessage(io.EOF, "Device not found"), "MFRGGZDF"), "Device not found (reference: MFRGGZDF)"},
+	}
+	for i, tt := range tests {
+		if got := UserMessage(tt.err); got != tt.want {
+			t.Errorf("test %d: UserMessage(%v): got %q, want %q", i+1, tt.err, got, tt.want)
+		}
+	}
+}
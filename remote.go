@@ -0,0 +1,185 @@
+package errors
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// RemoteError is an error decoded from its serialized representation,
+// typically received from another service. It keeps the messages and stack
+// traces of the original chain, the remote frames being printed with a
+// "remote: " prefix by %+v so that they are not mistaken for local ones.
+type RemoteError struct {
+	// Message is the message of the original error.
+	Message string
+	// Stack holds the serialized frames of the original error, if any.
+	Stack []string
+	// Causes holds the decoded causes of the original error.
+	Causes []error
+	// Hops holds the services the error went through, see SetServiceName.
+	Hops []Hop
+	// Time is the time the original error was created, if recorded.
+	Time time.Time
+	// Kind is the kind of the original error, see WithKind.
+	Kind Kind
+	// Code is the code of the original error, see WithCode.
+	Code Code
+	// RefID is the reference ID of the original error, see WithRefID.
+	RefID string
+	// Fingerprint is the fingerprint of the original error, if it was
+	// decoded without its stack traces, see Fingerprint and FromCompact.
+	Fingerprint string
+	// Schema is the schema version of the representation the error was
+	// decoded from, 0 if it had none, see ToJSON.
+	Schema int
+}
+
+// FromMap rebuilds an error from the representation built by ToMap, for
+// instance after it has been decoded from JSON. Unknown keys are ignored, and
+// representations of a newer schema version (see ToJSON) are decoded as far
+// as they are understood: their invalid times and causes are skipped
+// instead of failing the decoding.
+func FromMap(m map[string]interface{}) (*RemoteError, error) {
+	schema := schemaOf(m)
+	e, err := fromMap(m, schema > SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	e.Schema = schema
+	return e, nil
+}
+
+// fromMap rebuilds an error from m, skipping invalid times and causes if
+// lenient.
+func fromMap(m map[string]interface{}, lenient bool) (*RemoteError, error) {
+	msg, ok := m["message"].(string)
+	if !ok {
+		return nil, goerrors.New("errors: missing message")
+	}
+	hops, err := hopsFromMap(m)
+	if err != nil {
+		return nil, err
+	}
+	e := &RemoteError{Message: msg, Hops: hops}
+	if kind, ok := m["kind"].(string); ok {
+		e.Kind = Kind(kind)
+	}
+	if code, ok := m["code"].(string); ok {
+		e.Code = Code(code)
+	}
+	if id, ok := m["ref_id"].(string); ok {
+		e.RefID = id
+	}
+	if fp, ok := m["fingerprint"].(string); ok {
+		e.Fingerprint = fp
+	}
+	if ts, ok := m["time"].(string); ok {
+		if e.Time, err = time.Parse(time.RFC3339Nano, ts); err != nil && !lenient {
+			return nil, err
+		}
+	}
+	switch frames := m["stack"].(type) {
+	case []string:
+		e.Stack = frames
+	case []interface{}:
+		for _, f := range frames {
+			if text, ok := f.(string); ok {
+				e.Stack = append(e.Stack, text)
+			}
+		}
+	}
+	var causes []interface{}
+	if cause, ok := m["cause"]; ok {
+		causes = []interface{}{cause}
+	} else if list, ok := m["causes"].([]interface{}); ok {
+		causes = list
+	}
+	for _, c := range causes {
+		cm, ok := c.(map[string]interface{})
+		if !ok {
+			if lenient {
+				continue
+			}
+			return nil, goerrors.New("errors: invalid cause")
+		}
+		cause, err := fromMap(cm, lenient)
+		if err != nil {
+			if lenient {
+				continue
+			}
+			return nil, err
+		}
+		e.Causes = append(e.Causes, cause)
+	}
+	return e, nil
+}
+
+// FromJSON decodes the JSON encoding of the representation built by ToMap,
+// as returned by ToJSON, see FromMap.
+func FromJSON(data []byte) (*RemoteError, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return FromMap(m)
+}
+
+func (e *RemoteError) Error() string { return e.Message }
+
+// Unwrap returns the causes of the original error.
+func (e *RemoteError) Unwrap() []error { return e.Causes }
+
+// Is reports whether target is the Kind or Code of the original error.
+func (e *RemoteError) Is(target error) bool {
+	switch t := target.(type) {
+	case Kind:
+		return t != KindUnknown && e.Kind == t
+	case Code:
+		return t != "" && e.Code == t
+	}
+	return false
+}
+
+// Format formats the error like the original error, its remote stack trace
+// printed with a "remote: " prefix.
+func (e *RemoteError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			msgs := make([]string, len(e.Causes))
+			for i, cause := range e.Causes {
+				if i > 0 {
+					_, _ = io.WriteString(s, "\n")
+				}
+				formatExtended(s, cause)
+				msgs[i] = cause.Error()
+			}
+			msg := e.Message
+			if len(msgs) == 1 {
+				msg = strings.TrimSuffix(msg, messageSeparator()+msgs[0])
+			}
+			if len(msgs) == 0 {
+				_, _ = io.WriteString(s, msg)
+			} else if msg != strings.Join(msgs, "\n") {
+				_, _ = fmt.Fprintf(s, "\n%s", msg)
+			}
+			for _, f := range e.Stack {
+				fn, file := f, ""
+				if i := strings.IndexByte(f, ' '); i >= 0 {
+					fn, file = f[:i], f[i+1:]
+				}
+				_, _ = fmt.Fprintf(s, "\nremote: %s\n\t%s", fn, file)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, e.Message)
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Message)
+	}
+}
@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestFromJSON(t *testing.T) {
+	original := Wrap(fmt.Errorf("decode: %w", New("bad payload")), "uplink")
+	data, err := json.Marshal(ToMap(original))
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("FromJSON: %v", err)
+	}
+	if remote.Error() != original.Error() {
+		t.Errorf("FromJSON: got message %q, want %q", remote, original)
+	}
+	if !reflect.DeepEqual(ToMap(remote), ToMap(original)) {
+		t.Errorf("ToMap(FromJSON(data)):\n got %v\nwant %v", ToMap(remote), ToMap(original))
+	}
+	if got := Cause(remote).Error(); got != "bad payload" {
+		t.Errorf("Cause(FromJSON(data)): got %q, want \"bad payload\"", got)
+	}
+
+	var target *RemoteError
+	if !goerrors.As(Wrap(remote, "call"), &target) || target != remote {
+		t.Errorf("errors.As(Wrap(remote), &target) = false")
+	}
+
+	for _, data := range []string{``, `[]`, `{}`, `{"message":1}`, `{"message":"x","cause":"y"}`, `{"message":"x","causes":[{}]}`} {
+		if _, err := FromJSON([]byte(data)); err == nil {
+			t.Errorf("FromJSON(%q): expected an error", data)
+		}
+	}
+}
+
+func TestFormatRemoteError(t *testing.T) {
+	remote := &RemoteError{
+		Message: "uplink: decode: bad payload",
+		Stack:   []string{"main.uplink /src/main.go:12", "main.main /src/main.go:5"},
+		Causes: []error{&RemoteError{
+			Message: "decode: bad payload",
+			Causes: []error{&RemoteError{
+				Message: "bad payload",
+				Stack:   []string{"main.decode /src/decode.go:3"},
+			}},
+		}},
+	}
+	tests := []struct {
+		err    error
+		format string
+		want   string
+	}{
+		{remote, "%s", "uplink: decode: bad payload"},
+		{remote, "%v", "uplink: decode: bad payload"},
+		{remote, "%q", `"uplink: decode: bad payload"`},
+		{remote, "%+v", "bad payload\n" +
+			"remote: main.decode\n" +
+			"\t/src/decode.go:3\n" +
+			"decode\n" +
+			"uplink\n" +
+			"remote: main.uplink\n" +
+			"\t/src/main.go:12\n" +
+			"remote: main.main\n" +
+			"\t/src/main.go:5"},
+		{&RemoteError{Message: "EOF\nunexpected EOF", Causes: []error{io.EOF, io.ErrUnexpectedEOF}}, "%+v", "EOF\nunexpected EOF"},
+	}
+	for i, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.err); got != tt.want {
+			t.Errorf("test %d: Sprintf(%q):\n got: %q\nwant: %q", i+1, tt.format, got, tt.want)
+		}
+	}
+}
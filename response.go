@@ -0,0 +1,147 @@
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxBodySnippet is the number of bytes of the body of responses kept by
+// FromResponse.
+const maxBodySnippet = 512
+
+// FromResponse returns an error describing resp, unless its status is 2xx or
+// resp is nil, in which case it returns nil. The error has the stack trace at the point
+// FromResponse was called, the kind matching the status (see KindOf), and
+// the fields "status", "method", "url" and "body". The query of the URL is
+// redacted, and the body is truncated to its first 512 bytes. Responses
+// with a Retry-After header, given in seconds, are retryable after it (see
+// RetryAfter), and responses with RateLimit or X-RateLimit headers are
+// annotated with their rate limit (see RateLimit).
+//
+// FromResponse reads the body of resp, and leaves it to the caller to close
+// it.
+func FromResponse(resp *http.Response) error {
+	if resp == nil || resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	var method, target string
+	if resp.Request != nil {
+		method = resp.Request.Method
+		if resp.Request.URL != nil {
+			target = redactURL(resp.Request.URL)
+		}
+	}
+	status := resp.Status
+	if status == "" {
+		status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	var err error = &withStack{
+		error:   goerrors.New(strings.TrimSpace(fmt.Sprintf("%s %s: %s", method, target, status))),
+		stack:   callers(),
+		created: timestamp(),
+	}
+	err = WithField(err, "status", resp.StatusCode)
+	err = WithField(err, "method", method)
+	err = WithField(err, "url", target)
+	if resp.Body != nil {
+		err = WithField(err, "body", bodySnippet(resp.Body))
+	}
+	if kind := statusKind(resp.StatusCode); kind != KindUnknown {
+		err = WithKind(err, kind)
+	}
+	if seconds, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+		err = WithRetryAfter(err, seconds)
+	}
+	if l, ok := parseRateLimit(resp.Header); ok {
+		err = &withAttr{error: err, name: "rate_limit", value: l}
+	}
+	return err
+}
+
+// redactURL returns u without password and with the values of its query
+// replaced by "REDACTED".
+func redactURL(u *url.URL) string {
+	redacted := *u
+	if redacted.User != nil {
+		redacted.User = url.User(redacted.User.Username())
+	}
+	if redacted.RawQuery != "" {
+		query := redacted.Query()
+		for key := range query {
+			query[key] = []string{"REDACTED"}
+		}
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.String()
+}
+
+// bodySnippet returns the first maxBodySnippet bytes of body, followed by
+// "..." if it is longer.
+func bodySnippet(body io.Reader) string {
+	snippet, _ := readSnippet(body, maxBodySnippet)
+	return snippet
+}
+
+// readSnippet returns the first limit bytes of body, followed by "..." if it
+// is longer, and the bytes it read from body.
+func readSnippet(body io.Reader, limit int) (string, []byte) {
+	read, _ := ioutil.ReadAll(io.LimitReader(body, int64(limit)+1))
+	if len(read) <= limit {
+		return string(read), read
+	}
+	data := read[:limit]
+	// do not cut a rune in half.
+	for i := 1; i < utf8.UTFMax; i++ {
+		if r, size := utf8.DecodeLastRune(data); r != utf8.RuneError || size != 1 {
+			break
+		}
+		data = data[:len(data)-1]
+	}
+	return string(data) + "...", read
+}
+
+// retryAfter parses the delay of a Retry-After header given in seconds.
+func retryAfter(header string) (time.Duration, bool) {
+	var seconds int
+	if _, err := fmt.Sscanf(header, "%d", &seconds); err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// statusKind returns the kind of the errors described by HTTP responses with
+// the given status.
+func statusKind(status int) Kind {
+	switch status {
+	case http.StatusBadRequest:
+		return KindInvalid
+	case http.StatusUnauthorized:
+		return KindUnauthenticated
+	case http.StatusForbidden:
+		return KindPermissionDenied
+	case http.StatusNotFound, http.StatusGone:
+		return KindNotFound
+	case http.StatusConflict:
+		return KindConflict
+	case http.StatusTooManyRequests:
+		return KindRateLimited
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return KindTimeout
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return KindUnavailable
+	}
+	switch {
+	case status >= 400 && status < 500:
+		return KindInvalid
+	case status >= 500 && status < 600:
+		return KindInternal
+	}
+	return KindUnknown
+}
@@ -0,0 +1,55 @@
+package errors
+
+import "time"
+
+// WithRetryable annotates err with whether the operation that failed can be
+// retried, as reported by IsRetryable.
+// If err is nil, WithRetryable returns nil.
+func WithRetryable(err error, retryable bool) error {
+	if err == nil {
+		return nil
+	}
+	return &withAttr{error: err, name: "retryable", value: retryable}
+}
+
+// WithRetryAfter annotates err as retryable after d, as reported by
+// IsRetryable and RetryAfter.
+// If err is nil, WithRetryAfter returns nil.
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &withAttr{error: WithRetryable(err, true), name: "retry_after", value: d}
+}
+
+// IsRetryable reports whether the operation that failed with err can be
+// retried. The outermost annotation of WithRetryable or WithRetryAfter in
+// the chain of err applies; without annotation, errors with a Temporary
+// method returning true, such as some net.Error, are retryable.
+func IsRetryable(err error) bool {
+	if retryable, ok := lookupAttr(err, "retryable"); ok {
+		return retryable.(bool)
+	}
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		if t, ok := err.(interface{ Temporary() bool }); ok && t.Temporary() {
+			return true
+		}
+		causes := causes(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return false
+}
+
+// RetryAfter returns the delay err was annotated with by WithRetryAfter, if
+// any.
+func RetryAfter(err error) (time.Duration, bool) {
+	d, ok := lookupAttr(err, "retry_after")
+	if !ok {
+		return 0, false
+	}
+	return d.(time.Duration), true
+}
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+type temporaryError bool
+
+func (e temporaryError) Error() string   { return "temporary" }
+func (e temporaryError) Temporary() bool { return bool(e) }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{io.EOF, false},
+		{WithRetryable(io.EOF, true), true},
+		{Wrap(WithRetryable(io.EOF, true), "read"), true},
+		{WithRetryable(WithRetryable(io.EOF, true), false), false},
+		{WithRetryAfter(io.EOF, time.Second), true},
+		{Wrap(temporaryError(true), "dial"), true},
+		{Wrap(temporaryError(false), "dial"), false},
+		{WithRetryable(temporaryError(true), false), false},
+	}
+	for i, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("test %d: IsRetryable(%v): got %t, want %t", i+1, tt.err, got, tt.want)
+		}
+	}
+	if got := WithRetryable(nil, true); got != nil {
+		t.Errorf("WithRetryable(nil): got %#v, want nil", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if got := WithRetryAfter(nil, time.Second); got != nil {
+		t.Errorf("WithRetryAfter(nil): got %#v, want nil", got)
+	}
+	if _, ok := RetryAfter(io.EOF); ok {
+		t.Errorf("RetryAfter(io.EOF): got a delay")
+	}
+	err := Wrap(WithRetryAfter(io.EOF, 2*time.Second), "call")
+	if d, ok := RetryAfter(err); !ok || d != 2*time.Second {
+		t.Errorf("RetryAfter: got %v, %t, want 2s", d, ok)
+	}
+	m := ToMap(err)["cause"].(map[string]interface{})
+	if m["retry_after"] != "2s" || m["retryable"] != true {
+		t.Errorf("ToMap: got %v, want retry_after and retryable", m)
+	}
+}
@@ -0,0 +1,42 @@
+package errors
+
+import "encoding/json"
+
+// SchemaVersion is the version of the representation of errors encoded by
+// ToJSON. It is incremented when the representation changes in a way older
+// decoders can't ignore; keys are added without incrementing it, as decoders
+// ignore unknown keys.
+const SchemaVersion = 1
+
+// ToJSON returns the JSON encoding of the representation of err built by
+// ToMap, with the schema version of the representation as "schema" at the
+// top level, so that FromJSON can tell representations of newer versions,
+// and the summary of err returned by Summary as "summary", for log pipelines
+// to index it while they store the rest.
+// If err is nil, ToJSON returns nil.
+func ToJSON(err error) ([]byte, error) {
+	m := ToMap(err)
+	if m == nil {
+		return nil, nil
+	}
+	CheckPolicy(err, "ToJSON")
+	m["schema"] = SchemaVersion
+	m["summary"] = Summary(err)
+	return json.Marshal(boundSize(m))
+}
+
+// schemaOf returns the schema version of the representation m, 0 if it has
+// none.
+func schemaOf(m map[string]interface{}) int {
+	switch v := m["schema"].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case uint64:
+		return int(v)
+	case int64:
+		return int(v)
+	}
+	return 0
+}
@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+func TestToJSON(t *testing.T) {
+	if data, err := ToJSON(nil); data != nil || err != nil {
+		t.Errorf("ToJSON(nil) = %s, %v, want nil", data, err)
+	}
+
+	data, err := ToJSON(Wrap(io.EOF, "read"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["schema"] != float64(SchemaVersion) || m["message"] != "read: EOF" {
+		t.Errorf("ToJSON() = %s", data)
+	}
+	if _, ok := m["cause"].(map[string]interface{})["schema"]; ok {
+		t.Errorf("ToJSON() has a schema version in causes: %s", data)
+	}
+	e, err := FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Schema != SchemaVersion || e.Message != "read: EOF" || len(e.Causes) != 1 {
+		t.Errorf("FromJSON() = %+v", e)
+	}
+
+	if _, err := ToJSON(WithField(io.EOF, "ch", make(chan int))); err == nil {
+		t.Errorf("ToJSON() with an unsupported field succeeded")
+	}
+}
+
+func TestFromJSONSchema(t *testing.T) {
+	tests := []struct {
+		data   string
+		schema int
+		causes int
+		valid  bool
+	}{
+		{`{"message": "boom", "new": {"key": 1}}`, 0, 0, true},
+		{`{"schema": 1, "message": "boom", "cause": {"message": "EOF", "new": [1]}}`, 1, 1, true},
+		{`{"schema": 1, "message": "boom", "time": 42}`, 1, 0, true},
+		{`{"schema": 1, "message": "boom", "time": "yesterday"}`, 1, 0, false},
+		{`{"schema": 1, "message": "boom", "causes": [1, {"message": "EOF"}]}`, 1, 0, false},
+		{`{"schema": 2, "message": "boom", "time": "yesterday"}`, 2, 0, true},
+		{`{"schema": 2, "message": "boom", "causes": [1, {"message": "EOF"}, {"text": "gone"}]}`, 2, 1, true},
+		{`{"schema": 2, "text": "boom"}`, 0, 0, false},
+	}
+	for _, tt := range tests {
+		e, err := FromJSON([]byte(tt.data))
+		if (err == nil) != tt.valid {
+			t.Errorf("FromJSON(%s): got error %v, want valid %t", tt.data, err, tt.valid)
+			continue
+		}
+		if err == nil && (e.Schema != tt.schema || len(e.Causes) != tt.causes || e.Message != "boom") {
+			t.Errorf("FromJSON(%s) = %+v", tt.data, e)
+		}
+	}
+}
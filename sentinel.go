@@ -0,0 +1,42 @@
+package errors
+
+// Sentinel returns an error with the supplied message, intended for
+// package-level values such as
+//
+//	var ErrNotFound = errors.Sentinel("not found")
+//
+// Unlike New, Sentinel does not record a stack trace: the stack of a
+// package-level value only points at the package initialisation. A stack
+// trace is recorded when the sentinel is returned through Wrap, Wrapf or
+// WithStack. Sentinels are compared by identity, and returning one does not
+// allocate.
+func Sentinel(message string) error {
+	return &sentinel{msg: message}
+}
+
+type sentinel struct {
+	msg string
+}
+
+func (s *sentinel) Error() string {
+	return s.msg
+}
+
+// StringError is an error whose value is its message. Unlike Sentinel, it
+// can be declared as a constant:
+//
+//	const ErrClosed = errors.StringError("closed")
+//
+// StringErrors with the same message are equal, and can be wrapped and
+// formatted like any other error.
+type StringError string
+
+func (e StringError) Error() string {
+	return string(e)
+}
+
+// Is reports whether target is a StringError with the same message.
+func (e StringError) Is(target error) bool {
+	t, ok := target.(StringError)
+	return ok && t == e
+}
@@ -0,0 +1,70 @@
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"testing"
+)
+
+var errSentinel = Sentinel("sentinel")
+
+func returnSentinel() error {
+	return errSentinel
+}
+
+func TestSentinel(t *testing.T) {
+	if got, want := errSentinel.Error(), "sentinel"; got != want {
+		t.Errorf("Sentinel.Error(): got %q, want %q", got, want)
+	}
+	if errSentinel == Sentinel("sentinel") {
+		t.Errorf("Sentinels with the same message must not be equal")
+	}
+	if GetStackTrace(errSentinel) != nil {
+		t.Errorf("Sentinel recorded a stack trace")
+	}
+	if got := fmt.Sprintf("%+v", errSentinel); got != "sentinel" {
+		t.Errorf("Sentinel %%+v: got %q, want %q", got, "sentinel")
+	}
+	if allocs := testing.AllocsPerRun(100, func() { _ = returnSentinel() }); allocs != 0 {
+		t.Errorf("returning a sentinel allocates %v times, want 0", allocs)
+	}
+
+	for _, err := range []error{WithStack(errSentinel), Wrap(errSentinel, "wrapped"), Wrapf(WithStack(errSentinel), "wrapped %d", 1)} {
+		if !goerrors.Is(err, errSentinel) {
+			t.Errorf("errors.Is(%v, errSentinel) = false", err)
+		}
+		if Cause(err) != errSentinel {
+			t.Errorf("Cause(%v) = %v, want errSentinel", err, Cause(err))
+		}
+		if GetStackTrace(err) == nil {
+			t.Errorf("%v: stack trace not recorded", err)
+		}
+	}
+}
+
+const errClosed = StringError("closed")
+
+func TestStringError(t *testing.T) {
+	if got, want := errClosed.Error(), "closed"; got != want {
+		t.Errorf("StringError.Error(): got %q, want %q", got, want)
+	}
+	if !goerrors.Is(Wrap(errClosed, "write"), StringError("closed")) {
+		t.Errorf("errors.Is(Wrap(errClosed), StringError(\"closed\")) = false")
+	}
+	if goerrors.Is(Wrap(errClosed, "write"), StringError("open")) {
+		t.Errorf("errors.Is(Wrap(errClosed), StringError(\"open\")) = true")
+	}
+	if Cause(WithStack(errClosed)) != errClosed {
+		t.Errorf("Cause(WithStack(errClosed)) != errClosed")
+	}
+	if got, want := fmt.Sprintf("%v", Wrap(errClosed, "write")), "write: closed"; got != want {
+		t.Errorf("Wrap(errClosed) %%v: got %q, want %q", got, want)
+	}
+	testFormatRegexp(t, 0, Wrap(errClosed, "write"), "%+v", "closed\n"+
+		"write\n"+
+		"github.com/objenious/errors.TestStringError\n"+
+		"\t.+/github.com/objenious/errors/sentinel_test.go:63")
+	if allocs := testing.AllocsPerRun(100, func() { _ = error(errClosed) }); allocs != 0 {
+		t.Errorf("converting a StringError allocates %v times, want 0", allocs)
+	}
+}
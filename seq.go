@@ -0,0 +1,45 @@
+package errors
+
+// Seq runs sequential steps until one of them fails, see NewSeq.
+type Seq struct {
+	err error
+}
+
+// NewSeq returns a Seq, to run sequential steps without checking the error
+// of each of them:
+//
+//	s := errors.NewSeq()
+//	s.Do("decode", func() error { return decode(payload, &msg) })
+//	s.Do("validate", func() error { return msg.Validate() })
+//	s.Do("store", func() error { return store(ctx, msg) })
+//	return s.Err() // "validate: ..."
+//
+// The zero Seq is ready to use as well.
+func NewSeq() *Seq {
+	return &Seq{}
+}
+
+// Do calls f, unless a previous step failed. If f returns an error, it is
+// wrapped with the message label and the stack trace at the point Do was
+// called, and has the field "step", the label.
+func (s *Seq) Do(label string, f func() error) {
+	if s.err != nil {
+		return
+	}
+	err := f()
+	if err == nil {
+		return
+	}
+	s.err = WithField(&withStack{
+		error:   err,
+		stack:   callers(),
+		created: timestamp(),
+		msg:     label,
+		wrapped: true,
+	}, "step", label)
+}
+
+// Err returns the error of the step that failed, or nil.
+func (s *Seq) Err() error {
+	return s.err
+}
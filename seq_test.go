@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestSeq(t *testing.T) {
+	var ran []string
+	step := func(label string, err error) func() error {
+		return func() error {
+			ran = append(ran, label)
+			return err
+		}
+	}
+
+	s := NewSeq()
+	s.Do("decode", step("decode", nil))
+	s.Do("validate", step("validate", io.ErrUnexpectedEOF))
+	s.Do("store", step("store", nil))
+	err := s.Err()
+
+	if fmt.Sprint(ran) != "[decode validate]" {
+		t.Errorf("steps run: %v, want [decode validate]", ran)
+	}
+	if got, want := err.Error(), "validate: unexpected EOF"; got != want {
+		t.Errorf("Err() = %q, want %q", got, want)
+	}
+	if v, _ := Field(err, "step"); v != "validate" {
+		t.Errorf("Field(step) = %v, want validate", v)
+	}
+	if got := fmt.Sprintf("%v", GetStackTrace(err).StackTrace()[0]); got != "seq_test.go:20" {
+		t.Errorf("stack starts at %s, want seq_test.go:20", got)
+	}
+
+	var zero Seq
+	zero.Do("decode", step("decode", nil))
+	if err := zero.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
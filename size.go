@@ -0,0 +1,192 @@
+package errors
+
+import (
+	"encoding/json"
+	"sort"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// truncatedMarker marks the strings shortened and the lists cut by
+// SetMaxErrorSize.
+const truncatedMarker = "(truncated)"
+
+// maxErrorSize is the bound of SetMaxErrorSize, 0 if disabled.
+var maxErrorSize int64
+
+// SetMaxErrorSize bounds the size in bytes of the representations of errors
+// encoded by ToJSON, ToYAML and ToCBOR, as measured in JSON, protecting log
+// pipelines from pathological errors of megabytes. The representations of
+// larger errors are cut down, by halving the limits of the length of their
+// strings, the number of frames of their stack traces, of the elements of
+// their lists and of the entries of their maps, and the depth of their
+// chains, until they fit or the limits can't be reduced further. Shortened
+// strings end with "... (truncated)", cut lists with a "(truncated)" element,
+// or a level with that message for lists of causes, and levels whose stack
+// trace, maps or causes were cut have "truncated": true, as has the
+// outermost level of every representation cut down. Maps keep their first
+// entries in the order of their keys.
+// A size of 0, the default, or less disables the bound.
+func SetMaxErrorSize(n int) {
+	if n < 0 {
+		n = 0
+	}
+	atomic.StoreInt64(&maxErrorSize, int64(n))
+}
+
+// sizeLimits are the limits applied to a representation by boundSize.
+type sizeLimits struct {
+	strings int // length of strings
+	items   int // number of frames, of list elements and of map entries
+	depth   int // depth of the chain
+}
+
+// Initial limits of boundSize, and their minimums.
+var (
+	initialLimits = sizeLimits{strings: 4096, items: 64, depth: 32}
+	minimumLimits = sizeLimits{strings: 32, items: 1, depth: 1}
+)
+
+// boundSize returns m, or a copy of it cut down to the size set by
+// SetMaxErrorSize.
+func boundSize(m map[string]interface{}) map[string]interface{} {
+	max := int(atomic.LoadInt64(&maxErrorSize))
+	if max <= 0 || m == nil {
+		return m
+	}
+	size, ok := jsonSize(m)
+	if !ok || size <= max {
+		return m
+	}
+	l := initialLimits
+	for {
+		b := shrinkLevel(m, l, 0)
+		b["truncated"] = true
+		if size, _ := jsonSize(b); size <= max || l == minimumLimits {
+			return b
+		}
+		l = sizeLimits{
+			strings: halve(l.strings, minimumLimits.strings),
+			items:   halve(l.items, minimumLimits.items),
+			depth:   halve(l.depth, minimumLimits.depth),
+		}
+	}
+}
+
+// halve returns n halved, but not below min.
+func halve(n, min int) int {
+	if n /= 2; n < min {
+		return min
+	}
+	return n
+}
+
+// jsonSize returns the length of the JSON encoding of v.
+func jsonSize(v interface{}) (int, bool) {
+	data, err := json.Marshal(v)
+	return len(data), err == nil
+}
+
+// shrinkLevel returns a copy of m, the representation of the level at the
+// given depth of a chain, cut down to the limits l.
+func shrinkLevel(m map[string]interface{}, l sizeLimits, depth int) map[string]interface{} {
+	shrunk := make(map[string]interface{}, len(m))
+	var cut bool
+	for k, v := range m {
+		switch k {
+		case "cause", "causes", "warnings":
+			if depth+1 >= l.depth {
+				shrunk["truncated"] = true
+				continue
+			}
+			shrunk[k] = shrinkValue(v, l, func(v interface{}) interface{} {
+				if level, ok := v.(map[string]interface{}); ok {
+					return shrinkLevel(level, l, depth+1)
+				}
+				return shrinkValue(v, l, nil, &cut)
+			}, &cut)
+		case "hops":
+			shrunk[k] = v
+		default:
+			shrunk[k] = shrinkValue(v, l, nil, &cut)
+		}
+	}
+	if cut {
+		shrunk["truncated"] = true
+	}
+	return shrunk
+}
+
+// shrinkValue returns a copy of v cut down to the limits l, setting cut if
+// entries of a map or frames of a stack trace are dropped, which have no
+// element to mark them. If item is not nil, v is a list whose elements are
+// shrunk by item.
+func shrinkValue(v interface{}, l sizeLimits, item func(interface{}) interface{}, cut *bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if item != nil {
+			return item(v)
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		if len(keys) > l.items {
+			sort.Strings(keys)
+			keys = keys[:l.items]
+			*cut = true
+		}
+		m := make(map[string]interface{}, len(keys))
+		for _, k := range keys {
+			m[k] = shrinkValue(v[k], l, nil, cut)
+		}
+		return m
+	case []interface{}:
+		n := len(v)
+		if n > l.items {
+			n = l.items
+		}
+		list := make([]interface{}, n, n+1)
+		for i := range list {
+			if item != nil {
+				list[i] = item(v[i])
+			} else {
+				list[i] = shrinkValue(v[i], l, nil, cut)
+			}
+		}
+		if n < len(v) && item != nil {
+			list = append(list, map[string]interface{}{"message": truncatedMarker})
+		} else if n < len(v) {
+			list = append(list, truncatedMarker)
+		}
+		return list
+	case []string:
+		n := len(v)
+		if n > l.items {
+			n = l.items
+		}
+		list := make([]string, n)
+		for i := range list {
+			list[i] = shrinkString(v[i], l.strings)
+		}
+		if n < len(v) {
+			*cut = true
+		}
+		return list
+	case string:
+		return shrinkString(v, l.strings)
+	}
+	return v
+}
+
+// shrinkString returns s, or its first max bytes followed by
+// "... (truncated)" if it is longer, without cutting a rune in half.
+func shrinkString(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max] + "... " + truncatedMarker
+}
@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSetMaxErrorSize(t *testing.T) {
+	defer SetMaxErrorSize(0)
+	var err error = New(strings.Repeat("x", 10000))
+	for i := 0; i < 100; i++ {
+		err = Wrap(WithField(err, "payload", strings.Repeat("y", 1000)), "level")
+	}
+	data, _ := ToJSON(err)
+	if len(data) < 1000000 {
+		t.Fatalf("ToJSON() is %d bytes long", len(data))
+	}
+
+	SetMaxErrorSize(16 << 10)
+	data, _ = ToJSON(err)
+	if len(data) > 16<<10 {
+		t.Errorf("ToJSON() is %d bytes long, want at most %d", len(data), 16<<10)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["truncated"] != true || m["schema"] != float64(SchemaVersion) {
+		t.Errorf("ToJSON() = %s", data)
+	}
+	if msg := m["message"].(string); !strings.HasSuffix(msg, "... (truncated)") {
+		t.Errorf("message = %q", msg)
+	}
+	if _, err := FromJSON(data); err != nil {
+		t.Errorf("FromJSON() = %v", err)
+	}
+	if got := len(ToYAML(err)); got > 32<<10 {
+		t.Errorf("ToYAML() is %d bytes long", got)
+	}
+	if got := len(ToCBOR(err)); got > 16<<10 {
+		t.Errorf("ToCBOR() is %d bytes long", got)
+	}
+	if _, err := FromCBOR(ToCBOR(err)); err != nil {
+		t.Errorf("FromCBOR() = %v", err)
+	}
+
+	// small errors are left as they are.
+	data, _ = ToJSON(New("boom"))
+	if strings.Contains(string(data), "truncated") {
+		t.Errorf("ToJSON() = %s", data)
+	}
+}
+
+func TestBoundSizeCauses(t *testing.T) {
+	defer SetMaxErrorSize(0)
+	errs := make([]error, 200)
+	for i := range errs {
+		errs[i] = New(strings.Repeat("z", 100))
+	}
+	SetMaxErrorSize(4 << 10)
+	data, _ := ToJSON(Join(errs...))
+	if len(data) > 4<<10 {
+		t.Errorf("ToJSON() is %d bytes long", len(data))
+	}
+	r, err := FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	causes := r.Unwrap()
+	if last := causes[len(causes)-1]; last.Error() != "(truncated)" {
+		t.Errorf("last cause = %q", last)
+	}
+}
+
+func TestShrinkString(t *testing.T) {
+	if got := shrinkString("éé", 3); got != "é... (truncated)" {
+		t.Errorf("shrinkString() = %q", got)
+	}
+	if got := shrinkString("abc", 3); got != "abc" {
+		t.Errorf("shrinkString() = %q", got)
+	}
+}
+
+func TestShrinkLevel(t *testing.T) {
+	m := map[string]interface{}{
+		"message": "boom",
+		"stack":   []string{"a", "b", "c"},
+		"fields":  map[string]interface{}{"cause": "field", "a": 1, "b": 2},
+		"cause":   map[string]interface{}{"message": "EOF", "cause": map[string]interface{}{"message": "deep"}},
+	}
+	got := shrinkLevel(m, sizeLimits{strings: 32, items: 2, depth: 2}, 0)
+	if stack := got["stack"].([]string); len(stack) != 2 || stack[1] != "b" {
+		t.Errorf("stack = %q", stack)
+	}
+	if fields := got["fields"].(map[string]interface{}); len(fields) != 2 || fields["a"] != 1 || fields["b"] != 2 {
+		t.Errorf("fields = %v", fields)
+	}
+	cause := got["cause"].(map[string]interface{})
+	if _, ok := cause["cause"]; ok || cause["truncated"] != true || got["truncated"] != true {
+		t.Errorf("shrinkLevel() = %v", got)
+	}
+
+	// levels left as they are are not marked.
+	got = shrinkLevel(map[string]interface{}{"message": "boom", "stack": []string{"a"}}, sizeLimits{strings: 32, items: 2, depth: 2}, 0)
+	if _, ok := got["truncated"]; ok {
+		t.Errorf("shrinkLevel() = %v", got)
+	}
+}
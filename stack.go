@@ -1,20 +1,26 @@
 package errors
 
 import (
-	goerrors "errors"
 	"fmt"
 	"io"
 	"path"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
-// GetStackTrace returns the stack trace if exists
+// GetStackTrace returns the stack trace if exists.
+// It is the deepest stack trace in the chain of err. For errors wrapping
+// several errors, the first of them carrying a stack trace is used.
 func GetStackTrace(err error) *stack {
-	cause := goerrors.Unwrap(err)
-	if cause != nil {
-		st := GetStackTrace(cause)
+	return getStackTrace(acyclic(err))
+}
+
+func getStackTrace(err error) *stack {
+	for _, cause := range causes(err) {
+		st := getStackTrace(cause)
 		if st != nil {
 			return st
 		}
@@ -65,6 +71,16 @@ func (f Frame) name() string {
 	return fn.Name()
 }
 
+// Package returns the import path of the package of the function of f, or
+// an empty string if unknown.
+func (f Frame) Package() string {
+	fn := runtime.FuncForPC(f.pc())
+	if fn == nil {
+		return ""
+	}
+	return pkgname(fn.Name())
+}
+
 // Format formats the frame according to the fmt.Formatter interface.
 //
 //    %s    source file
@@ -77,14 +93,21 @@ func (f Frame) name() string {
 //    %+s   function name and path of source file relative to the compile time
 //          GOPATH separated by \n\t (<funcname>\n\t<path>)
 //    %+v   equivalent to %+s:%d
+//
+// The output of %+s and %+v depends on the mode, see SetMode.
 func (f Frame) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 's':
 		switch {
 		case s.Flag('+'):
-			io.WriteString(s, f.name())
+			m := currentMode()
+			if m.color {
+				io.WriteString(s, colorFunc+f.name()+colorReset)
+			} else {
+				io.WriteString(s, f.name())
+			}
 			io.WriteString(s, "\n\t")
-			io.WriteString(s, f.file())
+			io.WriteString(s, f.displayFile(m))
 		default:
 			io.WriteString(s, path.Base(f.file()))
 		}
@@ -93,20 +116,77 @@ func (f Frame) Format(s fmt.State, verb rune) {
 	case 'n':
 		io.WriteString(s, funcname(f.name()))
 	case 'v':
+		if s.Flag('+') {
+			f.formatLong(s, currentMode())
+			return
+		}
 		f.Format(s, 's')
 		io.WriteString(s, ":")
 		f.Format(s, 'd')
 	}
 }
 
+// formatLong prints f as %+v does, in mode m.
+func (f Frame) formatLong(w io.Writer, m modeSettings) {
+	if m.color {
+		io.WriteString(w, colorFunc+f.name()+colorReset)
+	} else {
+		io.WriteString(w, f.name())
+	}
+	io.WriteString(w, "\n\t")
+	io.WriteString(w, f.displayFile(m))
+	io.WriteString(w, ":")
+	writeLine(w, f.line())
+	if m.sources {
+		if src, ok := sourceLine(f.file(), f.line()); ok {
+			io.WriteString(w, "\n\t\t")
+			io.WriteString(w, src)
+		}
+	}
+}
+
+// writeLine writes the line number n to w one digit at a time, as the
+// substrings of a constant string are not allocated, unlike strconv.Itoa
+// for numbers of more than two digits.
+func writeLine(w io.Writer, n int) {
+	if n < 0 {
+		n = 0
+	}
+	if n >= 10 {
+		writeLine(w, n/10)
+	}
+	const digits = "0123456789"
+	io.WriteString(w, digits[n%10:n%10+1])
+}
+
 // MarshalText formats a stacktrace Frame as a text string. The output is the
 // same as that of fmt.Sprintf("%+v", f), but without newlines or tabs.
 func (f Frame) MarshalText() ([]byte, error) {
+	return []byte(f.text(currentMode())), nil
+}
+
+// ShortFrame is a Frame formatted and marshaled as its function name only,
+// without the import path of its package, as %n does: TestFormatNew rather
+// than github.com/objenious/errors.TestFormatNew.
+type ShortFrame Frame
+
+// String returns the function name of f.
+func (f ShortFrame) String() string {
+	return funcname(Frame(f).name())
+}
+
+// MarshalText returns the function name of f.
+func (f ShortFrame) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// text returns the text of MarshalText in mode m.
+func (f Frame) text(m modeSettings) string {
 	name := f.name()
 	if name == "unknown" {
-		return []byte(name), nil
+		return name
 	}
-	return []byte(fmt.Sprintf("%s %s:%d", name, f.file(), f.line())), nil
+	return fmt.Sprintf("%s %s:%d", name, f.displayFile(m), f.line())
 }
 
 // StackTrace is stack of Frames from innermost (newest) to outermost (oldest).
@@ -125,7 +205,7 @@ func (st StackTrace) Format(s fmt.State, verb rune) {
 	case 'v':
 		switch {
 		case s.Flag('+'):
-			for _, f := range st {
+			for _, f := range st.redacted() {
 				io.WriteString(s, "\n")
 				f.Format(s, verb)
 			}
@@ -143,7 +223,7 @@ func (st StackTrace) Format(s fmt.State, verb rune) {
 // Frame, only valid when called with '%s' or '%v'.
 func (st StackTrace) formatSlice(s fmt.State, verb rune) {
 	io.WriteString(s, "[")
-	for i, f := range st {
+	for i, f := range st.redacted() {
 		if i > 0 {
 			io.WriteString(s, " ")
 		}
@@ -152,6 +232,56 @@ func (st StackTrace) formatSlice(s fmt.State, verb rune) {
 	io.WriteString(s, "]")
 }
 
+// frameRedactor holds the function set by SetFrameRedactor.
+var frameRedactor atomic.Value
+
+type redactor struct {
+	f func(Frame) (Frame, bool)
+}
+
+// SetFrameRedactor sets a function applied to each Frame of a stack trace
+// before it is printed or serialized. It returns the Frame to use instead, or
+// false to drop the frame altogether, e.g. to hide internal packages before
+// errors leave the process. A nil function disables redaction.
+func SetFrameRedactor(f func(Frame) (Frame, bool)) {
+	frameRedactor.Store(redactor{f})
+}
+
+// redacted returns the frames of st as modified by the frame redactor and
+// the mode, see SetMode.
+func (st StackTrace) redacted() StackTrace {
+	r, _ := frameRedactor.Load().(redactor)
+	return st.redactedWith(r)
+}
+
+// redactedWith returns the frames of st as modified by r and the mode.
+func (st StackTrace) redactedWith(r redactor) StackTrace {
+	m := currentMode()
+	if r.f == nil && !m.dropRuntime && (m.maxFrames == 0 || len(st) <= m.maxFrames) {
+		return st
+	}
+	frames := make(StackTrace, 0, len(st))
+	for _, f := range st {
+		if m.maxFrames > 0 && len(frames) == m.maxFrames {
+			break
+		}
+		if m.dropRuntime && strings.HasPrefix(f.name(), "runtime.") {
+			continue
+		}
+		if m.mainOnly && !inMainModule(f) {
+			continue
+		}
+		if r.f != nil {
+			var ok bool
+			if f, ok = r.f(f); !ok {
+				continue
+			}
+		}
+		frames = append(frames, f)
+	}
+	return frames
+}
+
 // stack represents a stack of program counters.
 type stack []uintptr
 
@@ -160,8 +290,7 @@ func (s *stack) Format(st fmt.State, verb rune) {
 	case 'v':
 		switch {
 		case st.Flag('+'):
-			for _, pc := range *s {
-				f := Frame(pc)
+			for _, f := range s.StackTrace().redacted() {
 				fmt.Fprintf(st, "\n%+v", f)
 			}
 		}
@@ -176,11 +305,53 @@ func (s *stack) StackTrace() StackTrace {
 	return f
 }
 
+// depth is the maximum number of frames recorded in a stack.
+const depth = 32
+
+// stackPooling is non-zero when callers should capture into pooled buffers.
+var stackPooling int32
+
+// stackPool holds the scratch buffers used to capture program counters.
+var stackPool = sync.Pool{
+	New: func() interface{} {
+		return new([depth]uintptr)
+	},
+}
+
+// SetStackPooling enables or disables the capture of stack traces into
+// pooled buffers. When enabled, only the captured program counters are
+// copied into the error, which reduces GC pressure in programs creating
+// many short-lived errors. It is disabled by default.
+func SetStackPooling(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&stackPooling, v)
+}
+
+// callers returns the stack trace of the caller of the function calling
+// callers.
 func callers() *stack {
-	const depth = 32
+	return capture(4)
+}
+
+// capture returns the stack trace from the skip-th frame, 0 identifying the
+// frame of runtime.Callers.
+func capture(skip int) *stack {
+	if atomic.LoadInt32(&stackPooling) != 0 {
+		pcs := stackPool.Get().(*[depth]uintptr)
+		n := runtime.Callers(skip, pcs[:])
+		st := make(stack, n)
+		copy(st, pcs[:n])
+		stackPool.Put(pcs)
+		countSite(st)
+		return &st
+	}
 	var pcs [depth]uintptr
-	n := runtime.Callers(3, pcs[:])
+	n := runtime.Callers(skip, pcs[:])
 	var st stack = pcs[0:n]
+	countSite(st)
 	return &st
 }
 
@@ -191,3 +362,14 @@ func funcname(name string) string {
 	i = strings.Index(name, ".")
 	return name[i+1:]
 }
+
+// pkgname removes the function name from a function name
+// qualified by its import path, unescaping the dots of the last
+// element of the path.
+func pkgname(name string) string {
+	i := strings.LastIndex(name, "/")
+	if j := strings.Index(name[i+1:], "."); j >= 0 {
+		name = name[:i+1+j]
+	}
+	return strings.Replace(name, "%2e", ".", -1)
+}
@@ -245,3 +245,18 @@ func caller() Frame {
 	frame, _ := frames.Next()
 	return Frame(frame.PC)
 }
+
+func TestStackPooling(t *testing.T) {
+	SetStackPooling(true)
+	defer SetStackPooling(false)
+
+	errs := []error{New("first"), New("second")}
+	for i, err := range errs {
+		st := GetStackTrace(err).StackTrace()
+		testFormatRegexp(t, i, st[0], "%+v", "github.com/objenious/errors.TestStackPooling\n"+
+			"\t.+/github.com/objenious/errors/stack_test.go:253")
+	}
+	if p0, p1 := *GetStackTrace(errs[0]), *GetStackTrace(errs[1]); &p0[0] == &p1[0] {
+		t.Errorf("stacks share their buffer")
+	}
+}
@@ -0,0 +1,45 @@
+package errors
+
+import "hash/fnv"
+
+// Equal reports whether st and other hold the same frames, in the same order.
+func (st StackTrace) Equal(other StackTrace) bool {
+	if len(st) != len(other) {
+		return false
+	}
+	for i, f := range st {
+		if f != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns a hash of the frames of st, the same for equal stack traces
+// within a process, to index errors by stack trace in aggregation layers.
+// It depends on the program counters of the frames, so unlike Fingerprint it
+// must not be compared across processes.
+func (st StackTrace) Hash() uint64 {
+	h := fnv.New64a()
+	var b [8]byte
+	for _, f := range st {
+		for i := range b {
+			b[i] = byte(f >> (8 * uint(i)))
+		}
+		h.Write(b[:])
+	}
+	return h.Sum64()
+}
+
+// CommonPrefix returns the call path shared by a and b, from their outermost
+// frame: as stack traces list frames from the innermost, it is the longest
+// common tail of a and b, returned as a subslice of a. Errors with the same
+// common prefix arose from the same caller, e.g. to report them as similar
+// errors below it.
+func CommonPrefix(a, b StackTrace) StackTrace {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return a[len(a)-n:]
+}
@@ -0,0 +1,44 @@
+package errors
+
+import "testing"
+
+func stackOf(err error) StackTrace {
+	return err.(*withStack).StackTrace()
+}
+
+func similar() []error {
+	var errs []error
+	for i := 0; i < 2; i++ {
+		errs = append(errs, New("boom"))
+	}
+	return append(errs, New("boom"))
+}
+
+func TestStackTraceEqual(t *testing.T) {
+	errs := similar()
+	a, b, c := stackOf(errs[0]), stackOf(errs[1]), stackOf(errs[2])
+	if !a.Equal(b) || a.Hash() != b.Hash() {
+		t.Errorf("stack traces from the same call site differ: %v, %v", a, b)
+	}
+	if a.Equal(c) || a.Hash() == c.Hash() {
+		t.Errorf("stack traces from different call sites are equal: %v, %v", a, c)
+	}
+	if a.Equal(a[1:]) || !StackTrace(nil).Equal(StackTrace{}) {
+		t.Errorf("Equal does not compare lengths")
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	errs := similar()
+	a, c := stackOf(errs[0]), stackOf(errs[2])
+	got := CommonPrefix(a, c)
+	if !got.Equal(a[1:]) {
+		t.Errorf("CommonPrefix = %v, want %v", got, a[1:])
+	}
+	if got := CommonPrefix(a, a); !got.Equal(a) {
+		t.Errorf("CommonPrefix(a, a) = %v, want %v", got, a)
+	}
+	if got := CommonPrefix(a, stackOf(New("boom"))[:0]); len(got) != 0 {
+		t.Errorf("CommonPrefix(a, empty) = %v, want empty", got)
+	}
+}
@@ -0,0 +1,48 @@
+package errors
+
+// PipelineStage tags the errors of a stage of a pipeline with its name, see
+// Stage.
+type PipelineStage struct {
+	name string
+}
+
+// Stage returns a PipelineStage tagging errors with the stage name, so that
+// the failures of the stages of a pipeline are tagged consistently:
+//
+//	var decode = errors.Stage("decode")
+//
+//	if err := json.Unmarshal(data, &msg); err != nil {
+//		return decode.Wrap(err)
+//	}
+//
+// The stages of an error are returned by Stages, and included by ToMap as
+// "stage".
+func Stage(name string) PipelineStage {
+	return PipelineStage{name: name}
+}
+
+// Name returns the name of s
+func (s PipelineStage) Name() string { return s.name }
+
+// Wrap tags err with the name of s, without changing its message.
+// If err is nil, Wrap returns nil.
+func (s PipelineStage) Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withAttr{error: err, name: "stage", value: s.name}
+}
+
+// Stages returns the names of the stages err was tagged with, from the
+// outermost to the innermost.
+func Stages(err error) []string {
+	values := lookupAttrs(err, "stage")
+	if len(values) == 0 {
+		return nil
+	}
+	stages := make([]string, len(values))
+	for i, v := range values {
+		stages[i] = v.(string)
+	}
+	return stages
+}
@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestStage(t *testing.T) {
+	decode, store := Stage("decode"), Stage("store")
+	if decode.Name() != "decode" {
+		t.Errorf("Name() = %q, want decode", decode.Name())
+	}
+	if err := decode.Wrap(nil); err != nil {
+		t.Errorf("Wrap(nil) = %v, want nil", err)
+	}
+
+	err := decode.Wrap(io.EOF)
+	if err.Error() != "EOF" || Cause(err) != io.EOF {
+		t.Errorf("Wrap(io.EOF) = %v, cause %v", err, Cause(err))
+	}
+	if got := ToMap(err)["stage"]; got != "decode" {
+		t.Errorf("ToMap()[stage] = %v, want decode", got)
+	}
+
+	tests := []struct {
+		err  error
+		want []string
+	}{
+		{nil, nil},
+		{io.EOF, nil},
+		{err, []string{"decode"}},
+		{store.Wrap(Wrap(err, "import")), []string{"store", "decode"}},
+	}
+	for _, tt := range tests {
+		if got := Stages(tt.err); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Stages(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
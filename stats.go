@@ -0,0 +1,71 @@
+package errors
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// statsEnabled is non-zero when errors are counted per call site.
+var statsEnabled int32
+
+// sites holds the counters of the call sites, by program counter.
+var sites sync.Map
+
+// SetStats enables or disables counting the errors created or wrapped by
+// this package per call site, as returned by Stats, so that long-running
+// services can expose their top error origins. It is disabled by default.
+func SetStats(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&statsEnabled, v)
+}
+
+// countSite counts an error created with the stack st, if stats are enabled.
+func countSite(st stack) {
+	if atomic.LoadInt32(&statsEnabled) == 0 || len(st) == 0 {
+		return
+	}
+	c, ok := sites.Load(st[0])
+	if !ok {
+		c, _ = sites.LoadOrStore(st[0], new(uint64))
+	}
+	atomic.AddUint64(c.(*uint64), 1)
+}
+
+// SiteStats is the number of errors created or wrapped at a call site.
+type SiteStats struct {
+	// Frame is the call site. Print it with %+v for its function, file and
+	// line.
+	Frame Frame
+	// Count is the number of errors created or wrapped at Frame.
+	Count uint64
+}
+
+// Stats returns a snapshot of the number of errors created or wrapped per
+// call site since stats were enabled (see SetStats) or reset, the most
+// frequent first.
+func Stats() []SiteStats {
+	var stats []SiteStats
+	sites.Range(func(pc, c interface{}) bool {
+		stats = append(stats, SiteStats{Frame: Frame(pc.(uintptr)), Count: atomic.LoadUint64(c.(*uint64))})
+		return true
+	})
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Frame < stats[j].Frame
+	})
+	return stats
+}
+
+// ResetStats resets the counters of all call sites.
+func ResetStats() {
+	sites.Range(func(pc, _ interface{}) bool {
+		sites.Delete(pc)
+		return true
+	})
+}
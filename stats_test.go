@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	defer SetStats(false)
+	defer ResetStats()
+	ResetStats()
+	New("not counted")
+
+	SetStats(true)
+	for i := 0; i < 3; i++ {
+		_ = Wrap(io.EOF, "read")
+		if i > 0 {
+			_ = New("decode")
+		}
+	}
+	stats := Stats()
+	if len(stats) != 2 {
+		t.Fatalf("Stats(): got %d sites, want 2", len(stats))
+	}
+	if stats[0].Count != 3 || stats[1].Count != 2 {
+		t.Errorf("Stats(): got counts %d and %d, want 3 and 2", stats[0].Count, stats[1].Count)
+	}
+	for _, s := range stats {
+		if got := fmt.Sprintf("%+v", s.Frame); !strings.HasPrefix(got, "github.com/objenious/errors.TestStats\n") {
+			t.Errorf("Stats(): got frame %q, want TestStats", got)
+		}
+	}
+
+	ResetStats()
+	if stats := Stats(); len(stats) != 0 {
+		t.Errorf("Stats() after ResetStats: got %v, want none", stats)
+	}
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MaxSummaryLength is the maximum length in bytes of the summaries returned
+// by Summary.
+const MaxSummaryLength = 256
+
+// Summary returns a single line describing err, to index in log pipelines
+// while its details, returned by Details, are stored as a blob: the message
+// of err with its line breaks replaced by "; " and its other control
+// characters by spaces, truncated to MaxSummaryLength bytes. ToJSON includes
+// it as "summary" at the top level.
+// If err is nil, Summary returns an empty string.
+func Summary(err error) string {
+	if err == nil {
+		return ""
+	}
+	return summarize(err.Error())
+}
+
+// summarize returns msg on a single line of at most MaxSummaryLength bytes.
+func summarize(msg string) string {
+	lines := strings.FieldsFunc(msg, func(r rune) bool { return r == '\n' || r == '\r' })
+	s := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, strings.Join(lines, "; "))
+	if len(s) > MaxSummaryLength {
+		s = truncateString(s, MaxSummaryLength)
+	}
+	return s
+}
+
+// Details returns the full description of err, its chain formatted with %+v
+// with the stack traces of its levels, to store along with its summary
+// returned by Summary.
+// If err is nil, Details returns an empty string.
+func Details(err error) string {
+	if err == nil {
+		return ""
+	}
+	return fmt.Sprintf("%+v", err)
+}
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{io.EOF, "EOF"},
+		{Wrap(Join(io.EOF, New("boom\r\n\tat line 2")), "sync"), "sync: EOF; boom;  at line 2"},
+		{New(strings.Repeat("é", MaxSummaryLength)), strings.Repeat("é", (MaxSummaryLength-3)/2) + "..."},
+	}
+	for _, tt := range tests {
+		got := Summary(tt.err)
+		if got != tt.want {
+			t.Errorf("Summary(%q) = %q, want %q", tt.err, got, tt.want)
+		}
+		if len(got) > MaxSummaryLength {
+			t.Errorf("Summary(%q) is %d bytes long", tt.err, len(got))
+		}
+	}
+}
+
+func TestDetails(t *testing.T) {
+	if got := Details(nil); got != "" {
+		t.Errorf("Details(nil) = %q", got)
+	}
+	got := Details(Wrap(io.EOF, "read"))
+	if !strings.HasPrefix(got, "EOF\nread\ngithub.com/objenious/errors.TestDetails\n") {
+		t.Errorf("Details() = %q", got)
+	}
+}
+
+func TestToJSONSummary(t *testing.T) {
+	data, err := ToJSON(Wrap(Join(io.EOF, io.ErrClosedPipe), "sync"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m["summary"], "sync: EOF; io: read/write on closed pipe"; got != want {
+		t.Errorf("summary = %v, want %q", got, want)
+	}
+	if _, ok := m["cause"].(map[string]interface{})["summary"]; ok {
+		t.Errorf("ToJSON() has a summary in causes: %s", data)
+	}
+}
@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"context"
+	"time"
+)
+
+// RunWithTimeout calls fn with a context derived from ctx, that is canceled
+// after the timeout d, and returns the error of fn. If fn fails after the
+// timeout expired, the error is wrapped with the message "timed out after
+// <d>" and the stack trace at the point RunWithTimeout was called, is of kind
+// KindTimeout, has a Timeout method returning true, as net.Error, and the
+// field "timeout", d; it is also annotated with the time elapsed, see
+// Duration. The errors of fn after the deadline of ctx, if it is sooner, are
+// returned as-is. fn should return when its context is done: RunWithTimeout
+// does not return before it.
+func RunWithTimeout(ctx context.Context, d time.Duration, fn func(ctx context.Context) error) error {
+	parent := ctx
+	ctx, cancel := context.WithTimeout(parent, d)
+	defer cancel()
+	t := StartTimer()
+	err := fn(ctx)
+	if err == nil || ctx.Err() != context.DeadlineExceeded {
+		return err
+	}
+	// the deadline of ctx is the one of parent if it is sooner.
+	if deadline, ok := parent.Deadline(); ok {
+		if own, _ := ctx.Deadline(); own.Equal(deadline) {
+			return err
+		}
+	}
+	err = &withStack{
+		error:   timeoutError{err},
+		stack:   callers(),
+		created: timestamp(),
+		msg:     "timed out after " + d.String(),
+		wrapped: true,
+	}
+	return WithKind(WithDuration(WithField(err, "timeout", d), t.Elapsed()), KindTimeout)
+}
+
+// timeoutError is the error of a function run by RunWithTimeout that timed
+// out.
+type timeoutError struct {
+	error
+}
+
+// Unwrap returns the error of the function
+func (e timeoutError) Unwrap() error { return e.error }
+
+// Timeout reports that the function timed out
+func (e timeoutError) Timeout() bool { return true }
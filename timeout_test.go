@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunWithTimeout(t *testing.T) {
+	err := RunWithTimeout(context.Background(), 10*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if got, want := err.Error(), "timed out after 10ms: context deadline exceeded"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	var timeout interface{ Timeout() bool }
+	if !As(err, &timeout) || !timeout.Timeout() {
+		t.Error("RunWithTimeout() has no Timeout method")
+	}
+	if KindOf(err) != KindTimeout || !Is(err, context.DeadlineExceeded) {
+		t.Errorf("RunWithTimeout() = %v, want a timeout", err)
+	}
+	if v, _ := Field(err, "timeout"); v != 10*time.Millisecond {
+		t.Errorf("Field(timeout) = %v, want 10ms", v)
+	}
+	if d, ok := Duration(err); !ok || d < 10*time.Millisecond {
+		t.Errorf("Duration() = %v, %t, want at least 10ms", d, ok)
+	}
+	if GetStackTrace(err) == nil {
+		t.Error("RunWithTimeout() has no stack trace")
+	}
+
+	if err := RunWithTimeout(context.Background(), time.Second, func(context.Context) error { return nil }); err != nil {
+		t.Errorf("RunWithTimeout() = %v, want nil", err)
+	}
+	errFailed := New("failed")
+	if err := RunWithTimeout(context.Background(), time.Second, func(context.Context) error { return errFailed }); err != errFailed {
+		t.Errorf("RunWithTimeout() = %v, want the error of fn", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	err = RunWithTimeout(ctx, time.Second, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != context.DeadlineExceeded {
+		t.Errorf("RunWithTimeout(parent deadline) = %v, want context.DeadlineExceeded", err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = RunWithTimeout(ctx, time.Second, func(ctx context.Context) error { return ctx.Err() })
+	if err != context.Canceled {
+		t.Errorf("RunWithTimeout(canceled) = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// timestamps is non-zero when errors record their creation time.
+var timestamps int32
+
+// SetTimestamps enables or disables recording the time errors are created
+// or wrapped by this package, so that errors queued or batched before being
+// logged can be correlated. The time is returned by CreatedAt and included
+// by ToMap. It is disabled by default.
+func SetTimestamps(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&timestamps, v)
+}
+
+// timestamp returns the current time if timestamps are recorded.
+func timestamp() time.Time {
+	if atomic.LoadInt32(&timestamps) == 0 {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// CreatedAt returns the time err was created: the time recorded by the
+// innermost error of its chain that has one. It returns false if no time
+// was recorded, see SetTimestamps.
+func CreatedAt(err error) (time.Time, bool) {
+	var created time.Time
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		switch e := err.(type) {
+		case *withStack:
+			if !e.created.IsZero() {
+				created = e.created
+			}
+		case *RemoteError:
+			if !e.Time.IsZero() {
+				created = e.Time
+			}
+		}
+		causes := causes(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return created, !created.IsZero()
+}
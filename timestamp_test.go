@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestCreatedAt(t *testing.T) {
+	if _, ok := CreatedAt(New("error")); ok {
+		t.Errorf("CreatedAt: got a time while timestamps are disabled")
+	}
+	if _, ok := CreatedAt(nil); ok {
+		t.Errorf("CreatedAt(nil): got a time")
+	}
+
+	SetTimestamps(true)
+	defer SetTimestamps(false)
+	before := time.Now()
+	root := New("error")
+	time.Sleep(time.Millisecond)
+	err := Wrap(WithStack(root), "wrapped")
+	created, ok := CreatedAt(err)
+	if !ok || created != root.(*withStack).created {
+		t.Errorf("CreatedAt: got %v, %t, want the time of the innermost error", created, ok)
+	}
+	if created.Before(before) || !created.Before(err.(*withStack).created) {
+		t.Errorf("CreatedAt: got %v, want between %v and the time of the wrapper", created, before)
+	}
+	if got, ok := CreatedAt(Wrap(io.EOF, "read")); !ok || got.Before(before) {
+		t.Errorf("CreatedAt(Wrap(io.EOF)): got %v, %t", got, ok)
+	}
+
+	data, _ := json.Marshal(ToMap(err))
+	remote, decodeErr := FromJSON(data)
+	if decodeErr != nil {
+		t.Fatal(decodeErr)
+	}
+	if got, ok := CreatedAt(remote); !ok || !got.Equal(created) {
+		t.Errorf("CreatedAt(FromJSON(ToMap(err))): got %v, %t, want %v", got, ok, created)
+	}
+	if _, err := FromJSON([]byte(`{"message":"x","time":"yesterday"}`)); err == nil {
+		t.Errorf("FromJSON with an invalid time: expected an error")
+	}
+}
@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// FieldError describes why the value of a field is invalid.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+// MarshalJSON encodes e as an item of the "errors" extension member of
+// problem+json documents (RFC 9457): the reason is its "detail", the field
+// its "pointer", as a JSON pointer where dots separate nested fields, and
+// "~" and "/" in field names are escaped as "~0" and "~1" (RFC 6901).
+func (e FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Detail  string `json:"detail"`
+		Pointer string `json:"pointer"`
+		Field   string `json:"field"`
+	}{
+		Detail:  e.Reason,
+		Pointer: "#/" + strings.Join(pointerSegments(e.Field), "/"),
+		Field:   e.Field,
+	})
+}
+
+// pointerEscaper escapes the segments of JSON pointers.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// pointerSegments returns the escaped segments of the JSON pointer to field.
+func pointerSegments(field string) []string {
+	segments := strings.Split(field, ".")
+	for i, s := range segments {
+		segments[i] = pointerEscaper.Replace(s)
+	}
+	return segments
+}
+
+// ValidationError is an error listing the invalid fields of an input. It is
+// built with NewValidation.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error returns the list of invalid fields
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("validation failed")
+	for i, f := range e.Fields {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(f.Field)
+		b.WriteString(": ")
+		b.WriteString(f.Reason)
+	}
+	return b.String()
+}
+
+// Field returns the reasons why the field name is invalid, in the order
+// they were added.
+func (e *ValidationError) Field(name string) []string {
+	var reasons []string
+	for _, f := range e.Fields {
+		if f.Field == name {
+			reasons = append(reasons, f.Reason)
+		}
+	}
+	return reasons
+}
+
+// MarshalJSON encodes e as the "errors" extension member of problem+json
+// documents: {"errors": [{"detail": "required", "pointer": "#/name", ...}]}.
+func (e *ValidationError) MarshalJSON() ([]byte, error) {
+	fields := e.Fields
+	if fields == nil {
+		fields = []FieldError{}
+	}
+	return json.Marshal(struct {
+		Errors []FieldError `json:"errors"`
+	}{fields})
+}
+
+// Validation builds a ValidationError:
+//
+//	return errors.NewValidation().
+//		Field("name", "required").
+//		Field("eui", "invalid hex").
+//		Err()
+type Validation struct {
+	fields []FieldError
+}
+
+// NewValidation returns an empty Validation.
+func NewValidation() *Validation {
+	return &Validation{}
+}
+
+// Field adds a reason why the field name is invalid.
+func (v *Validation) Field(name, reason string) *Validation {
+	v.fields = append(v.fields, FieldError{Field: name, Reason: reason})
+	return v
+}
+
+// Merge adds the fields of the ValidationError in the chain of err, if any,
+// with their names prefixed by prefix and a dot, to validate nested inputs.
+// Other errors are added as a reason why the field prefix is invalid. Nil
+// errors are ignored.
+func (v *Validation) Merge(prefix string, err error) *Validation {
+	if err == nil {
+		return v
+	}
+	var ve *ValidationError
+	if !As(err, &ve) {
+		return v.Field(prefix, err.Error())
+	}
+	for _, f := range ve.Fields {
+		if prefix != "" {
+			f.Field = prefix + "." + f.Field
+		}
+		v.fields = append(v.fields, f)
+	}
+	return v
+}
+
+// Err returns a *ValidationError listing the invalid fields, of kind
+// KindInvalid and with the stack trace at the point Err was called, or nil
+// if no field is invalid.
+func (v *Validation) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return WithKind(&withStack{
+		error:   &ValidationError{Fields: append([]FieldError(nil), v.fields...)},
+		stack:   callers(),
+		created: timestamp(),
+		wrapped: true,
+	}, KindInvalid)
+}
@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestValidation(t *testing.T) {
+	if err := NewValidation().Err(); err != nil {
+		t.Fatalf("Err() without fields: got %v, want nil", err)
+	}
+	address := NewValidation().Field("zip", "required").Err()
+	err := NewValidation().
+		Field("name", "required").
+		Field("eui", "invalid hex").
+		Field("eui", "too long").
+		Merge("address", address).
+		Merge("owner", io.EOF).
+		Merge("ignored", nil).
+		Err()
+	want := "validation failed: name: required, eui: invalid hex, eui: too long, address.zip: required, owner: EOF"
+	if got := err.Error(); got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if GetStackTrace(err) == nil {
+		t.Errorf("Err(): got no stack trace")
+	}
+	if got := KindOf(err); got != KindInvalid {
+		t.Errorf("KindOf(): got %q, want %q", got, KindInvalid)
+	}
+
+	var ve *ValidationError
+	if !goerrors.As(Wrap(err, "create device"), &ve) {
+		t.Fatalf("errors.As(*ValidationError) = false")
+	}
+	if got, want := ve.Field("eui"), []string{"invalid hex", "too long"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Field(eui): got %q, want %q", got, want)
+	}
+	if got := ve.Field("unknown"); got != nil {
+		t.Errorf("Field(unknown): got %q, want nil", got)
+	}
+
+	data, _ := json.Marshal(&ValidationError{Fields: []FieldError{{Field: "address.zip", Reason: "required"}}})
+	if got, want := string(data), `{"errors":[{"detail":"required","pointer":"#/address/zip","field":"address.zip"}]}`; got != want {
+		t.Errorf("MarshalJSON: got %s, want %s", got, want)
+	}
+	data, _ = json.Marshal(FieldError{Field: "headers.content/type~1", Reason: "invalid"})
+	if got, want := string(data), `{"detail":"invalid","pointer":"#/headers/content~1type~01","field":"headers.content/type~1"}`; got != want {
+		t.Errorf("MarshalJSON: got %s, want %s", got, want)
+	}
+	data, _ = json.Marshal(&ValidationError{})
+	if got, want := string(data), `{"errors":[]}`; got != want {
+		t.Errorf("MarshalJSON: got %s, want %s", got, want)
+	}
+}
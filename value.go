@@ -0,0 +1,39 @@
+package errors
+
+import "reflect"
+
+// value is the value of the attributes set by WithValue.
+type value struct {
+	key, value interface{}
+}
+
+// WithValue annotates err with v under key, as returned by ValueFrom. Unlike
+// fields, values are not included by ToMap: they can be any data passed up
+// the stack along with the error, such as a partially decoded message. As for
+// context.WithValue, key must be comparable and should be of a type defined by
+// the package using it, to avoid collisions: WithValue panics if key is nil or
+// not comparable.
+// If err is nil, WithValue returns nil.
+func WithValue(err error, key, v interface{}) error {
+	if key == nil {
+		panic("errors: WithValue with a nil key")
+	}
+	if !reflect.TypeOf(key).Comparable() {
+		panic("errors: WithValue with a key that is not comparable")
+	}
+	if err == nil {
+		return nil
+	}
+	return &withAttr{error: err, name: "value", value: value{key: key, value: v}}
+}
+
+// ValueFrom returns the value under key of the outermost error of the chain
+// of err annotated with one by WithValue.
+func ValueFrom(err error, key interface{}) (interface{}, bool) {
+	for _, v := range lookupAttrs(err, "value") {
+		if v := v.(value); v.key == key {
+			return v.value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+type valueKey int
+
+func TestWithValue(t *testing.T) {
+	if err := WithValue(nil, valueKey(0), 1); err != nil {
+		t.Errorf("WithValue(nil) = %v, want nil", err)
+	}
+
+	type partial struct{ Port int }
+	err := WithValue(Wrap(WithValue(io.EOF, valueKey(0), &partial{Port: 1}), "decode"), valueKey(1), "outer")
+	if v, ok := ValueFrom(err, valueKey(0)); !ok || v.(*partial).Port != 1 {
+		t.Errorf("ValueFrom(0) = %v, %t", v, ok)
+	}
+	if v, ok := ValueFrom(err, valueKey(1)); !ok || v != "outer" {
+		t.Errorf("ValueFrom(1) = %v, %t", v, ok)
+	}
+	if v, ok := ValueFrom(err, 0); ok {
+		t.Errorf("ValueFrom(int 0) = %v, want none", v)
+	}
+	if v, ok := ValueFrom(WithValue(err, valueKey(0), nil), valueKey(0)); !ok || v != nil {
+		t.Errorf("ValueFrom() = %v, %t, want the outermost value", v, ok)
+	}
+
+	if err.Error() != "decode: EOF" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	m := ToMap(err)
+	if _, ok := m["value"]; ok {
+		t.Errorf("ToMap() includes values: %v", m)
+	}
+	if _, ok := m["cause"].(map[string]interface{})["value"]; ok {
+		t.Errorf("ToMap() includes values: %v", m)
+	}
+	if n := Dump(err); n.Values[valueKey(1)] != "outer" || n.Children[0].Values[valueKey(0)] == nil {
+		t.Errorf("Dump().Values = %v", n.Values)
+	}
+	if key, v := err.(Annotation).Annotation(); key != "1" || v != "outer" {
+		t.Errorf("Annotation() = %q, %v", key, v)
+	}
+}
+
+func TestWithValueKey(t *testing.T) {
+	for _, key := range []interface{}{nil, []string{"key"}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("WithValue(%v) did not panic", key)
+				}
+			}()
+			_ = WithValue(io.EOF, key, 1)
+		}()
+	}
+}
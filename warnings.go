@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrorsAndWarnings collects the fatal errors and the warnings of a run, such
+// as the decoding of a payload where some fields are recoverable:
+//
+//	var ew errors.ErrorsAndWarnings
+//	for _, f := range fields {
+//		if err := decode(f); err != nil {
+//			if f.Optional {
+//				ew.Warn(err)
+//			} else {
+//				ew.Fail(err)
+//			}
+//		}
+//	}
+//	return ew.Err()
+//
+// The zero ErrorsAndWarnings is empty and ready to use.
+type ErrorsAndWarnings struct {
+	errs     []error
+	warnings []error
+}
+
+// Fail adds the fatal error err. Nil errors are ignored.
+func (ew *ErrorsAndWarnings) Fail(err error) {
+	if err != nil {
+		ew.errs = append(ew.errs, err)
+	}
+}
+
+// Warn adds the warning err. Nil errors are ignored.
+func (ew *ErrorsAndWarnings) Warn(err error) {
+	if err != nil {
+		ew.warnings = append(ew.warnings, err)
+	}
+}
+
+// Errors returns the fatal errors added so far.
+func (ew *ErrorsAndWarnings) Errors() []error {
+	return ew.errs
+}
+
+// Warnings returns the warnings added so far.
+func (ew *ErrorsAndWarnings) Warnings() []error {
+	return ew.warnings
+}
+
+// Err returns nil if no fatal error was added, even if there are warnings.
+// Otherwise, it returns an error wrapping the fatal errors, that also carries
+// the warnings, as returned by Warnings.
+func (ew *ErrorsAndWarnings) Err() error {
+	if len(ew.errs) == 0 {
+		return nil
+	}
+	return &withWarnings{
+		errs:     append([]error(nil), ew.errs...),
+		warnings: append([]error(nil), ew.warnings...),
+	}
+}
+
+// Warnings returns the warnings carried by the outermost error of the chain
+// of err returned by ErrorsAndWarnings.Err.
+func Warnings(err error) []error {
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		if w, ok := err.(*withWarnings); ok {
+			return w.warnings
+		}
+		causes := causes(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return nil
+}
+
+// withWarnings wraps fatal errors along with warnings. Only the fatal errors
+// are unwrapped.
+type withWarnings struct {
+	errs     []error
+	warnings []error
+}
+
+func (w *withWarnings) Error() string {
+	msgs := make([]string, 0, len(w.errs)+len(w.warnings))
+	for _, err := range w.errs {
+		msgs = append(msgs, err.Error())
+	}
+	for _, err := range w.warnings {
+		msgs = append(msgs, "warning: "+err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the fatal errors
+func (w *withWarnings) Unwrap() []error {
+	return w.errs
+}
+
+// Format formats the fatal errors then the warnings, with their stack traces
+// for %+v
+func (w *withWarnings) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatIndented(s, w) {
+				return
+			}
+			for i, err := range w.errs {
+				if i > 0 {
+					_, _ = io.WriteString(s, "\n")
+				}
+				formatExtended(s, err)
+			}
+			for _, err := range w.warnings {
+				_, _ = io.WriteString(s, "\nwarning: ")
+				formatExtended(s, err)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, w.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", w.Error())
+	}
+}
@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestErrorsAndWarnings(t *testing.T) {
+	var ew ErrorsAndWarnings
+	ew.Warn(nil)
+	ew.Warn(New("unknown field"))
+	if err := ew.Err(); err != nil {
+		t.Fatalf("Err() with warnings only: got %v, want nil", err)
+	}
+	ew.Fail(nil)
+	ew.Fail(io.ErrUnexpectedEOF)
+	if len(ew.Errors()) != 1 || len(ew.Warnings()) != 1 {
+		t.Fatalf("got %d errors and %d warnings, want 1 and 1", len(ew.Errors()), len(ew.Warnings()))
+	}
+
+	err := Wrap(ew.Err(), "decode")
+	if got, want := err.Error(), "decode: unexpected EOF\nwarning: unknown field"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got := Cause(err); got != io.ErrUnexpectedEOF {
+		t.Errorf("Cause(): got %v, want io.ErrUnexpectedEOF", got)
+	}
+	if w := Warnings(err); len(w) != 1 || w[0].Error() != "unknown field" {
+		t.Errorf("Warnings(): got %v, want [unknown field]", w)
+	}
+	if w := Warnings(io.EOF); w != nil {
+		t.Errorf("Warnings(io.EOF): got %v, want nil", w)
+	}
+	if got := fmt.Sprintf("%+v", ew.Err()); !strings.HasPrefix(got, "unexpected EOF\nwarning: unknown field\ngithub.com/objenious/errors.TestErrorsAndWarnings\n") {
+		t.Errorf("%%+v: got %q", got)
+	}
+	m := ToMap(ew.Err())
+	if w, ok := m["warnings"].([]interface{}); !ok || len(w) != 1 {
+		t.Errorf("ToMap: got %v, want a warnings list", m)
+	}
+}
@@ -0,0 +1,93 @@
+package errors
+
+import "fmt"
+
+// Wrapper is implemented by the errors of this package that record a
+// message and a stack trace: those returned by New, Errorf, WithStack, Wrap
+// and the like. errors.As can find them in a chain, to inspect a level
+// without depending on the types of this package:
+//
+//	var w errors.Wrapper
+//	if errors.As(err, &w) {
+//		fmt.Println(w.Message(), w.StackTrace())
+//	}
+type Wrapper interface {
+	error
+	// Unwrap returns the wrapped error, or nil for errors created by New
+	// or Errorf without %w.
+	Unwrap() error
+	// Message returns the message added by the error: the message of an
+	// error created by New or Errorf, or the message of a wrapper without
+	// the message of the wrapped error, empty for WithStack.
+	Message() string
+	// StackTrace returns the stack trace recorded by the error.
+	StackTrace() StackTrace
+}
+
+// StackTracer is implemented by the errors carrying a stack trace, those of
+// this package as well as any other type recording one.
+type StackTracer interface {
+	StackTrace() StackTrace
+}
+
+// StackError is implemented by the errors carrying a stack trace.
+type StackError interface {
+	error
+	StackTracer
+}
+
+// IsStackTracer reports whether err or one of its causes carries a stack
+// trace.
+func IsStackTracer(err error) bool {
+	_, ok := AsStackTracer(err)
+	return ok
+}
+
+// AsStackTracer returns the first error of the chain of err carrying a stack
+// trace, following the first cause of each level.
+func AsStackTracer(err error) (StackTracer, bool) {
+	chain := err
+	for depth := 0; err != nil && inDepth(chain, depth); depth++ {
+		if st, ok := err.(StackTracer); ok {
+			return st, true
+		}
+		causes := causes(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+	return nil, false
+}
+
+// Annotation is implemented by the errors annotating another error with a
+// value, without changing its message, such as those returned by WithField,
+// WithKind or WithDuration.
+type Annotation interface {
+	error
+	// Unwrap returns the annotated error.
+	Unwrap() error
+	// Annotation returns the key and value of the annotation: the key of
+	// the field for WithField, the key printed with %v for WithValue, or
+	// else the key of the value in ToMap.
+	Annotation() (key string, value interface{})
+}
+
+// Message returns the message added by w
+func (w *withStack) Message() string {
+	if !w.wrapped {
+		return w.error.Error()
+	}
+	return w.message()
+}
+
+// Annotation returns the key and value of w
+func (w *withAttr) Annotation() (string, interface{}) {
+	switch v := w.value.(type) {
+	case field:
+		return v.key, v.value
+	case value:
+		return fmt.Sprint(v.key), v.value
+	}
+	return w.name, w.value
+}
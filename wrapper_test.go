@@ -0,0 +1,81 @@
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWrapper(t *testing.T) {
+	tests := []struct {
+		err     error
+		message string
+		unwrap  error
+	}{
+		{New("boom"), "boom", nil},
+		{Errorf("boom %d", 1), "boom 1", nil},
+		{WithStack(io.EOF), "", io.EOF},
+		{Wrap(io.EOF, "read"), "read", io.EOF},
+		{Wrapf(io.EOF, "read %d", 1), "read 1", io.EOF},
+		{Wraplazy(io.EOF, "read %d", 1), "read 1", io.EOF},
+	}
+	for i, tt := range tests {
+		var w Wrapper
+		if !goerrors.As(WithKind(tt.err, KindInternal), &w) {
+			t.Fatalf("test %d: errors.As(Wrapper) = false", i+1)
+		}
+		if w.Message() != tt.message || w.Unwrap() != tt.unwrap || len(w.StackTrace()) == 0 {
+			t.Errorf("test %d: got message %q, unwrap %v, stack %v", i+1, w.Message(), w.Unwrap(), w.StackTrace())
+		}
+		var se StackError
+		if !goerrors.As(tt.err, &se) || se != w {
+			t.Errorf("test %d: errors.As(StackError) = false", i+1)
+		}
+	}
+	var w Wrapper
+	if goerrors.As(io.EOF, &w) {
+		t.Errorf("errors.As(io.EOF, Wrapper) = true")
+	}
+
+	var a Annotation
+	err := Wrap(WithDuration(WithField(io.EOF, "device", "42"), time.Second), "read")
+	if !goerrors.As(err, &a) {
+		t.Fatalf("errors.As(Annotation) = false")
+	}
+	if key, value := a.Annotation(); key != "duration" || value != time.Second {
+		t.Errorf("Annotation(): got %q, %v", key, value)
+	}
+	if !goerrors.As(a.Unwrap(), &a) {
+		t.Fatalf("errors.As(Annotation) = false")
+	}
+	if key, value := a.Annotation(); key != "device" || value != "42" || a.Unwrap() != io.EOF {
+		t.Errorf("Annotation(): got %q, %v", key, value)
+	}
+}
+
+func TestAsStackTracer(t *testing.T) {
+	inner := New("boom")
+	tests := []struct {
+		err  error
+		want StackTracer
+	}{
+		{nil, nil},
+		{io.EOF, nil},
+		{inner, inner.(StackTracer)},
+		{WithKind(inner, KindInternal), inner.(StackTracer)},
+		{fmt.Errorf("outer: %w", inner), inner.(StackTracer)},
+		{Join(io.EOF, inner), nil},
+		{Join(inner, io.EOF), inner.(StackTracer)},
+	}
+	for i, tt := range tests {
+		got, ok := AsStackTracer(tt.err)
+		if got != tt.want || ok != (tt.want != nil) {
+			t.Errorf("test %d: AsStackTracer(%v) = %v, %t", i+1, tt.err, got, ok)
+		}
+		if IsStackTracer(tt.err) != ok {
+			t.Errorf("test %d: IsStackTracer(%v) = %t", i+1, tt.err, !ok)
+		}
+	}
+}
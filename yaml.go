@@ -0,0 +1,155 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ToYAML returns the representation of err built by ToMap as a YAML
+// document, readable in operator-facing output without escaping:
+//
+//	message: 'read config: open config.json: no such file or directory'
+//	stack:
+//	  - main.readConfig /src/main.go:12
+//	cause:
+//	  message: 'open config.json: no such file or directory'
+//
+// The message comes first and the causes last. If err is nil, ToYAML
+// returns an empty string.
+func ToYAML(err error) string {
+	if err == nil {
+		return ""
+	}
+	var b strings.Builder
+	writeYAMLMap(&b, boundSize(ToMap(err)), "", false)
+	return b.String()
+}
+
+// yamlKeyOrder returns the rank of a key of ToMap, so that messages come
+// first and causes last, others being sorted alphabetically.
+func yamlKeyOrder(key string) int {
+	switch key {
+	case "message":
+		return 0
+	case "cause", "causes":
+		return 2
+	}
+	return 1
+}
+
+// writeYAMLMap writes m at indent. The first key is not indented when m is a
+// list item, as it follows the item marker.
+func writeYAMLMap(b *strings.Builder, m map[string]interface{}, indent string, item bool) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if oi, oj := yamlKeyOrder(keys[i]), yamlKeyOrder(keys[j]); oi != oj {
+			return oi < oj
+		}
+		return keys[i] < keys[j]
+	})
+	for i, k := range keys {
+		if i > 0 || !item {
+			b.WriteString(indent)
+		}
+		b.WriteString(yamlScalar(k, indent))
+		b.WriteString(":")
+		writeYAMLValue(b, m[k], indent+"  ")
+	}
+}
+
+// writeYAMLValue writes v after a key, its nested lines at indent.
+func writeYAMLValue(b *strings.Builder, v interface{}, indent string) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			break
+		}
+		b.WriteString("\n")
+		writeYAMLMap(b, v, indent, false)
+		return
+	case []string:
+		list := make([]interface{}, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		writeYAMLValue(b, list, indent)
+		return
+	case []interface{}:
+		if len(v) == 0 {
+			break
+		}
+		b.WriteString("\n")
+		for _, item := range v {
+			b.WriteString(indent + "- ")
+			if m, ok := item.(map[string]interface{}); ok && len(m) > 0 {
+				writeYAMLMap(b, m, indent+"  ", true)
+				continue
+			}
+			b.WriteString(yamlValue(item, indent+"  ") + "\n")
+		}
+		return
+	}
+	b.WriteString(" " + yamlValue(v, indent) + "\n")
+}
+
+// yamlValue returns v as a scalar or an empty collection, its continuation
+// lines at indent.
+func yamlValue(v interface{}, indent string) string {
+	switch v := v.(type) {
+	case string:
+		return yamlScalar(v, indent)
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "{}"
+	case []interface{}, []string:
+		return "[]"
+	}
+	return yamlScalar(fmt.Sprint(v), indent)
+}
+
+// yamlScalar returns s as a plain, single-quoted, literal block or
+// double-quoted YAML scalar, whichever is the most readable.
+func yamlScalar(s, indent string) string {
+	printable := true
+	for _, r := range s {
+		if r != '\n' && !unicode.IsPrint(r) {
+			printable = false
+			break
+		}
+	}
+	switch {
+	case !printable || strings.HasPrefix(s, " ") || strings.HasSuffix(s, "\n"):
+		return strconv.Quote(s)
+	case strings.Contains(s, "\n"):
+		return "|-\n" + indent + strings.Replace(s, "\n", "\n"+indent, -1)
+	case yamlPlain(s):
+		return s
+	}
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// yamlPlain reports whether s can be written as a plain YAML scalar, without
+// being mistaken for another type or YAML syntax.
+func yamlPlain(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return false
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return false
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~", "y", "n":
+		return false
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestToYAML(t *testing.T) {
+	if got := ToYAML(nil); got != "" {
+		t.Errorf("ToYAML(nil): got %q, want \"\"", got)
+	}
+
+	tests := []struct {
+		err  error
+		want string
+	}{{
+		io.EOF,
+		"message: EOF\n",
+	}, {
+		fmt.Errorf("decode: %w", io.ErrUnexpectedEOF),
+		"message: 'decode: unexpected EOF'\n" +
+			"cause:\n" +
+			"  message: unexpected EOF\n",
+	}, {
+		fmt.Errorf("it's %w", Sentinel("true")),
+		"message: it's true\n" +
+			"cause:\n" +
+			"  message: 'true'\n",
+	}, {
+		fmt.Errorf("%w\n%w", Sentinel("first"), Sentinel("- second")),
+		"message: |-\n" +
+			"  first\n" +
+			"  - second\n" +
+			"causes:\n" +
+			"  - message: first\n" +
+			"  - message: '- second'\n",
+	}, {
+		Sentinel("bell\a"),
+		"message: \"bell\\a\"\n",
+	}}
+	for i, tt := range tests {
+		if got := ToYAML(tt.err); got != tt.want {
+			t.Errorf("test %d: ToYAML(%q):\n got: %q\nwant: %q", i+1, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestToYAMLStack(t *testing.T) {
+	got := ToYAML(Wrap(io.EOF, "read"))
+	want := "^message: 'read: EOF'\n" +
+		"stack:\n" +
+		"  - github.com/objenious/errors.TestToYAMLStack .+/github.com/objenious/errors/yaml_test.go:51\n" +
+		"(  - .+\n)*" +
+		"cause:\n" +
+		"  message: EOF\n$"
+	if !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("ToYAML(Wrap(io.EOF, \"read\")):\n got: %q\nwant: %q", got, want)
+	}
+}